@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// BisectSuspectsRequest describes the commits a bisect has not ruled out yet.
+type BisectSuspectsRequest struct {
+	// Symptom is the user's description of the bug, e.g. "login returns 500".
+	Symptom  string
+	Suspects []git.LogEntry
+	Language string
+}
+
+// SummarizeSuspects briefly describes what the remaining suspect commits
+// change and which of them look related to the symptom. Only commit
+// messages are sent.
+func (c *Client) SummarizeSuspects(ctx context.Context, req BisectSuspectsRequest) (string, error) {
+	if len(req.Suspects) == 0 {
+		return "", errors.New("no suspect commits")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("A developer is running git bisect to find the commit that introduced a bug.\n")
+	builder.WriteString(fmt.Sprintf("Symptom: %s\n", req.Symptom))
+	builder.WriteString("The first bad commit is one of the commits below (newest first). In at most three short sentences, say what this range changes and which commits, by short hash, look most likely to cause the symptom and why. If none stands out, say so.\n")
+	builder.WriteString(fmt.Sprintf("Respond in %s as plain text without Markdown.\n", responseLanguage(req.Language).Label()))
+	writeCommitMessages(&builder, req.Suspects)
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.2)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("AI returned an empty summary")
+	}
+	return text, nil
+}
+
+// CulpritRequest describes the first bad commit found by a bisect.
+type CulpritRequest struct {
+	Symptom      string
+	Commit       git.LogEntry
+	Diff         string
+	SkippedFiles []string
+	RepoInfo     git.RepoInfo
+	Language     string
+}
+
+// ExplainCulprit explains how the first bad commit's diff can cause the
+// symptom and where a fix would likely go.
+func (c *Client) ExplainCulprit(ctx context.Context, req CulpritRequest) (string, error) {
+	if strings.TrimSpace(req.Diff) == "" && len(req.SkippedFiles) == 0 {
+		return "", errors.New("commit has no changes to explain")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("You are an experienced software engineer helping debug a regression.\n")
+	builder.WriteString(fmt.Sprintf("git bisect found that the commit below is the first one showing this symptom: %s\n", req.Symptom))
+	builder.WriteString("Explain, with sections Cause, Evidence and Fix:\n")
+	builder.WriteString("- Cause: which change in the diff most plausibly causes the symptom and how.\n")
+	builder.WriteString("- Evidence: the specific files and lines, quoting the relevant code.\n")
+	builder.WriteString("- Fix: where and how to fix it, or what to check first if the diff alone does not explain the symptom (for example a flaky test or an environment change).\n")
+	builder.WriteString("Be honest when the link between the diff and the symptom is uncertain.\n")
+	builder.WriteString(fmt.Sprintf("Respond in %s as concise Markdown.\n", responseLanguage(req.Language).Label()))
+	builder.WriteString(fmt.Sprintf("Repository path: %s\nBranch: %s\nRemote: %s\n", req.RepoInfo.Path, req.RepoInfo.Branch, req.RepoInfo.Remote))
+	builder.WriteString("Commit:\n")
+	writeCommitMessages(&builder, []git.LogEntry{req.Commit})
+	writeSkippedFiles(&builder, req.SkippedFiles)
+	builder.WriteString("Git diff:\n---\n")
+	builder.WriteString(trimDiff(req.Diff))
+	builder.WriteString("\n---\n")
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.3)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("AI returned an empty explanation")
+	}
+	return text, nil
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// BranchRequest describes work that is about to start on a new branch.
+type BranchRequest struct {
+	// Description is the user's free-form description, e.g. "fix the login timeout".
+	Description string
+	RepoInfo    git.RepoInfo
+}
+
+// BranchHeader turns a description of upcoming work into a Conventional
+// Commits header ("fix(auth): raise login timeout") from which a branch
+// name can be derived.
+func (c *Client) BranchHeader(ctx context.Context, req BranchRequest) (string, error) {
+	desc := strings.TrimSpace(req.Description)
+	if desc == "" {
+		return "", errors.New("empty branch description")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("A developer is starting new work and described it as follows:\n")
+	builder.WriteString("---\n")
+	builder.WriteString(trimText(desc, 500))
+	builder.WriteString("\n---\n")
+	builder.WriteString("Write the Conventional Commits header that the finished work would most likely have.\n")
+	builder.WriteString("- Format: <type>(<optional scope>): <description>, with type one of feat, fix, refactor, perf, docs, test, build, ci, chore.\n")
+	builder.WriteString("- The description is English, lowercase, imperative, at most 6 words, and keeps key identifiers from the request.\n")
+	builder.WriteString(fmt.Sprintf("Repository: %s\n", req.RepoInfo.Path))
+	builder.WriteString("Respond ONLY as a single JSON object of the form {\"header\":\"<type>(<scope>): <description>\"}, with no code fences.\n")
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.2)
+	if err != nil {
+		return "", err
+	}
+
+	clean := extractJSONBlock(text)
+	if strings.TrimSpace(clean) == "" {
+		return "", fmt.Errorf("failed to find JSON object in branch response: %q", text)
+	}
+	var parsed struct {
+		Header string `json:"header"`
+	}
+	if err := json.Unmarshal([]byte(clean), &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse branch JSON: %w; raw=%q", err, clean)
+	}
+	header := strings.TrimSpace(strings.SplitN(parsed.Header, "\n", 2)[0])
+	if header == "" {
+		return "", errors.New("AI returned an empty branch header")
+	}
+	return header, nil
+}
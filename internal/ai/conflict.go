@@ -0,0 +1,161 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// maxConflictContextCharacters caps each side of a conflict in the prompt.
+const maxConflictContextCharacters = 6000
+
+// ConflictRequest describes one conflicted hunk of a file.
+type ConflictRequest struct {
+	Path string
+	// Operation is the git operation that stopped, e.g. "rebase" or "merge".
+	Operation string
+	// Ours and Theirs are the two sides; Base is the common ancestor when
+	// the file has diff3 markers (HasBase).
+	Ours    string
+	Base    string
+	Theirs  string
+	HasBase bool
+	// OursLabel and TheirsLabel are the labels git wrote on the markers.
+	OursLabel   string
+	TheirsLabel string
+	// Incoming is the commit being applied (rebase, cherry-pick) or merged.
+	Incoming *git.LogEntry
+	// Before and After are the unconflicted lines around the hunk.
+	Before   string
+	After    string
+	Language string
+}
+
+// ConflictResolution is the model's proposal for one hunk.
+type ConflictResolution struct {
+	// Resolution replaces the whole hunk, markers included.
+	Resolution  string `json:"resolution"`
+	Explanation string `json:"explanation"`
+	// Confidence is "high", "medium" or "low".
+	Confidence string `json:"confidence"`
+}
+
+// ResolveConflict asks the model to merge both sides of a conflicted hunk.
+func (c *Client) ResolveConflict(ctx context.Context, req ConflictRequest) (ConflictResolution, error) {
+	var resp ConflictResolution
+
+	if strings.TrimSpace(req.Ours) == "" && strings.TrimSpace(req.Theirs) == "" {
+		return resp, errors.New("conflict has no content on either side")
+	}
+
+	text, err := c.generate(ctx, buildConflictPrompt(req), c.maxTokens, 0.1)
+	if err != nil {
+		return resp, err
+	}
+
+	clean := extractJSONBlock(text)
+	if strings.TrimSpace(clean) == "" {
+		return resp, fmt.Errorf("failed to find JSON object in conflict response: %q", text)
+	}
+	if err := json.Unmarshal([]byte(clean), &resp); err != nil {
+		return resp, fmt.Errorf("failed to parse conflict resolution JSON: %w; raw=%q", err, clean)
+	}
+
+	// Hunks are whole lines; keep the file's line structure intact.
+	if resp.Resolution != "" && !strings.HasSuffix(resp.Resolution, "\n") {
+		resp.Resolution += "\n"
+	}
+	resp.Explanation = strings.TrimSpace(resp.Explanation)
+	resp.Confidence = strings.ToLower(strings.TrimSpace(resp.Confidence))
+	return resp, nil
+}
+
+func buildConflictPrompt(req ConflictRequest) string {
+	oursRole, theirsRole := conflictRoles(req.Operation)
+
+	var builder strings.Builder
+	builder.WriteString("You are an experienced software engineer resolving a git conflict.\n")
+	builder.WriteString("Produce the merged code for the conflicted region so that the intent of BOTH sides is preserved.\n")
+	builder.WriteString("Rules:\n")
+	builder.WriteString("- Compare each side with the base to see what each side changed, then apply both changes.\n")
+	builder.WriteString("- If the changes truly contradict each other, prefer the side whose intent is clearer and say so in the explanation.\n")
+	builder.WriteString("- Output only the code that replaces the region: no conflict markers, no surrounding context lines, no commentary in the code.\n")
+	builder.WriteString("- Keep the file's indentation style and line endings. Do not reformat unrelated lines.\n")
+	builder.WriteString("- Set confidence to low when you had to guess, e.g. the sides change the same logic in incompatible ways.\n")
+	builder.WriteString(fmt.Sprintf("Write the explanation in %s, in at most three sentences.\n", responseLanguage(req.Language).Label()))
+	builder.WriteString(fmt.Sprintf("File: %s\n", req.Path))
+	if req.Operation != "" {
+		builder.WriteString(fmt.Sprintf("Git operation: %s\n", req.Operation))
+	}
+	if req.Incoming != nil {
+		builder.WriteString(fmt.Sprintf("Incoming commit: %s %s\n", req.Incoming.ShortHash(), req.Incoming.Subject))
+		if body := strings.TrimSpace(req.Incoming.Body); body != "" {
+			builder.WriteString(trimText(body, 1000))
+			builder.WriteString("\n")
+		}
+	}
+	if req.Before != "" {
+		builder.WriteString("Lines before the conflict (unchanged, do not repeat):\n---\n")
+		builder.WriteString(req.Before)
+		builder.WriteString("---\n")
+	}
+	builder.WriteString(fmt.Sprintf("OURS (%s, %s):\n---\n", oursRole, req.OursLabel))
+	builder.WriteString(trimCode(req.Ours))
+	builder.WriteString("\n---\n")
+	if req.HasBase {
+		builder.WriteString("BASE (common ancestor):\n---\n")
+		builder.WriteString(trimCode(req.Base))
+		builder.WriteString("\n---\n")
+	} else {
+		builder.WriteString("The common ancestor is not available; infer each side's intent from the code.\n")
+	}
+	builder.WriteString(fmt.Sprintf("THEIRS (%s, %s):\n---\n", theirsRole, req.TheirsLabel))
+	builder.WriteString(trimCode(req.Theirs))
+	builder.WriteString("\n---\n")
+	if req.After != "" {
+		builder.WriteString("Lines after the conflict (unchanged, do not repeat):\n---\n")
+		builder.WriteString(req.After)
+		builder.WriteString("---\n")
+	}
+	builder.WriteString("JSON response requirements (very important):\n")
+	builder.WriteString("- Respond ONLY as a single valid JSON object, with no extra text, no explanation, and no code fences.\n")
+	builder.WriteString("- The JSON must have exactly this shape and key names:\n")
+	builder.WriteString(`{"resolution":"<merged code>","explanation":"<why>","confidence":"high|medium|low"}` + "\n")
+	builder.WriteString("- Escape newlines, quotes and backslashes in the resolution so the JSON stays valid.\n")
+	builder.WriteString("- Do NOT wrap the JSON in ``` or ```json. Do NOT add any commentary before or after the JSON.\n")
+	return builder.String()
+}
+
+// conflictRoles explains what "ours" and "theirs" mean for op; during a
+// rebase they are the reverse of what most people expect.
+func conflictRoles(op string) (ours, theirs string) {
+	switch op {
+	case string(git.OperationRebase):
+		return "the upstream branch being rebased onto", "the local commit being replayed"
+	case string(git.OperationCherryPick):
+		return "the current branch", "the commit being cherry-picked"
+	case string(git.OperationRevert):
+		return "the current branch", "the inverse of the commit being reverted"
+	default:
+		return "the current branch", "the branch being merged in"
+	}
+}
+
+// trimCode caps code without trimming it, since leading whitespace matters.
+func trimCode(code string) string {
+	code = strings.TrimRight(code, "\n")
+	if len(code) <= maxConflictContextCharacters {
+		return code
+	}
+	// Cut at a rune boundary so the prompt stays valid UTF-8.
+	limit := maxConflictContextCharacters
+	for limit > 0 && !utf8.RuneStart(code[limit]) {
+		limit--
+	}
+	return code[:limit] + "\n... (truncated)"
+}
@@ -0,0 +1,22 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrimCode(t *testing.T) {
+	if got := trimCode("\tindented\n\n"); got != "\tindented" {
+		t.Errorf("trimCode kept trailing newlines or dropped indentation: %q", got)
+	}
+	// A three-byte rune straddling the limit must not be split.
+	long := strings.Repeat("a", maxConflictContextCharacters-1) + "日本"
+	got := trimCode(long)
+	if !utf8.ValidString(got) {
+		t.Fatalf("trimCode produced invalid UTF-8")
+	}
+	if want := strings.Repeat("a", maxConflictContextCharacters-1) + "\n... (truncated)"; got != want {
+		t.Errorf("trimCode cut at %d bytes, want %d", len(got)-len("\n... (truncated)"), maxConflictContextCharacters-1)
+	}
+}
@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// maxHistoryPatchCharacters caps each commit's patch in a file history so
+// that older commits still fit in the prompt.
+const maxHistoryPatchCharacters = 2000
+
+// ExplainMode selects what an ExplainRequest is about.
+type ExplainMode string
+
+const (
+	// ExplainCommit explains a single commit.
+	ExplainCommit ExplainMode = "commit"
+	// ExplainLine explains why a line exists, from the commit that introduced it.
+	ExplainLine ExplainMode = "line"
+	// ExplainHistory summarizes how a file evolved.
+	ExplainHistory ExplainMode = "history"
+)
+
+// ExplainRequest holds what the model needs to explain code or history to
+// someone new to the repository.
+type ExplainRequest struct {
+	Mode ExplainMode
+	// Commit and Diff describe the commit being explained (commit and line
+	// modes). For a line, Diff is limited to the line's file.
+	Commit       git.LogEntry
+	Diff         string
+	SkippedFiles []string
+	// Path, Line and LineText locate the line being explained; Excerpt is
+	// the surrounding code with line numbers, as it is today.
+	Path     string
+	Line     int
+	LineText string
+	Excerpt  string
+	// History lists the commits that changed Path, newest first.
+	History  []git.FileChange
+	RepoInfo git.RepoInfo
+	Language string
+}
+
+// Explain asks the model for a plain-language explanation. Unlike a review
+// it describes what the code does and why, without critiquing it.
+func (c *Client) Explain(ctx context.Context, req ExplainRequest) (string, error) {
+	var builder strings.Builder
+	builder.WriteString("You are a senior engineer on this project helping a new team member understand the code base.\n")
+	builder.WriteString("Explain, do not review: describe what the code does and why it was written this way, based on the evidence below. Do not suggest improvements unless something is clearly broken.\n")
+	builder.WriteString("When the evidence does not show the reason for something, say so instead of guessing.\n")
+	builder.WriteString(fmt.Sprintf("Respond in %s as concise Markdown.\n", responseLanguage(req.Language).Label()))
+	builder.WriteString(fmt.Sprintf("Repository path: %s\nBranch: %s\nRemote: %s\n", req.RepoInfo.Path, req.RepoInfo.Branch, req.RepoInfo.Remote))
+
+	switch req.Mode {
+	case ExplainCommit:
+		if strings.TrimSpace(req.Diff) == "" && len(req.SkippedFiles) == 0 {
+			return "", errors.New("commit has no changes to explain")
+		}
+		builder.WriteString("Explain the commit below: the problem it addresses, what it changes and how the pieces fit together, and anything a reader should watch out for. Start with a one-sentence summary.\n")
+		writeExplainedCommit(&builder, req.Commit)
+		writeSkippedFiles(&builder, req.SkippedFiles)
+		builder.WriteString("Diff:\n---\n")
+		builder.WriteString(trimDiff(req.Diff))
+		builder.WriteString("\n---\n")
+	case ExplainLine:
+		builder.WriteString(fmt.Sprintf("Explain why line %d of %s exists: what it does in its surroundings, and why it was introduced, using the commit that last changed it.\n", req.Line, req.Path))
+		builder.WriteString(fmt.Sprintf("The line:\n    %s\n", req.LineText))
+		if req.Excerpt != "" {
+			builder.WriteString("Surrounding code today:\n---\n")
+			builder.WriteString(req.Excerpt)
+			builder.WriteString("\n---\n")
+		}
+		builder.WriteString("Commit that last changed the line:\n")
+		writeExplainedCommit(&builder, req.Commit)
+		if strings.TrimSpace(req.Diff) != "" {
+			builder.WriteString("That commit's changes to the file:\n---\n")
+			builder.WriteString(trimDiff(req.Diff))
+			builder.WriteString("\n---\n")
+		}
+	case ExplainHistory:
+		if len(req.History) == 0 {
+			return "", errors.New("file has no history to explain")
+		}
+		builder.WriteString(fmt.Sprintf("Summarize how %s evolved: when and why it was created, the main phases of change and the reasons behind them, and what it is responsible for now. Refer to commits by their short hash. Group small related commits instead of listing every one.\n", req.Path))
+		builder.WriteString("Commits that changed the file, newest first, each with its patch to the file:\n")
+		var history strings.Builder
+		for _, change := range req.History {
+			history.WriteString(fmt.Sprintf("### %s %s (%s, %s)\n", change.Commit.ShortHash(), change.Commit.Subject, change.Commit.Author, change.Commit.Date.Format("2006-01-02")))
+			if change.Commit.Body != "" {
+				history.WriteString(change.Commit.Body + "\n")
+			}
+			history.WriteString(trimText(change.Patch, maxHistoryPatchCharacters) + "\n")
+		}
+		builder.WriteString("---\n")
+		builder.WriteString(trimDiff(history.String()))
+		builder.WriteString("\n---\n")
+	default:
+		return "", fmt.Errorf("unknown explain mode %q", req.Mode)
+	}
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.3)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("AI returned an empty explanation")
+	}
+	return text, nil
+}
+
+func writeExplainedCommit(builder *strings.Builder, commit git.LogEntry) {
+	builder.WriteString(fmt.Sprintf("Commit %s by %s on %s: %s\n", commit.ShortHash(), commit.Author, commit.Date.Format("2006-01-02"), commit.Subject))
+	if commit.Body != "" {
+		for _, line := range strings.Split(commit.Body, "\n") {
+			builder.WriteString("    " + line + "\n")
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how serious a review finding is.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRanks orders severities from least to most serious.
+var severityRanks = map[Severity]int{
+	SeverityInfo:     0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// Rank returns the ordering of s; unknown severities rank as info.
+func (s Severity) Rank() int {
+	return severityRanks[s]
+}
+
+// ParseSeverity normalizes a severity name, reporting whether it is known.
+func ParseSeverity(s string) (Severity, bool) {
+	sev := Severity(strings.ToLower(strings.TrimSpace(s)))
+	_, ok := severityRanks[sev]
+	return sev, ok
+}
+
+// Finding is a single structured review comment tied to a location.
+type Finding struct {
+	File       string   `json:"file"`
+	StartLine  int      `json:"start_line,omitempty"`
+	EndLine    int      `json:"end_line,omitempty"`
+	Severity   Severity `json:"severity"`
+	Category   string   `json:"category"`
+	Message    string   `json:"message"`
+	Suggestion string   `json:"suggestion,omitempty"`
+}
+
+// Location formats the finding position as file:line or file:start-end.
+func (f Finding) Location() string {
+	switch {
+	case f.File == "":
+		return "(general)"
+	case f.StartLine <= 0:
+		return f.File
+	case f.EndLine > f.StartLine:
+		return fmt.Sprintf("%s:%d-%d", f.File, f.StartLine, f.EndLine)
+	default:
+		return fmt.Sprintf("%s:%d", f.File, f.StartLine)
+	}
+}
+
+// structuredReviewEnvelope is the JSON shape requested for structured reviews.
+type structuredReviewEnvelope struct {
+	Summary  string    `json:"summary"`
+	Findings []Finding `json:"findings"`
+}
+
+// parseStructuredReview extracts and normalizes findings from a model response.
+func parseStructuredReview(text string) (string, []Finding, error) {
+	raw := extractJSONBlock(text)
+	if strings.TrimSpace(raw) == "" {
+		return "", nil, fmt.Errorf("failed to find JSON object in review response: %q", text)
+	}
+
+	var envelope structuredReviewEnvelope
+	if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+		return "", nil, fmt.Errorf("failed to parse review findings JSON: %w; raw=%q", err, raw)
+	}
+
+	findings := make([]Finding, 0, len(envelope.Findings))
+	for _, f := range envelope.Findings {
+		f.Message = strings.TrimSpace(f.Message)
+		if f.Message == "" {
+			continue
+		}
+		f.File = strings.TrimPrefix(strings.TrimSpace(f.File), "b/")
+		f.Category = strings.ToLower(strings.TrimSpace(f.Category))
+		if f.Category == "" {
+			f.Category = "general"
+		}
+		sev, ok := ParseSeverity(string(f.Severity))
+		if !ok {
+			sev = SeverityMedium
+		}
+		f.Severity = sev
+		if f.StartLine < 0 {
+			f.StartLine = 0
+		}
+		if f.EndLine < f.StartLine {
+			f.EndLine = f.StartLine
+		}
+		f.Suggestion = strings.TrimSpace(f.Suggestion)
+		findings = append(findings, f)
+	}
+	return strings.TrimSpace(envelope.Summary), findings, nil
+}
+
+// writeStructuredReviewInstructions appends the JSON contract for structured reviews.
+func writeStructuredReviewInstructions(builder *strings.Builder) {
+	builder.WriteString("JSON response requirements (very important):\n")
+	builder.WriteString("- Respond ONLY as a single valid JSON object, with no extra text, no explanation, no markdown, and no code fences.\n")
+	builder.WriteString("- The JSON must have exactly this shape and key names:\n")
+	builder.WriteString(`{"summary":"<short overview>","findings":[{"file":"<path as in the diff>","start_line":<int>,"end_line":<int>,"severity":"<info|low|medium|high|critical>","category":"<category>","message":"<what is wrong and why>","suggestion":"<concrete fix or replacement code>"}]}` + "\n")
+	builder.WriteString("- \"file\" is the new path from the diff header without the a/ or b/ prefix; use an empty string for general remarks.\n")
+	builder.WriteString("- \"start_line\" and \"end_line\" are line numbers in the NEW version of the file, taken from the @@ hunk headers; use 0 when unknown.\n")
+	builder.WriteString("- \"severity\" must be one of exactly: info, low, medium, high, critical (lowercase). Reserve high and critical for real bugs, security issues or data loss.\n")
+	builder.WriteString("- \"category\" is a short lowercase tag such as bug, security, performance, tests, style, docs, api, maintainability.\n")
+	builder.WriteString("- Return an empty findings array when there is nothing worth reporting.\n")
+	builder.WriteString("- Do NOT wrap the JSON in ``` or ```json. Do NOT add any commentary before or after the JSON.\n")
+}
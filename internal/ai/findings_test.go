@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStructuredReview(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantSummary string
+		want        []Finding
+	}{
+		{
+			name:        "plain JSON",
+			text:        `{"summary":" Looks fine. ","findings":[{"file":"main.go","start_line":3,"end_line":5,"severity":"high","category":"bug","message":"nil map write","suggestion":"m := map[string]int{}"}]}`,
+			wantSummary: "Looks fine.",
+			want: []Finding{
+				{File: "main.go", StartLine: 3, EndLine: 5, Severity: SeverityHigh, Category: "bug", Message: "nil map write", Suggestion: "m := map[string]int{}"},
+			},
+		},
+		{
+			name:        "fenced JSON with prose around it",
+			text:        "Here is the review:\n```json\n{\"summary\":\"ok\",\"findings\":[{\"file\":\"b/util.go\",\"start_line\":7,\"severity\":\"low\",\"category\":\"Style\",\"message\":\"use {} literal\"}]}\n```\nThanks!",
+			wantSummary: "ok",
+			want: []Finding{
+				{File: "util.go", StartLine: 7, EndLine: 7, Severity: SeverityLow, Category: "style", Message: "use {} literal"},
+			},
+		},
+		{
+			name:        "missing findings array",
+			text:        `{"summary":"Nothing to report."}`,
+			wantSummary: "Nothing to report.",
+			want:        []Finding{},
+		},
+		{
+			name: "bad and mixed-case severities",
+			text: `{"findings":[
+				{"file":"a.go","severity":"CRITICAL","message":"sql injection"},
+				{"file":"a.go","severity":"blocker","message":"unknown severity"},
+				{"file":"a.go","message":"no severity"}]}`,
+			want: []Finding{
+				{File: "a.go", Severity: SeverityCritical, Category: "general", Message: "sql injection"},
+				{File: "a.go", Severity: SeverityMedium, Category: "general", Message: "unknown severity"},
+				{File: "a.go", Severity: SeverityMedium, Category: "general", Message: "no severity"},
+			},
+		},
+		{
+			name: "lines and empty messages are normalized",
+			text: `{"findings":[
+				{"file":" x.go ","start_line":-4,"end_line":-1,"severity":"info","category":"docs","message":"  typo  "},
+				{"file":"y.go","start_line":9,"end_line":2,"severity":"info","category":"docs","message":"reversed range"},
+				{"file":"z.go","severity":"high","message":"   "}]}`,
+			want: []Finding{
+				{File: "x.go", Severity: SeverityInfo, Category: "docs", Message: "typo"},
+				{File: "y.go", StartLine: 9, EndLine: 9, Severity: SeverityInfo, Category: "docs", Message: "reversed range"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, got, err := parseStructuredReview(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary != tt.wantSummary {
+				t.Errorf("summary = %q, want %q", summary, tt.wantSummary)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStructuredReviewErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"I could not review this diff.",
+		`{"summary": "cut off`,
+		`{"findings": "none"}`,
+	} {
+		if _, _, err := parseStructuredReview(text); err == nil {
+			t.Errorf("parseStructuredReview(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Severity
+		ok   bool
+	}{
+		{"high", SeverityHigh, true},
+		{" Critical ", SeverityCritical, true},
+		{"INFO", SeverityInfo, true},
+		{"blocker", Severity("blocker"), false},
+		{"", Severity(""), false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSeverity(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseSeverity(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFindingLocation(t *testing.T) {
+	tests := []struct {
+		f    Finding
+		want string
+	}{
+		{Finding{}, "(general)"},
+		{Finding{File: "a.go"}, "a.go"},
+		{Finding{File: "a.go", StartLine: 4, EndLine: 4}, "a.go:4"},
+		{Finding{File: "a.go", StartLine: 4, EndLine: 9}, "a.go:4-9"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Location(); got != tt.want {
+			t.Errorf("Location(%+v) = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// Focus narrows a review to one area with its own checklist.
+type Focus string
+
+const (
+	FocusSecurity    Focus = "security"
+	FocusPerformance Focus = "performance"
+	FocusTests       Focus = "tests"
+	FocusAPI         Focus = "api"
+)
+
+// Focuses lists the supported focus modes in prompt order.
+var Focuses = []Focus{FocusSecurity, FocusPerformance, FocusTests, FocusAPI}
+
+var focusAliases = map[string]Focus{
+	"security":      FocusSecurity,
+	"sec":           FocusSecurity,
+	"performance":   FocusPerformance,
+	"perf":          FocusPerformance,
+	"tests":         FocusTests,
+	"test":          FocusTests,
+	"testing":       FocusTests,
+	"api":           FocusAPI,
+	"compat":        FocusAPI,
+	"compatibility": FocusAPI,
+}
+
+// ParseFocus normalizes a focus name or alias such as "perf".
+func ParseFocus(s string) (Focus, bool) {
+	f, ok := focusAliases[strings.ToLower(strings.TrimSpace(s))]
+	return f, ok
+}
+
+// focusChecklist holds the checks for one focus, with extra Go-specific
+// items used when the diff touches Go files.
+type focusChecklist struct {
+	title  string
+	checks []string
+	golang []string
+}
+
+var focusChecklists = map[Focus]focusChecklist{
+	FocusSecurity: {
+		title: "Security",
+		checks: []string{
+			"injection: untrusted input reaching shell commands, SQL, templates, file paths or URLs without validation or escaping",
+			"secrets, tokens or personal data hard-coded, logged, or sent to third parties",
+			"missing or weakened authentication, authorization or TLS verification",
+			"unsafe deserialization, path traversal, SSRF and open redirects",
+			"weak cryptography, predictable randomness, or secrets compared without constant time",
+			"files or directories created with overly broad permissions",
+		},
+		golang: []string{
+			"exec.Command with \"sh -c\" or string-built arguments from user input",
+			"math/rand used where crypto/rand is required",
+			"InsecureSkipVerify, http.DefaultClient without timeouts, unbounded io.ReadAll on network input",
+			"filepath.Join with unsanitized user input (check for .. escapes)",
+			"unchecked errors, especially from authentication, crypto, Close or Write calls, that let failures pass silently",
+		},
+	},
+	FocusPerformance: {
+		title: "Performance",
+		checks: []string{
+			"work inside loops that could be hoisted, batched or cached",
+			"quadratic algorithms over inputs that can grow",
+			"unbounded memory growth, large copies, or loading whole files/responses into memory",
+			"N+1 queries or network calls, missing timeouts, pagination or connection reuse",
+			"blocking calls on hot or latency-sensitive paths",
+		},
+		golang: []string{
+			"goroutine leaks: goroutines that block forever on channels or never observe ctx.Done()",
+			"missing slice/map preallocation when the size is known, string concatenation in loops instead of strings.Builder",
+			"context misuse: context.Background/TODO instead of the caller's ctx, contexts stored in structs, cancel functions never called",
+			"defer inside long loops, unclosed response bodies, files or tickers",
+			"lock contention: mutexes held across I/O, copied sync types",
+		},
+	},
+	FocusTests: {
+		title: "Tests",
+		checks: []string{
+			"new behaviour or fixed bugs without a test that would have caught them",
+			"edge cases not covered: empty input, errors, boundaries, concurrency",
+			"tests asserting implementation details instead of behaviour, or that cannot fail",
+			"flaky patterns: sleeps, real network, wall-clock time, shared global state",
+		},
+		golang: []string{
+			"table-driven tests where several cases share one shape",
+			"t.Helper in helpers, t.Cleanup/t.TempDir instead of manual cleanup",
+			"t.Parallel with captured loop variables or shared state",
+			"code paths that need -race coverage",
+		},
+	},
+	FocusAPI: {
+		title: "API compatibility",
+		checks: []string{
+			"removed or renamed public functions, types, fields, flags, config keys or endpoints",
+			"changed signatures, defaults, return values, error values or output formats callers rely on",
+			"wire-format, file-format or database schema changes without a migration path",
+			"behaviour changes that need a deprecation notice, changelog entry or major version bump",
+		},
+		golang: []string{
+			"exported identifiers removed, renamed or given new parameters or results",
+			"methods added to exported interfaces (breaks external implementations)",
+			"exported struct fields removed or retyped, or struct tags changed",
+			"sentinel errors replaced, or errors no longer matching errors.Is/errors.As",
+		},
+	},
+}
+
+// Title returns the human-readable section name for f.
+func (f Focus) Title() string {
+	if c, ok := focusChecklists[f]; ok {
+		return c.title
+	}
+	return string(f)
+}
+
+// writeFocusSections appends the checklist of each requested focus. Go items
+// are included only when the diff touches Go files.
+func writeFocusSections(builder *strings.Builder, focuses []Focus, golang bool) {
+	if len(focuses) == 0 {
+		return
+	}
+	builder.WriteString("Review focus: concentrate on the areas below and skip unrelated style remarks. Work through each checklist against the diff:\n")
+	for _, f := range focuses {
+		checklist, ok := focusChecklists[f]
+		if !ok {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("## %s (category \"%s\")\n", checklist.title, f))
+		for _, check := range checklist.checks {
+			builder.WriteString("- " + check + "\n")
+		}
+		if golang {
+			for _, check := range checklist.golang {
+				builder.WriteString("- Go: " + check + "\n")
+			}
+		}
+	}
+}
+
+// focusCategories renders the focus names for the category instruction.
+func focusCategories(focuses []Focus) string {
+	names := make([]string, 0, len(focuses))
+	for _, f := range focuses {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// normalizeFocusCategories maps category aliases such as "perf" onto the
+// canonical focus names so findings can be grouped by focus.
+func normalizeFocusCategories(findings []Finding) {
+	for i := range findings {
+		if f, ok := ParseFocus(findings[i].Category); ok {
+			findings[i].Category = string(f)
+		}
+	}
+}
+
+// diffTouchesGo reports whether the diff changes any Go source file.
+func diffTouchesGo(diff string) bool {
+	parsed, err := git.ParseDiff(diff)
+	if err != nil {
+		return strings.Contains(diff, ".go\n")
+	}
+	for _, f := range parsed.Files {
+		if strings.HasSuffix(f.Path(), ".go") {
+			return true
+		}
+	}
+	return false
+}
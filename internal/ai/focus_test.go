@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFocus(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Focus
+		ok   bool
+	}{
+		{"security", FocusSecurity, true},
+		{"sec", FocusSecurity, true},
+		{"perf", FocusPerformance, true},
+		{"Performance", FocusPerformance, true},
+		{"tests", FocusTests, true},
+		{"test", FocusTests, true},
+		{" testing ", FocusTests, true},
+		{"api", FocusAPI, true},
+		{"COMPAT", FocusAPI, true},
+		{"style", "", false},
+		{"", "", false},
+		{"security,perf", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseFocus(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseFocus(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBuildPromptFocusChecklists(t *testing.T) {
+	const goDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1 +1,2 @@
+ package main
++// TODO
+`
+	const docDiff = `diff --git a/README.md b/README.md
+index 1111111..2222222 100644
+--- a/README.md
++++ b/README.md
+@@ -1 +1,2 @@
+ # demo
++More docs.
+`
+	tests := []struct {
+		name   string
+		diff   string
+		focus  []Focus
+		golang bool
+	}{
+		{"security on Go", goDiff, []Focus{FocusSecurity}, true},
+		{"performance and tests on Go", goDiff, []Focus{FocusPerformance, FocusTests}, true},
+		{"api on docs", docDiff, []Focus{FocusAPI}, false},
+		{"all on docs", docDiff, Focuses, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := buildPrompt(ReviewRequest{Diff: tt.diff, Focus: tt.focus})
+			selected := make(map[Focus]bool)
+			for _, f := range tt.focus {
+				selected[f] = true
+			}
+			for _, f := range Focuses {
+				checklist := focusChecklists[f]
+				header := "## " + checklist.title + " (category \"" + string(f) + "\")"
+				if got := strings.Contains(prompt, header); got != selected[f] {
+					t.Errorf("prompt contains %q = %v, want %v", header, got, selected[f])
+				}
+				for _, check := range checklist.checks {
+					if got := strings.Contains(prompt, "- "+check+"\n"); got != selected[f] {
+						t.Errorf("prompt contains %s check %q = %v, want %v", f, check, got, selected[f])
+					}
+				}
+				for _, check := range checklist.golang {
+					want := selected[f] && tt.golang
+					if got := strings.Contains(prompt, "- Go: "+check+"\n"); got != want {
+						t.Errorf("prompt contains %s Go check %q = %v, want %v", f, check, got, want)
+					}
+				}
+			}
+		})
+	}
+
+	if prompt := buildPrompt(ReviewRequest{Diff: goDiff}); strings.Contains(prompt, "Review focus:") {
+		t.Error("prompt without --focus contains a focus checklist")
+	}
+}
+
+func TestBuildPromptFocusCategories(t *testing.T) {
+	prompt := buildPrompt(ReviewRequest{Structured: true, Focus: []Focus{FocusSecurity, FocusAPI}})
+	if want := `one of: security, api.`; !strings.Contains(prompt, want) {
+		t.Errorf("structured prompt does not restrict categories to %q", want)
+	}
+}
+
+func TestNormalizeFocusCategories(t *testing.T) {
+	findings := []Finding{{Category: "perf"}, {Category: "Compat"}, {Category: "security"}, {Category: "style"}}
+	normalizeFocusCategories(findings)
+	want := []string{"performance", "api", "security", "style"}
+	for i, f := range findings {
+		if f.Category != want[i] {
+			t.Errorf("finding %d category = %q, want %q", i, f.Category, want[i])
+		}
+	}
+}
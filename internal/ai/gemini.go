@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/repocontext"
 )
 
 const (
@@ -21,15 +23,43 @@ const (
 	defaultModel      = "gemini-2.0-flash"
 	defaultBaseURL    = "https://generativelanguage.googleapis.com/v1beta"
 	maxDiffCharacters = 12000
+	// maxPromptCommits caps how many commit messages are included in a prompt.
+	maxPromptCommits = 50
 )
 
-// Client handles calls to the Gemini 2.5 Flash API.
+// Provider identifies the API flavour a Client talks to.
+type Provider string
+
+const (
+	// ProviderGemini is the Google Gemini generateContent API (default).
+	ProviderGemini Provider = "gemini"
+	// ProviderOpenAI is any OpenAI-compatible chat completions API,
+	// including self-hosted or company gateways.
+	ProviderOpenAI Provider = "openai"
+)
+
+// Client handles calls to the Gemini 2.5 Flash API, or to an
+// OpenAI-compatible gateway when configured through Options.
 type Client struct {
+	provider   Provider
 	apiKey     string
 	model      string
 	httpClient *http.Client
 	maxTokens  int
 	baseURL    string
+	redact     bool
+}
+
+// Options configures a Client created with NewClientWithOptions.
+type Options struct {
+	Provider  Provider
+	APIKey    string
+	Model     string
+	BaseURL   string
+	MaxTokens int
+	// Redact masks secrets, emails and similar data in every prompt
+	// before it leaves the machine.
+	Redact bool
 }
 
 // RiskLevel represents the AI-assessed risk when running a suggested command.
@@ -69,17 +99,36 @@ type commandSuggestionEnvelope struct {
 
 // ReviewRequest bundles the information sent to Gemini for analysis.
 type ReviewRequest struct {
-	Diff      string
-	RepoInfo  git.RepoInfo
-	Mode      string
-	Language  string
-	Short     bool
-	CreatedAt time.Time
+	Diff     string
+	RepoInfo git.RepoInfo
+	Mode     string
+	// Target is a human-readable description of what is being reviewed,
+	// e.g. "changes since merge-base with main".
+	Target string
+	// Commits are the commits covered by the diff, newest first. Their
+	// messages give the reviewer the author's stated intent.
+	Commits []git.LogEntry
+	// SkippedFiles are one-line summaries of files left out of Diff
+	// (lockfiles, generated or vendored code).
+	SkippedFiles []string
+	// Context holds unchanged code, history and docs around the diff so the
+	// reviewer can resolve symbols defined outside the hunks.
+	Context repocontext.Bundle
+	// Focus limits the review to the given areas, each with a checklist.
+	Focus    []Focus
+	Language string
+	Short    bool
+	// Structured asks for machine-readable findings instead of free text.
+	Structured bool
+	CreatedAt  time.Time
 }
 
-// ReviewResponse encapsulates the text returned by Gemini.
+// ReviewResponse encapsulates the text returned by Gemini. For structured
+// reviews, Summary and Findings are populated from the JSON response.
 type ReviewResponse struct {
-	Text string
+	Text     string
+	Summary  string
+	Findings []Finding
 }
 
 // CommitAnalysisRequest carries the diff used to generate a commit message
@@ -87,6 +136,13 @@ type ReviewResponse struct {
 type CommitAnalysisRequest struct {
 	Diff     string
 	RepoInfo git.RepoInfo
+	// SkippedFiles are one-line summaries of files left out of Diff.
+	SkippedFiles []string
+	// Language is the BCP-47 tag for privacy reasons shown to the user.
+	Language string
+	// MessageLanguage, when set, is the BCP-47 tag for the commit message
+	// description; otherwise the message is written in English.
+	MessageLanguage string
 }
 
 // CommitAnalysisResponse wraps the AI-generated commit message,
@@ -100,25 +156,119 @@ type CommitAnalysisResponse struct {
 
 // NewClient creates a Gemini client.
 func NewClient(apiKey string, maxTokens int) *Client {
-	model := os.Getenv("GEMINI_MODEL")
-	if model == "" {
-		model = defaultModel
+	return NewClientWithOptions(Options{
+		Provider:  ProviderGemini,
+		APIKey:    apiKey,
+		Model:     os.Getenv("GEMINI_MODEL"),
+		MaxTokens: maxTokens,
+	})
+}
+
+// NewClientWithOptions creates a client for the provider in opts, filling
+// in provider defaults for the model and base URL.
+func NewClientWithOptions(opts Options) *Client {
+	provider := Provider(strings.ToLower(strings.TrimSpace(string(opts.Provider))))
+	if provider == "" {
+		provider = ProviderGemini
 	}
+
+	model := strings.TrimSpace(opts.Model)
+	baseURL := strings.TrimRight(strings.TrimSpace(opts.BaseURL), "/")
+	switch provider {
+	case ProviderOpenAI:
+		if model == "" {
+			model = defaultOpenAIModel
+		}
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+	default:
+		if model == "" {
+			model = defaultModel
+		}
+		if baseURL == "" {
+			baseURL = defaultBaseURL
+		}
+	}
+
+	maxTokens := opts.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = 1024
 	}
 
 	return &Client{
-		apiKey: apiKey,
-		model:  model,
+		provider: provider,
+		apiKey:   opts.APIKey,
+		model:    model,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 		maxTokens: maxTokens,
-		baseURL:   defaultBaseURL,
+		baseURL:   baseURL,
+		redact:    opts.Redact,
 	}
 }
 
+// generate sends a single-turn prompt to the configured provider and
+// returns the first non-empty text in the response.
+func (c *Client) generate(ctx context.Context, prompt string, maxTokens int, temperature float64) (string, error) {
+	if c.redact {
+		prompt = Redact(prompt)
+	}
+	if c.provider == ProviderOpenAI {
+		return c.generateOpenAI(ctx, prompt, maxTokens, temperature)
+	}
+	return c.generateGemini(ctx, prompt, maxTokens, temperature)
+}
+
+func (c *Client) generateGemini(ctx context.Context, prompt string, maxTokens int, temperature float64) (string, error) {
+	payload := generateContentRequest{
+		Contents: []content{
+			{
+				Role: "user",
+				Parts: []part{
+					{Text: prompt},
+				},
+			},
+		},
+		GenerationConfig: &generationConfig{
+			MaxOutputTokens: intPtr(maxTokens),
+			Temperature:     floatPtr(temperature),
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, c.model, c.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr map[string]any
+		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
+		return "", fmt.Errorf("gemini API error: status=%d body=%v", httpResp.StatusCode, apiErr)
+	}
+
+	var genResp generateContentResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&genResp); err != nil {
+		return "", err
+	}
+
+	return genResp.extractText()
+}
+
 // SuggestCommands asks Gemini to propose CLI commands for a natural-language
 // request, given the current system context. The AI is instructed to respond
 // with a strict, machine-parseable JSON structure.
@@ -176,53 +326,7 @@ func (c *Client) SuggestCommands(ctx context.Context, message string, sysCtx Sys
 	builder.WriteString("- The \"tags\" field is optional but recommended; use simple tags like system, git, network, process, disk, ram, cpu.\n")
 	builder.WriteString("- Do NOT wrap the JSON in ``` or ```json. Do NOT add any commentary before or after the JSON.\n")
 
-	userPrompt := builder.String()
-
-	payload := generateContentRequest{
-		Contents: []content{
-			{
-				Role: "user",
-				Parts: []part{
-					{Text: userPrompt},
-				},
-			},
-		},
-		GenerationConfig: &generationConfig{
-			MaxOutputTokens: intPtr(c.maxTokens),
-			Temperature:     floatPtr(0.4),
-		},
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return suggestions, err
-	}
-
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, c.model, c.apiKey)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return suggestions, err
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return suggestions, err
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		var apiErr map[string]any
-		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
-		return suggestions, fmt.Errorf("gemini API error: status=%d body=%v", httpResp.StatusCode, apiErr)
-	}
-
-	var genResp generateContentResponse
-	if err := json.NewDecoder(httpResp.Body).Decode(&genResp); err != nil {
-		return suggestions, err
-	}
-
-	text, err := genResp.extractText()
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.4)
 	if err != nil {
 		return suggestions, err
 	}
@@ -279,57 +383,27 @@ func (c *Client) ReviewDiff(ctx context.Context, req ReviewRequest) (ReviewRespo
 		return resp, errors.New("diff is empty")
 	}
 
-	userPrompt := buildPrompt(req)
-	payload := generateContentRequest{
-		Contents: []content{
-			{
-				Role: "user",
-				Parts: []part{
-					{Text: userPrompt},
-				},
-			},
-		},
-		GenerationConfig: &generationConfig{
-			MaxOutputTokens: intPtr(c.maxTokens),
-			Temperature:     floatPtr(0.4),
-		},
+	temperature := 0.4
+	if req.Structured {
+		temperature = 0.2
 	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return resp, err
-	}
-
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, c.model, c.apiKey)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return resp, err
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return resp, err
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		var apiErr map[string]any
-		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
-		return resp, fmt.Errorf("gemini API error: status=%d body=%v", httpResp.StatusCode, apiErr)
-	}
-
-	var genResp generateContentResponse
-	if err := json.NewDecoder(httpResp.Body).Decode(&genResp); err != nil {
-		return resp, err
-	}
-
-	text, err := genResp.extractText()
+	text, err := c.generate(ctx, buildPrompt(req), c.maxTokens, temperature)
 	if err != nil {
 		return resp, err
 	}
 
 	resp.Text = text
+	if req.Structured {
+		summary, findings, err := parseStructuredReview(text)
+		if err != nil {
+			return resp, err
+		}
+		if len(req.Focus) > 0 {
+			normalizeFocusCategories(findings)
+		}
+		resp.Summary = summary
+		resp.Findings = findings
+	}
 	return resp, nil
 }
 
@@ -338,7 +412,7 @@ func (c *Client) ReviewDiff(ctx context.Context, req ReviewRequest) (ReviewRespo
 func (c *Client) AnalyzeCommit(ctx context.Context, req CommitAnalysisRequest) (CommitAnalysisResponse, error) {
 	var resp CommitAnalysisResponse
 
-	if strings.TrimSpace(req.Diff) == "" {
+	if strings.TrimSpace(req.Diff) == "" && len(req.SkippedFiles) == 0 {
 		return resp, errors.New("diff is empty")
 	}
 
@@ -384,63 +458,22 @@ func (c *Client) AnalyzeCommit(ctx context.Context, req CommitAnalysisRequest) (
 	builder.WriteString("- Use \"high\" if there is a clear chance of credentials, tokens, secrets, or personal data being exposed.\n")
 	builder.WriteString("Requirements for privacy_reasons:\n")
 	builder.WriteString("- Provide short, human-readable reasons if risk is medium or high; can be empty for low.\n")
+	builder.WriteString(fmt.Sprintf("- Write the reasons in %s.\n", responseLanguage(req.Language).Label()))
+	if msgLang := responseLanguage(req.MessageLanguage); !msgLang.IsEnglish() {
+		builder.WriteString(fmt.Sprintf("Language override for commit_message: write the description (and body, if any) in %s instead of English. Keep the type, scope, BREAKING CHANGE footer keyword and branch_name in English ASCII.\n", msgLang.Label()))
+	}
 	builder.WriteString(fmt.Sprintf("Repository path: %s\nBranch: %s\nRemote: %s\n",
 		req.RepoInfo.Path,
 		req.RepoInfo.Branch,
 		req.RepoInfo.Remote,
 	))
+	writeSkippedFiles(&builder, req.SkippedFiles)
 	builder.WriteString("Git diff:\n")
 	builder.WriteString("---\n")
 	builder.WriteString(trimDiff(req.Diff))
 	builder.WriteString("\n---\n")
 
-	userPrompt := builder.String()
-
-	payload := generateContentRequest{
-		Contents: []content{
-			{
-				Role: "user",
-				Parts: []part{
-					{Text: userPrompt},
-				},
-			},
-		},
-		GenerationConfig: &generationConfig{
-			MaxOutputTokens: intPtr(256),
-			Temperature:     floatPtr(0.3),
-		},
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return resp, err
-	}
-
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, c.model, c.apiKey)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return resp, err
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return resp, err
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		var apiErr map[string]any
-		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
-		return resp, fmt.Errorf("gemini API error: status=%d body=%v", httpResp.StatusCode, apiErr)
-	}
-
-	var genResp generateContentResponse
-	if err := json.NewDecoder(httpResp.Body).Decode(&genResp); err != nil {
-		return resp, err
-	}
-
-	text, err := genResp.extractText()
+	text, err := c.generate(ctx, builder.String(), 256, 0.3)
 	if err != nil {
 		return resp, err
 	}
@@ -462,6 +495,18 @@ func (c *Client) AnalyzeCommit(ctx context.Context, req CommitAnalysisRequest) (
 	return resp, nil
 }
 
+// responseLanguage parses a BCP-47 tag, falling back to English.
+func responseLanguage(tag string) i18n.Language {
+	if strings.TrimSpace(tag) == "" {
+		return i18n.English
+	}
+	lang, err := i18n.Parse(tag)
+	if err != nil {
+		return i18n.English
+	}
+	return lang
+}
+
 // extractJSONBlock tries to pull the first top-level JSON object from a text response.
 func extractJSONBlock(s string) string {
 	start := strings.Index(s, "{")
@@ -469,10 +514,25 @@ func extractJSONBlock(s string) string {
 		return ""
 	}
 
-	// Simple brace matching to find the matching closing brace.
+	// Simple brace matching to find the matching closing brace. Braces
+	// inside JSON strings (e.g. code in a value) are ignored.
 	depth := 0
+	inString, escaped := false, false
 	for i := start; i < len(s); i++ {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case s[i] == '\\':
+				escaped = true
+			case s[i] == '"':
+				inString = false
+			}
+			continue
+		}
 		switch s[i] {
+		case '"':
+			inString = true
 		case '{':
 			depth++
 		case '}':
@@ -486,27 +546,34 @@ func extractJSONBlock(s string) string {
 }
 
 func buildPrompt(req ReviewRequest) string {
-	lang := strings.ToLower(req.Language)
-	if lang != "vi" {
-		lang = "en"
-	}
+	lang := responseLanguage(req.Language)
 
 	modeLabel := "staged changes"
 	if req.Mode == "last-commit" {
 		modeLabel = "latest commit"
 	}
+	if strings.TrimSpace(req.Target) != "" {
+		modeLabel = req.Target
+	}
 
 	var builder strings.Builder
 	builder.WriteString("You are an experienced software engineer performing a code review for git changes.\n")
-	builder.WriteString("Provide structured feedback with sections: Overview, Risks/Bugs, Refactoring Ideas, Testing Suggestions, Commit Message feedback.\n")
+	switch {
+	case req.Structured:
+		builder.WriteString("Report each issue as a separate finding tied to the file and line range it concerns, with a severity, a category and a suggested fix.\n")
+	case len(req.Focus) > 0:
+		titles := make([]string, 0, len(req.Focus))
+		for _, f := range req.Focus {
+			titles = append(titles, f.Title())
+		}
+		builder.WriteString(fmt.Sprintf("Provide structured feedback with sections: Overview, %s.\n", strings.Join(titles, ", ")))
+	default:
+		builder.WriteString("Provide structured feedback with sections: Overview, Risks/Bugs, Refactoring Ideas, Testing Suggestions, Commit Message feedback.\n")
+	}
 	if req.Short {
 		builder.WriteString("Focus on the most critical issues and keep the response concise.\n")
 	}
-	if lang == "vi" {
-		builder.WriteString("Respond in Vietnamese with clear, natural language.\n")
-	} else {
-		builder.WriteString("Respond in English with clear, natural language.\n")
-	}
+	builder.WriteString(fmt.Sprintf("Respond in %s with clear, natural language.\n", lang.Label()))
 	builder.WriteString(fmt.Sprintf("Repository path: %s\nBranch: %s\nRemote: %s\nReview target: %s\nDate: %s\n",
 		req.RepoInfo.Path,
 		req.RepoInfo.Branch,
@@ -514,14 +581,102 @@ func buildPrompt(req ReviewRequest) string {
 		modeLabel,
 		req.CreatedAt.Format(time.RFC3339),
 	))
-	builder.WriteString("Git diff:\n")
-	builder.WriteString("---\n")
-	builder.WriteString(trimDiff(req.Diff))
-	builder.WriteString("\n---\n")
+	if len(req.Commits) > 0 {
+		builder.WriteString("Commits included in this review (newest first). Check that the changes match what the messages claim:\n")
+		writeCommitMessages(&builder, req.Commits)
+	}
+	writeSkippedFiles(&builder, req.SkippedFiles)
+	writeRepositoryContext(&builder, req.Context)
+	if annotated := annotatedDiff(req); annotated != "" {
+		builder.WriteString("Git diff, one section per file. The left gutter holds the line number in the NEW file (deleted lines show -<old line>); use these numbers for start_line and end_line:\n")
+		builder.WriteString("---\n")
+		builder.WriteString(trimDiff(annotated))
+		builder.WriteString("\n---\n")
+	} else {
+		builder.WriteString("Git diff:\n")
+		builder.WriteString("---\n")
+		builder.WriteString(trimDiff(req.Diff))
+		builder.WriteString("\n---\n")
+	}
+	writeFocusSections(&builder, req.Focus, diffTouchesGo(req.Diff))
 	builder.WriteString("Deliver actionable insights and mention missing tests or risks explicitly.\n")
+	if req.Structured && len(req.Focus) > 0 {
+		builder.WriteString(fmt.Sprintf("Set \"category\" to the focus area the finding belongs to, one of: %s.\n", focusCategories(req.Focus)))
+	}
+	if req.Structured {
+		builder.WriteString("Write summary, message and suggestion values in the requested language; keep JSON keys and enum values in English.\n")
+		writeStructuredReviewInstructions(&builder)
+	}
 	return builder.String()
 }
 
+// annotatedDiff renders the diff with line numbers for structured reviews,
+// so findings can be mapped back onto real file positions.
+func annotatedDiff(req ReviewRequest) string {
+	if !req.Structured {
+		return ""
+	}
+	parsed, err := git.ParseDiff(req.Diff)
+	if err != nil || len(parsed.Files) == 0 {
+		return ""
+	}
+	return parsed.AnnotatedString()
+}
+
+// writeSkippedFiles lists files whose diffs were left out of the prompt.
+func writeSkippedFiles(builder *strings.Builder, skipped []string) {
+	if len(skipped) == 0 {
+		return
+	}
+	builder.WriteString("Also changed, but omitted from the diff below (lockfiles, generated, vendored or binary files; do not review their contents):\n")
+	for _, s := range skipped {
+		builder.WriteString("- " + s + "\n")
+	}
+}
+
+// writeRepositoryContext appends unchanged code and documentation that
+// surrounds the diff. It is reference material only, not part of the review.
+func writeRepositoryContext(builder *strings.Builder, bundle repocontext.Bundle) {
+	if bundle.Empty() {
+		return
+	}
+	builder.WriteString("Repository context follows. It is unchanged code and documentation around the diff, provided so you can resolve identifiers defined outside the hunks; do not report findings on it.\n")
+	for _, s := range bundle.Snippets {
+		builder.WriteString(fmt.Sprintf("### %s:%d-%d (%s)\n", s.Path, s.StartLine, s.EndLine, s.Label))
+		builder.WriteString("```\n")
+		builder.WriteString(strings.TrimRight(s.Content, "\n"))
+		builder.WriteString("\n```\n")
+	}
+	if len(bundle.History) > 0 {
+		builder.WriteString("Earlier commits touching the same files (newest first):\n")
+		writeCommitMessages(builder, bundle.History)
+	}
+	if bundle.Readme != nil {
+		builder.WriteString(fmt.Sprintf("### %s\n", bundle.Readme.Label))
+		builder.WriteString(strings.TrimRight(bundle.Readme.Content, "\n"))
+		builder.WriteString("\n")
+	}
+	if bundle.Truncated {
+		builder.WriteString("(Some context was omitted to stay within the token budget.)\n")
+	}
+}
+
+// writeCommitMessages appends commit headers and bodies as prompt context.
+func writeCommitMessages(builder *strings.Builder, commits []git.LogEntry) {
+	for i, commit := range commits {
+		if i == maxPromptCommits {
+			builder.WriteString(fmt.Sprintf("... (%d more commits omitted)\n", len(commits)-maxPromptCommits))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("- %s %s (%s)\n", commit.ShortHash(), commit.Subject, commit.Author))
+		if commit.Body != "" {
+			for _, line := range strings.Split(commit.Body, "\n") {
+				builder.WriteString("    " + line + "\n")
+			}
+		}
+	}
+}
+
 func trimDiff(diff string) string {
 	diff = strings.TrimSpace(diff)
 	if len(diff) <= maxDiffCharacters {
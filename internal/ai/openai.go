@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIModel   = "gpt-4o-mini"
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+)
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// generateOpenAI calls an OpenAI-compatible /chat/completions endpoint.
+func (c *Client) generateOpenAI(ctx context.Context, prompt string, maxTokens int, temperature float64) (string, error) {
+	payload := chatCompletionRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "user", Content: prompt},
+		},
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr map[string]any
+		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
+		return "", fmt.Errorf("openai-compatible API error: status=%d body=%v", httpResp.StatusCode, apiErr)
+	}
+
+	var chatResp chatCompletionResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&chatResp); err != nil {
+		return "", err
+	}
+
+	for _, choice := range chatResp.Choices {
+		if text := strings.TrimSpace(choice.Message.Content); text != "" {
+			return text, nil
+		}
+	}
+	return "", errors.New("openai-compatible response contained no non-empty choices")
+}
@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// maxTemplateCharacters caps the PR template copied into the prompt.
+const maxTemplateCharacters = 4000
+
+// PullRequestRequest bundles what is sent to the model to describe a branch.
+type PullRequestRequest struct {
+	Diff     string
+	RepoInfo git.RepoInfo
+	// Base is the branch the pull request targets, e.g. "main".
+	Base string
+	// Commits are the commits on the branch since the merge-base, newest first.
+	Commits []git.LogEntry
+	// SkippedFiles are one-line summaries of files left out of Diff.
+	SkippedFiles []string
+	// Template is the repository's pull request template, if any. The body
+	// follows its headings instead of the default sections.
+	Template  string
+	Language  string
+	CreatedAt time.Time
+}
+
+// PullRequestDescription is a generated pull request title and Markdown body.
+type PullRequestDescription struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// DescribePullRequest asks the model for a pull request title and body.
+func (c *Client) DescribePullRequest(ctx context.Context, req PullRequestRequest) (PullRequestDescription, error) {
+	var resp PullRequestDescription
+
+	if strings.TrimSpace(req.Diff) == "" && len(req.Commits) == 0 {
+		return resp, errors.New("no commits or changes to describe")
+	}
+
+	text, err := c.generate(ctx, buildPullRequestPrompt(req), c.maxTokens, 0.3)
+	if err != nil {
+		return resp, err
+	}
+
+	clean := extractJSONBlock(text)
+	if strings.TrimSpace(clean) == "" {
+		return resp, fmt.Errorf("failed to find JSON object in pull request response: %q", text)
+	}
+	if err := json.Unmarshal([]byte(clean), &resp); err != nil {
+		return resp, fmt.Errorf("failed to parse pull request JSON: %w; raw=%q", err, clean)
+	}
+
+	resp.Title = strings.TrimSpace(resp.Title)
+	resp.Body = strings.TrimSpace(resp.Body)
+	if resp.Title == "" {
+		return resp, errors.New("AI returned an empty pull request title")
+	}
+	return resp, nil
+}
+
+func buildPullRequestPrompt(req PullRequestRequest) string {
+	lang := responseLanguage(req.Language)
+
+	var builder strings.Builder
+	builder.WriteString("You are an experienced software engineer writing the pull request for a branch you are about to open for review.\n")
+	builder.WriteString("Write for reviewers who have not seen the code: explain what changes and why, not how every line changed.\n")
+	builder.WriteString(fmt.Sprintf("Write the title and body in %s.\n", lang.Label()))
+	builder.WriteString("Title requirements:\n")
+	builder.WriteString("- A single line, at most 72 characters, imperative mood, no trailing period.\n")
+	builder.WriteString("- If the commits follow Conventional Commits, use the same <type>(<scope>): <description> form for the overall change.\n")
+	builder.WriteString("Body requirements:\n")
+	if strings.TrimSpace(req.Template) != "" {
+		builder.WriteString("- The repository has a pull request template. Fill it in: keep its headings and checklists in order, replace placeholder text and HTML comments with real content, and leave a checkbox unticked unless the diff shows it is done.\n")
+		builder.WriteString("Pull request template:\n")
+		builder.WriteString("---\n")
+		builder.WriteString(trimText(req.Template, maxTemplateCharacters))
+		builder.WriteString("\n---\n")
+	} else {
+		builder.WriteString("- Use these Markdown sections in order: ## Summary, ## Changes, ## Testing, ## Risk.\n")
+		builder.WriteString("- Summary: two or three sentences on what the branch does and why.\n")
+		builder.WriteString("- Changes: a bullet list of the notable changes, grouped by area.\n")
+		builder.WriteString("- Testing: how the change was or should be verified; mention missing tests explicitly.\n")
+		builder.WriteString("- Risk: what could break, migrations or config changes, and how to roll back.\n")
+	}
+	builder.WriteString("- Do not invent issue numbers, links, screenshots or test results that are not in the commits or diff.\n")
+	builder.WriteString(fmt.Sprintf("Repository path: %s\nBranch: %s\nRemote: %s\nTarget branch: %s\nDate: %s\n",
+		req.RepoInfo.Path,
+		req.RepoInfo.Branch,
+		req.RepoInfo.Remote,
+		req.Base,
+		req.CreatedAt.Format(time.RFC3339),
+	))
+	if len(req.Commits) > 0 {
+		builder.WriteString("Commits on the branch (newest first):\n")
+		writeCommitMessages(&builder, req.Commits)
+	}
+	writeSkippedFiles(&builder, req.SkippedFiles)
+	builder.WriteString("Git diff against the merge-base:\n")
+	builder.WriteString("---\n")
+	builder.WriteString(trimDiff(req.Diff))
+	builder.WriteString("\n---\n")
+	builder.WriteString("JSON response requirements (very important):\n")
+	builder.WriteString("- Respond ONLY as a single valid JSON object, with no extra text, no explanation, and no code fences.\n")
+	builder.WriteString("- The JSON must have exactly this shape and key names:\n")
+	builder.WriteString(`{"title":"<pull request title>","body":"<Markdown body>"}` + "\n")
+	builder.WriteString("- Escape newlines in the body as \\n so the JSON stays valid.\n")
+	builder.WriteString("- Do NOT wrap the JSON in ``` or ```json. Do NOT add any commentary before or after the JSON.\n")
+	return builder.String()
+}
+
+func trimText(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= limit {
+		return text
+	}
+	// Cut at a rune boundary so the prompt stays valid UTF-8.
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit] + "\n... (truncated)"
+}
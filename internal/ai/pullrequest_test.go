@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrimText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"short", "  hello \n", 10, "hello"},
+		{"exact", "hello", 5, "hello"},
+		{"ascii", "hello world", 5, "hello\n... (truncated)"},
+		{"multibyte boundary", "héllo", 2, "h\n... (truncated)"},
+		{"cjk", "日本語", 4, "日\n... (truncated)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trimText(tt.text, tt.limit)
+			if got != tt.want {
+				t.Errorf("trimText(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("trimText(%q, %d) is not valid UTF-8", tt.text, tt.limit)
+			}
+			if !strings.HasPrefix(strings.TrimSpace(tt.text), strings.TrimSuffix(got, "\n... (truncated)")) {
+				t.Errorf("trimText(%q, %d) = %q is not a prefix", tt.text, tt.limit, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package ai
+
+import "regexp"
+
+// redactionRules lists patterns masked by Redact, most specific first.
+// The rules favour false positives: a masked harmless value costs little,
+// a leaked credential costs a lot.
+var redactionRules = []struct {
+	// kind names what the rule detects, for FindSecrets.
+	kind        string
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{"private key", regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`), "[REDACTED PRIVATE KEY]"},
+	{"AWS access key", regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`), "[REDACTED AWS KEY]"},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`), "[REDACTED GOOGLE KEY]"},
+	{"access token", regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr|github_pat|glpat|xox[abprs])[-_][0-9A-Za-z_\-]{16,}\b`), "[REDACTED TOKEN]"},
+	{"API key", regexp.MustCompile(`\bsk-[0-9A-Za-z_\-]{20,}\b`), "[REDACTED TOKEN]"},
+	{"authorization header", regexp.MustCompile(`(?i)\b(bearer|basic)\s+[0-9A-Za-z._~+/=\-]{16,}`), "$1 [REDACTED]"},
+	{"password or secret assignment", regexp.MustCompile(`(?i)([a-z0-9_.\-]*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key)[a-z0-9_.\-]*["']?\s*[:=]\s*)["']?[^\s"',;\[][^\s"',;]{3,}["']?`), "$1[REDACTED]"},
+	{"URL credentials", regexp.MustCompile(`([a-z][a-z0-9+.\-]*://)[^/\s:@]+:[^/\s@]+@`), "$1[REDACTED]@"},
+	{"email address", regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`), "[REDACTED EMAIL]"},
+}
+
+// Redact masks credentials, tokens, private keys, URL passwords and email
+// addresses in text. It is used by profiles with strict redaction so that
+// prompts sent to shared gateways never carry obvious secrets.
+func Redact(text string) string {
+	for _, rule := range redactionRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
+
+// FindSecrets returns what kinds of credentials and personal data Redact
+// would mask in text, in rule order and without duplicates.
+func FindSecrets(text string) []string {
+	var kinds []string
+	for _, rule := range redactionRules {
+		if rule.pattern.MatchString(text) {
+			kinds = append(kinds, rule.kind)
+		}
+	}
+	return kinds
+}
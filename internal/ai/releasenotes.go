@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// ReleaseNote is one terse changelog entry to rewrite.
+type ReleaseNote struct {
+	Section string
+	Scope   string
+	Text    string
+}
+
+// ReleaseNotesRequest asks for user-facing wording of changelog entries.
+type ReleaseNotesRequest struct {
+	Notes    []ReleaseNote
+	RepoInfo git.RepoInfo
+	Language string
+}
+
+// RewriteReleaseNotes turns terse commit headers into release notes for
+// users. It returns exactly one rewritten line per input note, in order.
+func (c *Client) RewriteReleaseNotes(ctx context.Context, req ReleaseNotesRequest) ([]string, error) {
+	if len(req.Notes) == 0 {
+		return nil, nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("You are writing release notes for the users of a software project.\n")
+	builder.WriteString("Rewrite each changelog entry below into one clear, user-facing sentence that says what changed for the user and why it matters.\n")
+	builder.WriteString(fmt.Sprintf("Write in %s. Keep identifiers, flags, commands and file names unchanged.\n", responseLanguage(req.Language).Label()))
+	builder.WriteString("Do not invent details that the entry does not state, do not merge or split entries, and do not add the scope or a trailing commit hash.\n")
+	builder.WriteString(fmt.Sprintf("Repository: %s\nRemote: %s\n", req.RepoInfo.Path, req.RepoInfo.Remote))
+	builder.WriteString("Entries (index. [section] scope: text):\n")
+	for i, n := range req.Notes {
+		scope := ""
+		if n.Scope != "" {
+			scope = n.Scope + ": "
+		}
+		builder.WriteString(fmt.Sprintf("%d. [%s] %s%s\n", i+1, n.Section, scope, n.Text))
+	}
+	builder.WriteString("JSON response requirements (very important):\n")
+	builder.WriteString("- Respond ONLY as a single valid JSON object, with no extra text and no code fences.\n")
+	builder.WriteString(fmt.Sprintf("- Shape: {\"notes\":[\"<rewritten entry 1>\", ...]} with exactly %d strings in the same order as the entries.\n", len(req.Notes)))
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.3)
+	if err != nil {
+		return nil, err
+	}
+
+	clean := extractJSONBlock(text)
+	if strings.TrimSpace(clean) == "" {
+		return nil, fmt.Errorf("failed to find JSON object in release notes response: %q", text)
+	}
+	var parsed struct {
+		Notes []string `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(clean), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse release notes JSON: %w; raw=%q", err, clean)
+	}
+	if len(parsed.Notes) != len(req.Notes) {
+		return nil, fmt.Errorf("AI returned %d release notes for %d entries", len(parsed.Notes), len(req.Notes))
+	}
+	for i, n := range parsed.Notes {
+		parsed.Notes[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(n), "- "))
+		if parsed.Notes[i] == "" {
+			return nil, errors.New("AI returned an empty release note")
+		}
+	}
+	return parsed.Notes, nil
+}
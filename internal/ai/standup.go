@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// StandupBranch is the work on one branch.
+type StandupBranch struct {
+	Name    string
+	Commits []git.LogEntry
+}
+
+// StandupRepo is the work in one repository, grouped by branch.
+type StandupRepo struct {
+	Info     git.RepoInfo
+	Branches []StandupBranch
+}
+
+// Name is the repository's directory name.
+func (r StandupRepo) Name() string {
+	return filepath.Base(r.Info.Path)
+}
+
+// StandupRequest describes the activity to summarize for a standup.
+type StandupRequest struct {
+	// Period describes the time range in words, e.g. "since yesterday".
+	Period   string
+	Repos    []StandupRepo
+	Markdown bool
+	Language string
+}
+
+// Standup asks the model for a short standup update from commit history.
+// Only commit messages are sent, never diffs.
+func (c *Client) Standup(ctx context.Context, req StandupRequest) (string, error) {
+	if len(req.Repos) == 0 {
+		return "", errors.New("no activity to summarize")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Write a developer's standup update from the git commits below.\n")
+	builder.WriteString("- Group by repository, then by branch or topic; merge related commits into one bullet and describe outcomes, not individual commits.\n")
+	builder.WriteString("- Keep it short enough to read aloud in under a minute. Mention work that looks unfinished (WIP, fixup or draft commits) as in progress.\n")
+	builder.WriteString("- Do not invent work that the commits do not show, and do not add a \"blockers\" or \"plans\" section.\n")
+	if req.Markdown {
+		builder.WriteString("- Format as Markdown with a \"###\" heading per repository and \"-\" bullets.\n")
+	} else {
+		builder.WriteString("- Format as plain text for a chat message: repository names on their own line, \"-\" bullets, no Markdown markup.\n")
+	}
+	builder.WriteString(fmt.Sprintf("- Write in %s.\n", responseLanguage(req.Language).Label()))
+	builder.WriteString(fmt.Sprintf("Period: %s\n", req.Period))
+	builder.WriteString("Commits (newest first):\n---\n")
+	var commits strings.Builder
+	for _, repo := range req.Repos {
+		commits.WriteString(fmt.Sprintf("Repository %s", repo.Name()))
+		if repo.Info.Remote != "" {
+			commits.WriteString(" (" + repo.Info.Remote + ")")
+		}
+		commits.WriteString("\n")
+		for _, branch := range repo.Branches {
+			commits.WriteString("  Branch " + branch.Name + "\n")
+			for _, commit := range branch.Commits {
+				commits.WriteString(fmt.Sprintf("  - %s %s\n", commit.Date.Format("2006-01-02 15:04"), commit.Subject))
+				if commit.Body != "" {
+					commits.WriteString("      " + strings.ReplaceAll(trimText(commit.Body, 300), "\n", "\n      ") + "\n")
+				}
+			}
+		}
+	}
+	builder.WriteString(trimDiff(commits.String()))
+	builder.WriteString("\n---\n")
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.3)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("AI returned an empty standup summary")
+	}
+	return text, nil
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// maxStashMessageLength keeps stash messages readable in "git stash list".
+const maxStashMessageLength = 72
+
+// StashRequest describes uncommitted changes that are about to be stashed.
+type StashRequest struct {
+	Diff         string
+	SkippedFiles []string
+	// Untracked lists new files, which the diff does not show.
+	Untracked []string
+	RepoInfo  git.RepoInfo
+	Language  string
+}
+
+// StashMessage asks the model for a one-line description of the changes
+// so the stash is recognizable later.
+func (c *Client) StashMessage(ctx context.Context, req StashRequest) (string, error) {
+	if strings.TrimSpace(req.Diff) == "" && len(req.Untracked) == 0 && len(req.SkippedFiles) == 0 {
+		return "", errors.New("no changes to describe")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Summarize the uncommitted work-in-progress below as a short git stash message.\n")
+	builder.WriteString(fmt.Sprintf("- One line, at most %d characters, in %s, describing what the work is about (not \"WIP\" or \"changes\").\n", maxStashMessageLength, responseLanguage(req.Language).Label()))
+	builder.WriteString("- No trailing period, no quotes, no Conventional Commits prefix.\n")
+	builder.WriteString(fmt.Sprintf("Branch: %s\n", req.RepoInfo.Branch))
+	if len(req.Untracked) > 0 {
+		builder.WriteString("New untracked files:\n")
+		for _, f := range req.Untracked {
+			builder.WriteString("- " + f + "\n")
+		}
+	}
+	writeSkippedFiles(&builder, req.SkippedFiles)
+	builder.WriteString("Diff against HEAD:\n")
+	builder.WriteString("---\n")
+	builder.WriteString(trimDiff(req.Diff))
+	builder.WriteString("\n---\n")
+	builder.WriteString("Respond ONLY as a single JSON object of the form {\"message\":\"<stash message>\"}, with no code fences.\n")
+
+	text, err := c.generate(ctx, builder.String(), c.maxTokens, 0.2)
+	if err != nil {
+		return "", err
+	}
+
+	clean := extractJSONBlock(text)
+	if strings.TrimSpace(clean) == "" {
+		return "", fmt.Errorf("failed to find JSON object in stash message response: %q", text)
+	}
+	var parsed struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(clean), &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse stash message JSON: %w; raw=%q", err, clean)
+	}
+
+	msg := strings.TrimSpace(strings.SplitN(parsed.Message, "\n", 2)[0])
+	if msg == "" {
+		return "", errors.New("AI returned an empty stash message")
+	}
+	if r := []rune(msg); len(r) > maxStashMessageLength {
+		msg = strings.TrimSpace(string(r[:maxStashMessageLength]))
+	}
+	return msg, nil
+}
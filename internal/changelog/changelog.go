@@ -0,0 +1,362 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vinhtran/git-smart/internal/conventional"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/semver"
+)
+
+// Section is a group of entries in a release.
+type Section string
+
+const (
+	SectionBreaking Section = "Breaking Changes"
+	SectionFeatures Section = "Features"
+	SectionFixes    Section = "Fixes"
+	SectionOther    Section = "Other"
+)
+
+// sectionOrder is the order sections are rendered in.
+var sectionOrder = []Section{SectionBreaking, SectionFeatures, SectionFixes, SectionOther}
+
+// Unreleased is the version label for changes not yet tagged.
+const Unreleased = "Unreleased"
+
+// Entry is one changelog line derived from a commit.
+type Entry struct {
+	Section Section
+	Scope   string
+	// Text is the line shown in the changelog, without scope or hash.
+	Text string
+	// Note is the BREAKING CHANGE footer of a breaking entry, if any.
+	Note   string
+	Commit git.LogEntry
+}
+
+// Release is the changelog section for one version.
+type Release struct {
+	Version string
+	Date    time.Time
+	Entries []Entry
+}
+
+// Options controls which commits become entries.
+type Options struct {
+	// IncludeOther adds commits that are neither features nor fixes
+	// (refactor, docs, non-conventional, ...) under "Other".
+	IncludeOther bool
+}
+
+// Build groups commits into a release. Breaking changes are listed only
+// under Breaking Changes, using the BREAKING CHANGE footer when present.
+// Merge commits are skipped.
+func Build(version string, date time.Time, commits []git.LogEntry, opts Options) Release {
+	release := Release{Version: version, Date: date}
+	// Oldest first reads naturally within a section.
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		if strings.HasPrefix(c.Subject, "Merge ") {
+			continue
+		}
+		header, ok := conventional.ParseHeader(c.Subject)
+		if !ok || !header.Valid() {
+			if opts.IncludeOther {
+				release.Entries = append(release.Entries, Entry{Section: SectionOther, Text: c.Subject, Commit: c})
+			}
+			continue
+		}
+
+		entry := Entry{Scope: header.Scope, Text: header.Description, Commit: c}
+		note, hasNote := conventional.BreakingNote(c.Body)
+		switch {
+		case header.Breaking || hasNote:
+			entry.Section = SectionBreaking
+			entry.Note = note
+		case header.Type == "feat":
+			entry.Section = SectionFeatures
+		case header.Type == "fix":
+			entry.Section = SectionFixes
+		case opts.IncludeOther:
+			entry.Section = SectionOther
+		default:
+			continue
+		}
+		release.Entries = append(release.Entries, entry)
+	}
+	return release
+}
+
+// IsBreaking reports whether the commit header or body marks a breaking change.
+func IsBreaking(c git.LogEntry) bool {
+	header, _ := conventional.ParseHeader(c.Subject)
+	_, hasNote := conventional.BreakingNote(c.Body)
+	return header.Breaking || hasNote
+}
+
+// Markdown renders the release as a Keep a Changelog section.
+func (r Release) Markdown() string {
+	var b strings.Builder
+	if r.Version == Unreleased {
+		b.WriteString("## [Unreleased]\n")
+	} else {
+		b.WriteString(fmt.Sprintf("## [%s] - %s\n", strings.TrimPrefix(r.Version, "v"), r.Date.Format("2006-01-02")))
+	}
+
+	if len(r.Entries) == 0 {
+		b.WriteString("\nNo notable changes.\n")
+		return b.String()
+	}
+	for _, section := range sectionOrder {
+		lines := r.lines(section)
+		if len(lines) == 0 {
+			continue
+		}
+		b.WriteString("\n### " + string(section) + "\n\n")
+		b.WriteString(strings.Join(lines, "\n") + "\n")
+	}
+	return b.String()
+}
+
+// heading returns the release's "## [...]" line.
+func (r Release) heading() string {
+	return strings.SplitN(r.Markdown(), "\n", 2)[0]
+}
+
+// lines renders the entries of one section as Markdown list items.
+func (r Release) lines(section Section) []string {
+	var lines []string
+	for _, e := range r.Entries {
+		if e.Section == section {
+			lines = append(lines, e.line())
+		}
+	}
+	return lines
+}
+
+// line renders the entry as a list item, with its breaking note nested below.
+func (e Entry) line() string {
+	line := "- "
+	if e.Scope != "" {
+		line += "**" + e.Scope + ":** "
+	}
+	line += e.Text
+	if e.Commit.Hash != "" {
+		line += " (" + e.Commit.ShortHash() + ")"
+	}
+	if e.Note != "" {
+		line += "\n  - " + e.Note
+	}
+	return line
+}
+
+// header starts a new CHANGELOG.md.
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+var (
+	releaseHeading = regexp.MustCompile(`(?m)^## \[([^\]]+)\]`)
+	// linkDefinition matches Markdown link reference definitions such as
+	// "[1.2.0]: https://example.com/compare/v1.1.0...v1.2.0".
+	linkDefinition = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+)
+
+// Prepend inserts the release into existing above the first older release.
+// When existing already has a section for the same version, or a pending
+// Unreleased section the release is cut from, the generated entries are
+// merged into it instead, so hand-written notes survive. An empty existing
+// file gets the standard Keep a Changelog preamble.
+func Prepend(existing string, r Release) string {
+	section := strings.TrimRight(r.Markdown(), "\n") + "\n"
+	if strings.TrimSpace(existing) == "" {
+		return header + "\n" + section
+	}
+
+	label := strings.TrimPrefix(r.Version, "v")
+	matches := releaseHeading.FindAllStringSubmatchIndex(existing, -1)
+	if len(matches) == 0 {
+		return strings.TrimRight(existing, "\n") + "\n\n" + section
+	}
+	// sectionEnd returns where the i-th release section ends.
+	sectionEnd := func(i int) int {
+		if i+1 < len(matches) {
+			return matches[i+1][0]
+		}
+		return len(existing)
+	}
+
+	for i, m := range matches {
+		if existing[m[2]:m[3]] == label {
+			return splice(existing, m[0], sectionEnd(i), merge(existing[m[0]:sectionEnd(i)], r))
+		}
+	}
+	// A release takes over the pending Unreleased section it was cut from.
+	if existing[matches[0][2]:matches[0][3]] == Unreleased && r.Version != Unreleased {
+		return splice(existing, matches[0][0], sectionEnd(0), merge(existing[matches[0][0]:sectionEnd(0)], r))
+	}
+	if r.Version != Unreleased {
+		if v, err := semver.Parse(label); err == nil {
+			for _, m := range matches {
+				other, err := semver.Parse(existing[m[2]:m[3]])
+				if err == nil && semver.Compare(other, v) < 0 {
+					return splice(existing, m[0], m[0], section)
+				}
+			}
+			// Older than every release: append at the end.
+			return strings.TrimRight(existing, "\n") + "\n\n" + section
+		}
+	}
+	return splice(existing, matches[0][0], matches[0][0], section)
+}
+
+// noChanges is the placeholder body of a release without entries.
+const noChanges = "No notable changes."
+
+// subsection is a "### Title" block of an existing release section.
+type subsection struct {
+	title string
+	lines []string
+}
+
+// merge adds the release's entries to old, an existing release section, and
+// gives it the release's heading. Entries whose commit is already listed are
+// skipped, and everything else in old is kept as written.
+func merge(old string, r Release) string {
+	body := strings.SplitN(old, "\n", 2)
+	var intro, links []string
+	var subs []subsection
+	if len(body) == 2 {
+		lines := trimBlank(strings.Split(body[1], "\n"))
+		// Link definitions at the end of the file belong after every section.
+		end := len(lines)
+		for end > 0 && (linkDefinition.MatchString(lines[end-1]) || strings.TrimSpace(lines[end-1]) == "") {
+			end--
+		}
+		lines, links = lines[:end], trimBlank(lines[end:])
+		for _, line := range lines {
+			switch {
+			case strings.HasPrefix(line, "### "):
+				subs = append(subs, subsection{title: strings.TrimSpace(line[4:])})
+			case len(subs) > 0:
+				subs[len(subs)-1].lines = append(subs[len(subs)-1].lines, line)
+			case strings.TrimSpace(line) != noChanges:
+				intro = append(intro, line)
+			}
+		}
+	}
+
+	for _, section := range sectionOrder {
+		var added []string
+		for _, e := range r.Entries {
+			if e.Section == section && !listed(old, e) {
+				added = append(added, e.line())
+			}
+		}
+		if len(added) == 0 {
+			continue
+		}
+		i := indexOfSubsection(subs, section)
+		if i < 0 {
+			i = insertSubsection(&subs, section)
+		}
+		subs[i].lines = append(trimBlank(subs[i].lines), added...)
+	}
+
+	var b strings.Builder
+	b.WriteString(r.heading() + "\n")
+	if intro = trimBlank(intro); len(intro) > 0 {
+		b.WriteString("\n" + strings.Join(intro, "\n") + "\n")
+	}
+	for _, sub := range subs {
+		b.WriteString("\n### " + sub.title + "\n")
+		if lines := trimBlank(sub.lines); len(lines) > 0 {
+			b.WriteString("\n" + strings.Join(lines, "\n") + "\n")
+		}
+	}
+	if len(intro) == 0 && len(subs) == 0 {
+		b.WriteString("\n" + noChanges + "\n")
+	}
+	if len(links) > 0 {
+		b.WriteString("\n" + strings.Join(links, "\n") + "\n")
+	}
+	return b.String()
+}
+
+// listed reports whether old already mentions the entry, by its commit hash
+// or, for entries without one, by the exact list item.
+func listed(old string, e Entry) bool {
+	if e.Commit.Hash != "" {
+		return strings.Contains(old, "("+e.Commit.ShortHash()+")")
+	}
+	first := strings.SplitN(e.line(), "\n", 2)[0]
+	for _, line := range strings.Split(old, "\n") {
+		if strings.TrimSpace(line) == first {
+			return true
+		}
+	}
+	return false
+}
+
+// indexOfSubsection returns the index of the subsection titled section, or -1.
+func indexOfSubsection(subs []subsection, section Section) int {
+	for i, sub := range subs {
+		if strings.EqualFold(sub.title, string(section)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertSubsection adds an empty subsection for section before the first
+// one that sectionOrder puts after it, and returns its index.
+func insertSubsection(subs *[]subsection, section Section) int {
+	rank := func(title string) int {
+		for i, s := range sectionOrder {
+			if strings.EqualFold(title, string(s)) {
+				return i
+			}
+		}
+		return -1
+	}
+	at := len(*subs)
+	for i, sub := range *subs {
+		if rank(sub.title) > rank(string(section)) {
+			at = i
+			break
+		}
+	}
+	*subs = append(*subs, subsection{})
+	copy((*subs)[at+1:], (*subs)[at:])
+	(*subs)[at] = subsection{title: string(section)}
+	return at
+}
+
+// trimBlank drops leading and trailing blank lines.
+func trimBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splice replaces existing[start:end] with section, keeping a blank line
+// before whatever follows.
+func splice(existing string, start, end int, section string) string {
+	rest := existing[end:]
+	if rest != "" {
+		section += "\n"
+	}
+	return existing[:start] + section + rest
+}
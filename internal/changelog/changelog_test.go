@@ -0,0 +1,191 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+var day = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func release(version string, entries ...Entry) Release {
+	return Release{Version: version, Date: day, Entries: entries}
+}
+
+func feat(hash, text string) Entry {
+	return Entry{Section: SectionFeatures, Text: text, Commit: git.LogEntry{Hash: hash}}
+}
+
+func fix(hash, text string) Entry {
+	return Entry{Section: SectionFixes, Text: text, Commit: git.LogEntry{Hash: hash}}
+}
+
+func TestBuild(t *testing.T) {
+	// Newest first, as git log returns them.
+	commits := []git.LogEntry{
+		{Hash: "5555555", Subject: "Merge branch 'x'"},
+		{Hash: "4444444", Subject: "docs: explain flags"},
+		{Hash: "3333333", Subject: "feat(api)!: drop v1", Body: "BREAKING CHANGE: v1 clients must upgrade"},
+		{Hash: "2222222", Subject: "fix: handle empty input"},
+		{Hash: "1111111", Subject: "feat: add login"},
+		{Hash: "0000000", Subject: "tweak things"},
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want []Entry
+	}{
+		{"default", Options{}, []Entry{
+			{Section: SectionFeatures, Text: "add login"},
+			{Section: SectionFixes, Text: "handle empty input"},
+			{Section: SectionBreaking, Scope: "api", Text: "drop v1", Note: "v1 clients must upgrade"},
+		}},
+		{"include other", Options{IncludeOther: true}, []Entry{
+			{Section: SectionOther, Text: "tweak things"},
+			{Section: SectionFeatures, Text: "add login"},
+			{Section: SectionFixes, Text: "handle empty input"},
+			{Section: SectionBreaking, Scope: "api", Text: "drop v1", Note: "v1 clients must upgrade"},
+			{Section: SectionOther, Text: "explain flags"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Build("1.0.0", day, commits, tt.opts).Entries
+			if len(got) != len(tt.want) {
+				t.Fatalf("Build returned %d entries, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				g := got[i]
+				if g.Section != want.Section || g.Scope != want.Scope || g.Text != want.Text || g.Note != want.Note {
+					t.Errorf("entry %d = %+v, want %+v", i, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	r := release("v1.2.0",
+		fix("bbbbbbbbbb", "handle empty input"),
+		Entry{Section: SectionBreaking, Scope: "api", Text: "drop v1", Note: "upgrade clients", Commit: git.LogEntry{Hash: "cccccccccc"}},
+		feat("aaaaaaaaaa", "add login"),
+	)
+	want := `## [1.2.0] - 2024-05-01
+
+### Breaking Changes
+
+- **api:** drop v1 (ccccccc)
+  - upgrade clients
+
+### Features
+
+- add login (aaaaaaa)
+
+### Fixes
+
+- handle empty input (bbbbbbb)
+`
+	if got := r.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+	if got := release(Unreleased).Markdown(); got != "## [Unreleased]\n\nNo notable changes.\n" {
+		t.Errorf("empty Markdown() = %q", got)
+	}
+}
+
+const preamble = "# Changelog\n\nNotes.\n\n"
+
+func TestPrepend(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		release  Release
+		want     string
+	}{
+		{
+			name:     "new file",
+			existing: "",
+			release:  release("1.0.0", feat("aaaaaaa", "add login")),
+			want:     header + "\n## [1.0.0] - 2024-05-01\n\n### Features\n\n- add login (aaaaaaa)\n",
+		},
+		{
+			name:     "no releases yet",
+			existing: "# Changelog\n",
+			release:  release("1.0.0", feat("aaaaaaa", "add login")),
+			want:     "# Changelog\n\n## [1.0.0] - 2024-05-01\n\n### Features\n\n- add login (aaaaaaa)\n",
+		},
+		{
+			name:     "newer release goes first",
+			existing: preamble + "## [1.0.0] - 2024-01-01\n\n- old\n",
+			release:  release("v1.1.0", fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [1.1.0] - 2024-05-01\n\n### Fixes\n\n- fix crash (bbbbbbb)\n\n## [1.0.0] - 2024-01-01\n\n- old\n",
+		},
+		{
+			name:     "older release goes in version order",
+			existing: preamble + "## [2.0.0] - 2024-06-01\n\n- two\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+			release:  release("1.5.0", fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [2.0.0] - 2024-06-01\n\n- two\n\n## [1.5.0] - 2024-05-01\n\n### Fixes\n\n- fix crash (bbbbbbb)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+		},
+		{
+			name:     "oldest release is appended",
+			existing: preamble + "## [2.0.0] - 2024-06-01\n\n- two\n",
+			release:  release("1.0.0", fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [2.0.0] - 2024-06-01\n\n- two\n\n## [1.0.0] - 2024-05-01\n\n### Fixes\n\n- fix crash (bbbbbbb)\n",
+		},
+		{
+			name:     "unreleased goes on top",
+			existing: preamble + "## [1.0.0] - 2024-01-01\n\n- one\n",
+			release:  release(Unreleased, feat("aaaaaaa", "add login")),
+			want:     preamble + "## [Unreleased]\n\n### Features\n\n- add login (aaaaaaa)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+		},
+		{
+			name:     "regenerating unreleased keeps curated notes",
+			existing: preamble + "## [Unreleased]\n\nHighlights: faster startup.\n\n### Features\n\n- add login (aaaaaaa)\n- Hand-written note\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+			release:  release(Unreleased, feat("aaaaaaa", "add login again"), feat("ccccccc", "add logout"), fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [Unreleased]\n\nHighlights: faster startup.\n\n### Features\n\n- add login (aaaaaaa)\n- Hand-written note\n- add logout (ccccccc)\n\n### Fixes\n\n- fix crash (bbbbbbb)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+		},
+		{
+			name:     "release takes over unreleased",
+			existing: preamble + "## [Unreleased]\n\n### Added\n\n- Curated entry\n\n### Fixes\n\n- fix crash (bbbbbbb)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+			release:  release("1.1.0", feat("aaaaaaa", "add login"), fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [1.1.0] - 2024-05-01\n\n### Added\n\n- Curated entry\n\n### Features\n\n- add login (aaaaaaa)\n\n### Fixes\n\n- fix crash (bbbbbbb)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+		},
+		{
+			name:     "regenerating a version merges into it",
+			existing: preamble + "## [1.1.0] - 2024-04-01\n\n### Features\n\n- Login, reworded by hand (aaaaaaa)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+			release:  release("v1.1.0", feat("aaaaaaa", "add login"), fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [1.1.0] - 2024-05-01\n\n### Features\n\n- Login, reworded by hand (aaaaaaa)\n\n### Fixes\n\n- fix crash (bbbbbbb)\n\n## [1.0.0] - 2024-01-01\n\n- one\n",
+		},
+		{
+			name:     "placeholder is replaced by entries",
+			existing: preamble + "## [Unreleased]\n\nNo notable changes.\n",
+			release:  release(Unreleased, fix("bbbbbbb", "fix crash")),
+			want:     preamble + "## [Unreleased]\n\n### Fixes\n\n- fix crash (bbbbbbb)\n",
+		},
+		{
+			name:     "nothing new keeps the section",
+			existing: preamble + "## [Unreleased]\n\n- Curated entry\n",
+			release:  release(Unreleased),
+			want:     preamble + "## [Unreleased]\n\n- Curated entry\n",
+		},
+		{
+			name:     "link definitions stay at the end",
+			existing: preamble + "## [1.0.0] - 2024-01-01\n\n### Features\n\n- one (aaaaaaa)\n\n[1.0.0]: https://example.com/releases/v1.0.0\n",
+			release:  release("1.0.0", feat("aaaaaaa", "one"), feat("ddddddd", "two")),
+			want:     preamble + "## [1.0.0] - 2024-05-01\n\n### Features\n\n- one (aaaaaaa)\n- two (ddddddd)\n\n[1.0.0]: https://example.com/releases/v1.0.0\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Prepend(tt.existing, tt.release)
+			if got != tt.want {
+				t.Errorf("Prepend() =\n%s\nwant\n%s", got, tt.want)
+			}
+			// Prepending the same release again must not change anything.
+			if again := Prepend(got, tt.release); again != got {
+				t.Errorf("Prepend() is not idempotent:\n%s", again)
+			}
+		})
+	}
+}
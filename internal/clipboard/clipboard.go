@@ -0,0 +1,57 @@
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard tool is installed.
+var ErrUnavailable = errors.New("no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+
+// commands lists candidate clipboard writers in order of preference.
+func commands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	return append(cmds,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+		// WSL exposes the Windows clipboard.
+		[]string{"clip.exe"},
+	)
+}
+
+// Copy writes text to the clipboard. SMARTGIT_CLIPBOARD may name a custom
+// command (run through sh -c) that reads the text from stdin.
+func Copy(ctx context.Context, text string) error {
+	if custom := strings.TrimSpace(os.Getenv("SMARTGIT_CLIPBOARD")); custom != "" {
+		return run(ctx, []string{"sh", "-c", custom}, text)
+	}
+	for _, args := range commands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		return run(ctx, args, text)
+	}
+	return ErrUnavailable
+}
+
+func run(ctx context.Context, args []string, text string) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.New(strings.TrimSpace(args[0] + ": " + err.Error() + " " + string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/filter"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/repocontext"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+// filterDiffForAI drops ignored, generated and binary files from diff and
+// returns the remaining files plus one-line summaries of what was dropped.
+func filterDiffForAI(ctx context.Context, dir string, diff *git.Diff) (*git.Diff, []string, error) {
+	log := logger.L().With("path", dir)
+
+	root, err := git.TopLevel(ctx, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matcher, err := filter.Load(root, cfg.Ignore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept, skipped := filter.Apply(ctx, root, diff, matcher)
+	for _, s := range skipped {
+		log.InfoContext(ctx, "Summarizing file instead of sending its diff", "file", s.Path, "reason", s.Reason)
+	}
+	return kept, filter.Summaries(skipped), nil
+}
+
+// secretsOutsidePrompt scans the lines that diff adds to files
+// filterDiffForAI left out of prompt for credentials and personal data,
+// so ignoring a file never hides it from the privacy check. It returns one
+// "path: kinds" entry per affected file.
+func secretsOutsidePrompt(diff, prompt *git.Diff) []string {
+	sent := map[string]bool{}
+	for _, f := range prompt.Files {
+		sent[f.Path()] = true
+	}
+
+	var found []string
+	for _, f := range diff.Files {
+		if sent[f.Path()] {
+			continue
+		}
+		var added strings.Builder
+		for _, h := range f.Hunks {
+			for _, l := range h.Lines {
+				if l.Kind == git.LineAdded {
+					added.WriteString(l.Content)
+					added.WriteString("\n")
+				}
+			}
+		}
+		if kinds := ai.FindSecrets(added.String()); len(kinds) > 0 {
+			found = append(found, fmt.Sprintf("%s: %s", f.Path(), strings.Join(kinds, ", ")))
+		}
+	}
+	return found
+}
+
+// parseContextOptions turns --context values into repocontext options.
+func parseContextOptions(values []string, budget int) (repocontext.Options, error) {
+	out := repocontext.Options{BudgetTokens: budget}
+	for _, v := range values {
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "", "none":
+		case "functions", "funcs":
+			if out.Mode == repocontext.ModeNone {
+				out.Mode = repocontext.ModeFunctions
+			}
+		case "files", "full":
+			out.Mode = repocontext.ModeFiles
+		case "readme":
+			out.Readme = true
+		case "history", "log":
+			out.History = true
+		default:
+			return out, fmt.Errorf("invalid --context value %q (expected functions, files, readme, history or none)", v)
+		}
+	}
+	if out.Enabled() && budget <= 0 {
+		return out, fmt.Errorf("--context-budget must be positive, got %d", budget)
+	}
+	return out, nil
+}
+
+// buildReviewContext gathers the requested repository context for the
+// (already filtered) diff of target.
+func buildReviewContext(ctx context.Context, dir string, target reviewTarget, opts repocontext.Options) (repocontext.Bundle, error) {
+	root, err := git.TopLevel(ctx, dir)
+	if err != nil {
+		return repocontext.Bundle{}, err
+	}
+
+	src := target.source
+	src.Root = root
+	return repocontext.Build(ctx, src, target.diff, opts)
+}
+
+// excludedFromAI reports whether path (relative to the repository root)
+// must not be sent to AI, with the reason.
+func excludedFromAI(root, path string) (bool, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return false, "", err
+	}
+	matcher, err := filter.Load(root, cfg.Ignore)
+	if err != nil {
+		return false, "", err
+	}
+	excluded, reason := matcher.Match(path)
+	return excluded, reason, nil
+}
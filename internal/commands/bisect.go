@@ -0,0 +1,329 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+// maxListedSuspects caps how many suspect commits are printed per step.
+const maxListedSuspects = 10
+
+// errBisectStopped is returned when the user quits the bisect.
+var errBisectStopped = errors.New("bisect stopped")
+
+type bisectOptions struct {
+	good      string
+	bad       string
+	run       string
+	noAI      bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+var (
+	bisectCmd = &cobra.Command{
+		Use:   "bisect <symptom>",
+		Short: "Find the commit that introduced a bug with git bisect",
+		Long: `Find the commit that introduced a bug with git bisect.
+
+Describe the symptom and give a revision where it did not happen yet
+(--good, default: the latest tag). sg drives git bisect between it and
+--bad (default: HEAD). Each commit is judged by --run, a shell command
+that exits 0 when the commit is good, 125 to skip it and 1-127 when it is
+bad (as with "git bisect run"), or by asking you.
+
+After every step the remaining suspect commits are listed, with a short
+AI note on which look related to the symptom. Once the first bad commit
+is found, its diff is explained in relation to the symptom. The bisect is
+always reset afterwards, returning you to your branch.`,
+		Example: `  sg bisect "login returns 500" --good v1.4.0
+  sg bisect "TestParse fails" --good v1.4.0 --run "go test ./internal/parser -run TestParse"
+  sg bisect "page renders blank" --good 1a2b3c4 --bad origin/main --no-ai`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBisect,
+	}
+	bisectOpts bisectOptions
+)
+
+func init() {
+	rootCmd.AddCommand(bisectCmd)
+
+	bisectCmd.Flags().StringVar(&bisectOpts.good, "good", "", "Revision known to be good (default: the latest tag)")
+	bisectCmd.Flags().StringVar(&bisectOpts.bad, "bad", "HEAD", "Revision known to be bad")
+	bisectCmd.Flags().StringVar(&bisectOpts.run, "run", "", "Shell command that judges each commit (exit 0 good, 125 skip, 1-127 bad)")
+	bisectCmd.Flags().BoolVar(&bisectOpts.noAI, "no-ai", false, "Do not use AI for step summaries or the final explanation")
+	bisectCmd.Flags().IntVar(&bisectOpts.maxTokens, "max-tokens", 1024, "Maximum tokens for AI output")
+	bisectCmd.Flags().DurationVar(&bisectOpts.timeout, "timeout", time.Hour, "Timeout for the whole bisect")
+}
+
+func runBisect(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), bisectOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "bisect", "path", wd)
+
+	symptom := strings.TrimSpace(args[0])
+	if symptom == "" {
+		return errors.New("describe the symptom, e.g. sg bisect \"login returns 500\"")
+	}
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+	if running, err := git.BisectInProgress(ctx, wd); err != nil {
+		return err
+	} else if running {
+		return errors.New("a bisect is already in progress; finish it or run 'git bisect reset' first")
+	}
+	if op, err := git.InProgress(ctx, wd); err != nil {
+		return err
+	} else if op != git.OperationNone {
+		return fmt.Errorf("a %s is in progress; finish it or run 'sg resolve --abort' first", op)
+	}
+	status, err := git.StatusPorcelain(ctx, wd)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(status, "\n") {
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "??") {
+			return errors.New("bisect checks out other commits; commit or stash your changes first")
+		}
+	}
+
+	good := bisectOpts.good
+	if good == "" {
+		if good, err = git.LatestTag(ctx, wd, bisectOpts.bad); err != nil || good == "" {
+			return errors.New("no tag to start from; pass --good <revision> where the symptom did not occur")
+		}
+		fmt.Printf("Using the latest tag %s as the good revision.\n", good)
+	}
+	goodHash, err := git.RevParse(ctx, wd, good)
+	if err != nil {
+		return err
+	}
+	badHash, err := git.RevParse(ctx, wd, bisectOpts.bad)
+	if err != nil {
+		return err
+	}
+
+	var client *ai.Client
+	if !bisectOpts.noAI {
+		// Ask for a missing API key now rather than in the middle of the bisect.
+		if client, err = newAIClient(ctx, wd, bisectOpts.maxTokens); err != nil {
+			return err
+		}
+	}
+
+	log.InfoContext(ctx, "Starting bisect", "good", goodHash, "bad", badHash, "run", bisectOpts.run)
+	state, err := git.BisectStart(ctx, wd, badHash, goodHash)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Reset even when the command timed out or failed.
+		resetCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := git.BisectReset(resetCtx, wd); err != nil {
+			log.WarnContext(ctx, "Failed to reset bisect", "error", err)
+			fmt.Println("Could not end the bisect; run 'git bisect reset' to return to your branch.")
+			return
+		}
+		fmt.Println("Bisect reset; you are back where you started.")
+	}()
+
+	reader := bufio.NewReader(os.Stdin)
+	for step := 1; !state.Done; step++ {
+		printBisectStep(ctx, wd, client, symptom, step, state)
+
+		current, err := git.CommitLog(ctx, wd, "-1", state.Current)
+		if err != nil {
+			return err
+		}
+		if len(current) > 0 {
+			fmt.Printf("Testing %s %s\n", current[0].ShortHash(), current[0].Subject)
+		}
+
+		var verdict git.Verdict
+		if bisectOpts.run != "" {
+			verdict, err = runBisectTest(ctx, wd, bisectOpts.run)
+		} else {
+			verdict, err = askBisectVerdict(reader, symptom)
+		}
+		if errors.Is(err, errBisectStopped) {
+			recordAction(ctx, wd, "sg bisect", journal.Entry{Action: journal.ActionBisect, Target: symptom, Outcome: journal.OutcomeCancelled}, nil)
+			fmt.Println("Bisect stopped.")
+			return nil
+		}
+		if err != nil {
+			recordAction(ctx, wd, "sg bisect", journal.Entry{Action: journal.ActionBisect, Target: symptom}, err)
+			return err
+		}
+		log.InfoContext(ctx, "Marking commit", "commit", state.Current, "verdict", verdict)
+		if state, err = git.BisectMark(ctx, wd, verdict); err != nil {
+			return err
+		}
+	}
+
+	if state.Culprit == "" {
+		fmt.Println("Only skipped commits are left; the first bad commit is one of:")
+		for _, hash := range state.Candidates {
+			if commits, err := git.CommitLog(ctx, wd, "-1", hash); err == nil && len(commits) > 0 {
+				fmt.Printf("  %s %s\n", commits[0].ShortHash(), commits[0].Subject)
+			}
+		}
+		recordAction(ctx, wd, "sg bisect", journal.Entry{Action: journal.ActionBisect, Target: symptom, Outcome: journal.OutcomeCancelled}, nil)
+		return nil
+	}
+
+	recordAction(ctx, wd, "sg bisect", journal.Entry{Action: journal.ActionBisect, Commit: state.Culprit, Target: symptom}, nil)
+	return explainBisectCulprit(ctx, wd, client, symptom, state.Culprit)
+}
+
+// printBisectStep shows the commits that are still suspect.
+func printBisectStep(ctx context.Context, dir string, client *ai.Client, symptom string, step int, state git.BisectState) {
+	suspects, err := git.BisectSuspects(ctx, dir)
+	if err != nil {
+		logger.L().WarnContext(ctx, "Failed to list suspect commits", "path", dir, "error", err)
+		return
+	}
+	fmt.Printf("\n%sStep %d:%s %d suspect commit(s), about %d more step(s) after this one.\n", colorCyan, step, colorReset, len(suspects), state.Steps)
+	for i, c := range suspects {
+		if i == maxListedSuspects {
+			fmt.Printf("  ... and %d more\n", len(suspects)-maxListedSuspects)
+			break
+		}
+		fmt.Printf("  %s %s (%s)\n", c.ShortHash(), c.Subject, c.Author)
+	}
+	if client == nil {
+		return
+	}
+	summary, err := client.SummarizeSuspects(ctx, ai.BisectSuspectsRequest{Symptom: symptom, Suspects: suspects, Language: language.Tag})
+	if err != nil {
+		logger.L().WarnContext(ctx, "AI suspect summary failed", "path", dir, "error", err)
+		return
+	}
+	fmt.Println(summary)
+}
+
+// runBisectTest runs the test command on the checked-out commit and maps
+// its exit status the way "git bisect run" does.
+func runBisectTest(ctx context.Context, dir, command string) (git.Verdict, error) {
+	shell := strings.TrimSpace(os.Getenv("SHELL"))
+	if shell == "" {
+		shell = "sh"
+	}
+	fmt.Printf("Running: %s\n", command)
+	execCmd := exec.CommandContext(ctx, shell, "-c", command)
+	execCmd.Dir = dir
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+
+	err := execCmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		fmt.Println("Exit status 0: good.")
+		return git.VerdictGood, nil
+	case errors.As(err, &exitErr):
+		code := exitErr.ExitCode()
+		switch {
+		case code == 125:
+			fmt.Println("Exit status 125: skipping this commit.")
+			return git.VerdictSkip, nil
+		case code > 0 && code < 128:
+			fmt.Printf("Exit status %d: bad.\n", code)
+			return git.VerdictBad, nil
+		}
+		return "", fmt.Errorf("test command exited with status %d; stopping the bisect", code)
+	default:
+		return "", fmt.Errorf("failed to run the test command: %w", err)
+	}
+}
+
+// askBisectVerdict asks whether the checked-out commit shows the symptom.
+func askBisectVerdict(reader *bufio.Reader, symptom string) (git.Verdict, error) {
+	for {
+		fmt.Print(i18n.T(i18n.MsgBisectVerdict, symptom))
+		answer, err := reader.ReadString('\n')
+		if i18n.IsYes(answer) {
+			return git.VerdictBad, nil
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "b", "bad":
+			return git.VerdictBad, nil
+		case "g", "good", "n", "no":
+			return git.VerdictGood, nil
+		case "s", "skip":
+			return git.VerdictSkip, nil
+		case "q", "quit":
+			return "", errBisectStopped
+		}
+		if err != nil {
+			return "", errBisectStopped
+		}
+	}
+}
+
+func explainBisectCulprit(ctx context.Context, dir string, client *ai.Client, symptom, hash string) error {
+	commits, err := git.CommitLog(ctx, dir, "-1", hash)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("commit %s not found", hash)
+	}
+	commit := commits[0]
+	fmt.Printf("\n%sFirst bad commit:%s %s %s\n", colorGreen, colorReset, commit.ShortHash(), commit.Subject)
+	fmt.Printf("  %s, %s (%s)\n", commit.Author, commit.Date.Format("2006-01-02"), relativeTime(commit.Date))
+	if client == nil {
+		fmt.Printf("Inspect it with: git show %s\n", commit.ShortHash())
+		return nil
+	}
+
+	diff, err := git.LoadCommitDiff(ctx, dir, hash)
+	if err != nil {
+		return err
+	}
+	diff, skipped, err := filterDiffForAI(ctx, dir, diff)
+	if err != nil {
+		return err
+	}
+	repoInfo, err := git.GetRepoInfo(ctx, dir)
+	if err != nil {
+		return err
+	}
+	text, err := client.ExplainCulprit(ctx, ai.CulpritRequest{
+		Symptom:      symptom,
+		Commit:       commit,
+		Diff:         diff.String(),
+		SkippedFiles: skipped,
+		RepoInfo:     repoInfo,
+		Language:     language.Tag,
+	})
+	if err != nil {
+		return err
+	}
+	divider := strings.Repeat("-", 60)
+	fmt.Println(divider)
+	fmt.Println(text)
+	fmt.Println(divider)
+	return nil
+}
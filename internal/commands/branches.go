@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+type branchesPruneOptions struct {
+	remote         string
+	base           string
+	remoteBranches bool
+	dryRun         bool
+	yes            bool
+	timeout        time.Duration
+}
+
+var (
+	branchesCmd = &cobra.Command{
+		Use:   "branches",
+		Short: "Branch housekeeping",
+	}
+	branchesPruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Delete local branches that are merged or whose upstream is gone",
+		Long: `Delete local branches that are merged or whose upstream is gone.
+
+After "git fetch --prune", local branches are listed when they are merged
+into the default branch or their upstream was deleted on the remote (for
+example after a squash merge). Pick which ones to delete; protected
+branches and the current branch are never listed.
+
+With --remote-branches, merged branches on the remote whose last commit
+you authored (matching user.email) are offered for deletion too.`,
+		Example: `  sg branches prune
+  sg branches prune --dry-run
+  sg branches prune --remote-branches
+  sg branches prune --base develop -y`,
+		Args: cobra.NoArgs,
+		RunE: runBranchesPrune,
+	}
+	branchesPruneOpts branchesPruneOptions
+)
+
+func init() {
+	rootCmd.AddCommand(branchesCmd)
+	branchesCmd.AddCommand(branchesPruneCmd)
+
+	branchesPruneCmd.Flags().StringVar(&branchesPruneOpts.remote, "remote", "origin", "Remote to prune and compare against")
+	branchesPruneCmd.Flags().StringVar(&branchesPruneOpts.base, "base", "", "Branch that merged work lands in (default: the remote's default branch)")
+	branchesPruneCmd.Flags().BoolVar(&branchesPruneOpts.remoteBranches, "remote-branches", false, "Also offer to delete your merged branches on the remote")
+	branchesPruneCmd.Flags().BoolVar(&branchesPruneOpts.dryRun, "dry-run", false, "Only list the branches that could be deleted")
+	branchesPruneCmd.Flags().BoolVarP(&branchesPruneOpts.yes, "yes", "y", false, "Delete every merged branch without asking (branches with unmerged commits are kept)")
+	branchesPruneCmd.Flags().DurationVar(&branchesPruneOpts.timeout, "timeout", 2*time.Minute, "Timeout for the whole prune")
+}
+
+// pruneCandidate is a branch offered for deletion.
+type pruneCandidate struct {
+	branch git.Branch
+	// name is the branch name without the remote prefix.
+	name   string
+	remote bool
+	merged bool
+	reason string
+}
+
+func runBranchesPrune(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), branchesPruneOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "branches prune", "path", wd)
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+
+	remote := branchesPruneOpts.remote
+	log.InfoContext(ctx, "Fetching and pruning remote-tracking branches", "remote", remote)
+	if err := git.FetchPrune(ctx, wd, remote); err != nil {
+		return err
+	}
+
+	base := branchesPruneOpts.base
+	if base == "" {
+		if base, err = git.DefaultBranch(ctx, wd, remote); err != nil {
+			return fmt.Errorf("%w; pass --base", err)
+		}
+	}
+	// Prefer the remote's view of the base: local copies are often stale.
+	baseRef := remote + "/" + base
+	merged, err := git.MergedInto(ctx, wd, baseRef)
+	if err != nil {
+		baseRef = base
+		if merged, err = git.MergedInto(ctx, wd, baseRef); err != nil {
+			return err
+		}
+	}
+
+	branches, err := git.ListBranches(ctx, wd)
+	if err != nil {
+		return err
+	}
+	candidates := pruneCandidates(ctx, wd, branches, merged, remote, base, baseRef)
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to prune.")
+		return nil
+	}
+
+	printPruneCandidates(candidates)
+	if branchesPruneOpts.dryRun {
+		return nil
+	}
+
+	var selected []pruneCandidate
+	if branchesPruneOpts.yes {
+		for _, c := range candidates {
+			if c.merged {
+				selected = append(selected, c)
+			}
+		}
+	} else {
+		fmt.Print(i18n.T(i18n.MsgPruneSelect))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		indexes, err := parseSelection(answer, len(candidates))
+		if err != nil {
+			return err
+		}
+		for _, i := range indexes {
+			selected = append(selected, candidates[i])
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println(i18n.T(i18n.MsgNoBranchesDeleted))
+		return nil
+	}
+
+	deleted := 0
+	for _, c := range selected {
+		if c.remote {
+			log.InfoContext(ctx, "Deleting remote branch", "remote", remote, "branch", c.name)
+			err := git.DeleteRemoteBranch(ctx, wd, remote, c.name)
+			recordAction(ctx, wd, "sg branches prune", journal.Entry{Action: journal.ActionBranchDelete, Branch: c.name, Commit: c.branch.Hash, Target: remote}, err)
+			if err != nil {
+				fmt.Printf("Could not delete %s: %v\n", c.branch.Name, err)
+				continue
+			}
+		} else {
+			log.InfoContext(ctx, "Deleting local branch", "branch", c.name, "merged", c.merged)
+			// Merge status was checked against the base branch above, which
+			// "git branch -d" does not know about.
+			err := git.DeleteBranch(ctx, wd, c.name, true)
+			recordAction(ctx, wd, "sg branches prune", journal.Entry{Action: journal.ActionBranchDelete, Branch: c.name, Commit: c.branch.Hash}, err)
+			if err != nil {
+				fmt.Printf("Could not delete %s: %v\n", c.name, err)
+				continue
+			}
+		}
+		deleted++
+		restore := fmt.Sprintf("git branch %s %s", c.name, c.branch.Hash)
+		if c.remote {
+			restore = fmt.Sprintf("git push %s %s:refs/heads/%s", remote, c.branch.Hash, c.name)
+		}
+		fmt.Printf("Deleted %s (was %s; restore with: %s)\n", c.branch.Name, shortHash(c.branch.Hash), restore)
+	}
+	fmt.Printf("Deleted %d of %d selected branches.\n", deleted, len(selected))
+	return nil
+}
+
+// pruneCandidates picks local branches that are merged into baseRef or
+// whose upstream is gone, plus (with --remote-branches) the user's merged
+// branches on remote.
+func pruneCandidates(ctx context.Context, dir string, branches []git.Branch, merged map[string]bool, remote, base, baseRef string) []pruneCandidate {
+	var out []pruneCandidate
+	for _, b := range branches {
+		if b.Remote || b.Current || b.Name == base || isProtectedBranch(b.Name) {
+			continue
+		}
+		switch {
+		case merged[b.Name]:
+			out = append(out, pruneCandidate{branch: b, name: b.Name, merged: true, reason: "merged into " + baseRef})
+		case b.Gone:
+			out = append(out, pruneCandidate{branch: b, name: b.Name, reason: "upstream gone, has unmerged commits"})
+		}
+	}
+
+	if !branchesPruneOpts.remoteBranches {
+		return out
+	}
+	me := git.UserEmail(ctx, dir)
+	if me == "" {
+		logger.L().WarnContext(ctx, "user.email is not set; not offering remote branches", "path", dir)
+		return out
+	}
+	prefix := remote + "/"
+	for _, b := range branches {
+		name := strings.TrimPrefix(b.Name, prefix)
+		if !b.Remote || !strings.HasPrefix(b.Name, prefix) || name == base || isProtectedBranch(name) {
+			continue
+		}
+		if !merged[b.Name] || !strings.EqualFold(b.AuthorEmail, me) {
+			continue
+		}
+		out = append(out, pruneCandidate{branch: b, name: name, remote: true, merged: true, reason: "merged into " + baseRef + ", on " + remote})
+	}
+	return out
+}
+
+func printPruneCandidates(candidates []pruneCandidate) {
+	width := 0
+	for _, c := range candidates {
+		width = max(width, len(c.branch.Name))
+	}
+	for i, c := range candidates {
+		fmt.Printf("%3d) %-*s  %-14s %-20s %s\n", i+1, width, c.branch.Name, relativeTime(c.branch.Date), c.branch.Author, c.reason)
+	}
+}
+
+// parseSelection turns "1 3-5", "1,2" or "all" into zero-based indexes
+// below n. An empty answer or "none" selects nothing.
+func parseSelection(input string, n int) ([]int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	switch input {
+	case "", "none", "n":
+		return nil, nil
+	case "all", "a":
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	seen := make(map[int]bool)
+	var out []int
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(field, "-")
+		from, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("invalid selection %q", field)
+			}
+		}
+		if from < 1 || to > n || from > to {
+			return nil, fmt.Errorf("selection %q is out of range 1-%d", field, n)
+		}
+		for i := from - 1; i < to; i++ {
+			if !seen[i] {
+				seen[i] = true
+				out = append(out, i)
+			}
+		}
+	}
+	return out, nil
+}
+
+func shortHash(hash string) string {
+	return git.LogEntry{Hash: hash}.ShortHash()
+}
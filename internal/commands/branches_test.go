@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input   string
+		n       int
+		want    []int
+		wantErr bool
+	}{
+		{"", 3, nil, false},
+		{"  none ", 3, nil, false},
+		{"N", 3, nil, false},
+		{"all", 3, []int{0, 1, 2}, false},
+		{"A", 2, []int{0, 1}, false},
+		{"all", 0, []int{}, false},
+		{"2", 3, []int{1}, false},
+		{"1 3", 3, []int{0, 2}, false},
+		{"3,1", 3, []int{2, 0}, false},
+		{"1, 2", 3, []int{0, 1}, false},
+		{"2-4", 5, []int{1, 2, 3}, false},
+		{"1 2-3 2 3-3", 4, []int{0, 1, 2}, false},
+		{"5-5", 5, []int{4}, false},
+		{"0", 3, nil, true},
+		{"4", 3, nil, true},
+		{"2-4", 3, nil, true},
+		{"3-1", 3, nil, true},
+		{"x", 3, nil, true},
+		{"1-", 3, nil, true},
+		{"-2", 3, nil, true},
+		{"1 - 3", 3, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSelection(tt.input, tt.n)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSelection(%q, %d) error = %v, wantErr %v", tt.input, tt.n, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSelection(%q, %d) = %v, want %v", tt.input, tt.n, got, tt.want)
+		}
+	}
+}
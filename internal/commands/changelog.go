@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/changelog"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+type changelogOptions struct {
+	from      string
+	to        string
+	version   string
+	output    string
+	stdout    bool
+	all       bool
+	useAI     bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+var (
+	changelogCmd = &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate CHANGELOG.md entries from Conventional Commits",
+		Long: `Generate a Keep a Changelog section from the Conventional Commits between
+two revisions and prepend it to CHANGELOG.md.
+
+Commits are grouped into Breaking Changes ("!" or a BREAKING CHANGE footer),
+Features (feat) and Fixes (fix). Use --all to list other commits under
+Other, and --ai to rewrite terse headers into user-facing release notes.`,
+		Example: `  sg changelog --from v0.2.0
+  sg changelog --from v0.2.0 --to v0.3.0
+  sg changelog --version 0.3.0 --ai
+  sg changelog --stdout`,
+		Args: cobra.NoArgs,
+		RunE: runChangelog,
+	}
+	changelogOpts changelogOptions
+)
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	changelogCmd.Flags().StringVar(&changelogOpts.from, "from", "", "Start revision, exclusive (default: the latest tag before --to)")
+	changelogCmd.Flags().StringVar(&changelogOpts.to, "to", "HEAD", "End revision, inclusive")
+	changelogCmd.Flags().StringVar(&changelogOpts.version, "version", "", "Version heading (default: --to when it is a tag, otherwise Unreleased)")
+	changelogCmd.Flags().StringVar(&changelogOpts.output, "output", "CHANGELOG.md", "Changelog file, relative to the repository root")
+	changelogCmd.Flags().BoolVar(&changelogOpts.stdout, "stdout", false, "Print the section instead of writing the changelog file")
+	changelogCmd.Flags().BoolVar(&changelogOpts.all, "all", false, "Include commits other than features, fixes and breaking changes")
+	changelogCmd.Flags().BoolVar(&changelogOpts.useAI, "ai", false, "Rewrite entries into user-facing release notes with AI")
+	changelogCmd.Flags().IntVar(&changelogOpts.maxTokens, "max-tokens", 1024, "Maximum tokens for the AI rewrite")
+	changelogCmd.Flags().DurationVar(&changelogOpts.timeout, "timeout", 60*time.Second, "Timeout for the changelog generation")
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), changelogOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "changelog", "path", wd)
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+
+	release, err := buildRelease(ctx, wd, changelogOpts.from, changelogOpts.to, changelogOpts.version, changelogOpts.all)
+	if err != nil {
+		return err
+	}
+	log.InfoContext(ctx, "Collected changelog entries", "version", release.Version, "entries", len(release.Entries))
+
+	if changelogOpts.useAI && len(release.Entries) > 0 {
+		if err := rewriteReleaseNotes(ctx, wd, &release, changelogOpts.maxTokens); err != nil {
+			return err
+		}
+	}
+
+	if changelogOpts.stdout {
+		fmt.Print(release.Markdown())
+		return nil
+	}
+
+	path, err := writeChangelog(ctx, wd, changelogOpts.output, release)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s for %s (changelog entries: %d).\n", path, release.Version, len(release.Entries))
+	return nil
+}
+
+// buildRelease collects the commits in from..to and groups them. An empty
+// from selects the latest tag before to, or the whole history.
+func buildRelease(ctx context.Context, dir, from, to, version string, all bool) (changelog.Release, error) {
+	if from == "" {
+		tag, err := previousTag(ctx, dir, to)
+		if err != nil {
+			return changelog.Release{}, err
+		}
+		from = tag
+	}
+
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	commits, err := git.CommitLog(ctx, dir, revRange)
+	if err != nil {
+		return changelog.Release{}, err
+	}
+
+	date := time.Now()
+	if version == "" {
+		version = changelog.Unreleased
+		if git.IsTag(ctx, dir, to) {
+			version = to
+			if len(commits) > 0 {
+				date = commits[0].Date
+			}
+		}
+	}
+	return changelog.Build(version, date, commits, changelog.Options{IncludeOther: all}), nil
+}
+
+// previousTag returns the latest tag before rev, skipping rev itself when it
+// is a tag, so "--to v0.3.0" covers the commits since the tag before it.
+func previousTag(ctx context.Context, dir, rev string) (string, error) {
+	if !git.IsTag(ctx, dir, rev) {
+		return git.LatestTag(ctx, dir, rev)
+	}
+	if _, err := git.Run(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^"); err != nil {
+		// The tag is on the root commit.
+		return "", nil
+	}
+	return git.LatestTag(ctx, dir, rev+"^")
+}
+
+// rewriteReleaseNotes replaces entry texts with AI-written release notes.
+func rewriteReleaseNotes(ctx context.Context, dir string, release *changelog.Release, maxTokens int) error {
+	client, err := newAIClient(ctx, dir, maxTokens)
+	if err != nil {
+		return err
+	}
+	repoInfo, err := git.GetRepoInfo(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	notes := make([]ai.ReleaseNote, 0, len(release.Entries))
+	for _, e := range release.Entries {
+		notes = append(notes, ai.ReleaseNote{Section: string(e.Section), Scope: e.Scope, Text: e.Text})
+	}
+	rewritten, err := client.RewriteReleaseNotes(ctx, ai.ReleaseNotesRequest{
+		Notes:    notes,
+		RepoInfo: repoInfo,
+		Language: language.Tag,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rewrite release notes: %w", err)
+	}
+	for i := range release.Entries {
+		release.Entries[i].Text = rewritten[i]
+	}
+	return nil
+}
+
+// writeChangelog prepends release to the changelog file at the repository
+// root and returns its path.
+func writeChangelog(ctx context.Context, dir, output string, release changelog.Release) (string, error) {
+	root, err := git.TopLevel(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	path := output
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, output)
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(changelog.Prepend(string(existing), release)), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -14,6 +14,8 @@ import (
 
 	"github.com/vinhtran/git-smart/internal/ai"
 	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
 	"github.com/vinhtran/git-smart/pkg/logger"
 )
 
@@ -81,13 +83,11 @@ func runCommandSuggest(cmd *cobra.Command, args []string) error {
 		sysCtx.Repo = repoInfo
 	}
 
-	apiKey, err := resolveAPIKey(ctx)
+	client, err := newAIClient(ctx, wd, commandSuggestOpts.maxTokens)
 	if err != nil {
 		return err
 	}
 
-	client := ai.NewClient(apiKey, commandSuggestOpts.maxTokens)
-
 	log.InfoContext(ctx, "Requesting Gemini command suggestions")
 	suggestions, err := client.SuggestCommands(ctx, message, sysCtx)
 	if err != nil {
@@ -96,7 +96,7 @@ func runCommandSuggest(cmd *cobra.Command, args []string) error {
 
 	if commandSuggestOpts.dryRun {
 		renderSuggestions(message, suggestions)
-		fmt.Println("Dry run mode: no commands will be executed.")
+		fmt.Println(i18n.T(i18n.MsgDryRun))
 		return nil
 	}
 
@@ -109,7 +109,7 @@ func runCommandSuggest(cmd *cobra.Command, args []string) error {
 
 	selected, ok := chooseSuggestionInteractive(message, suggestions)
 	if !ok {
-		fmt.Println("Cancelled. No command was executed.")
+		fmt.Println(i18n.T(i18n.MsgCommandCancelled))
 		return nil
 	}
 	selected.Risk = normalizeRisk(selected.Command, selected.Risk)
@@ -217,23 +217,28 @@ func runSuggestedCommand(ctx context.Context, suggestion ai.SuggestedCommand) er
 		return fmt.Errorf("no valid command to execute")
 	}
 
-	fmt.Printf("About to execute: %s\n", cmdStr)
+	fmt.Println(i18n.T(i18n.MsgAboutToExecute, cmdStr))
+
+	wd, _ := os.Getwd()
+	entry := journal.Entry{Action: journal.ActionShell, Shell: cmdStr}
 
 	switch suggestion.Risk {
 	case ai.RiskLevelHigh:
-		fmt.Println("WARNING: this command is considered HIGH RISK.")
-		fmt.Print("Type \"yes\" to confirm execution, or press Enter to cancel: ")
+		fmt.Println(i18n.T(i18n.MsgHighRiskWarning))
+		fmt.Print(i18n.T(i18n.MsgHighRiskConfirm, i18n.T(i18n.MsgConfirmWord)))
 		reader := bufio.NewReader(os.Stdin)
 		line, _ := reader.ReadString('\n')
-		if strings.ToLower(strings.TrimSpace(line)) != "yes" {
-			fmt.Println("Cancelled due to high risk.")
+		if !i18n.IsConfirmWord(line) {
+			fmt.Println(i18n.T(i18n.MsgHighRiskCancelled))
+			entry.Outcome = journal.OutcomeCancelled
+			recordAction(ctx, wd, "sg cmd", entry, nil)
 			return nil
 		}
 	case ai.RiskLevelMedium:
-		fmt.Println("Note: this command has MEDIUM RISK (it may change system state).")
+		fmt.Println(i18n.T(i18n.MsgMediumRiskNote))
 	}
 
-	fmt.Printf("Running: %s\n", cmdStr)
+	fmt.Println(i18n.T(i18n.MsgRunning, cmdStr))
 
 	shell := strings.TrimSpace(os.Getenv("SHELL"))
 	if shell == "" {
@@ -245,11 +250,13 @@ func runSuggestedCommand(ctx context.Context, suggestion ai.SuggestedCommand) er
 	execCmd.Stderr = os.Stderr
 	execCmd.Stdin = os.Stdin
 
-	if err := execCmd.Run(); err != nil {
+	err := execCmd.Run()
+	recordAction(ctx, wd, "sg cmd", entry, err)
+	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ProcessState != nil {
-			fmt.Printf("Command exited with status code %d\n", exitErr.ProcessState.ExitCode())
+			fmt.Println(i18n.T(i18n.MsgCommandExitStatus, exitErr.ProcessState.ExitCode()))
 		} else {
-			fmt.Printf("Error while executing command: %v\n", err)
+			fmt.Println(i18n.T(i18n.MsgCommandFailed, err))
 		}
 		return err
 	}
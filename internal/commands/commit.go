@@ -11,7 +11,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/conventional"
 	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
 	"github.com/vinhtran/git-smart/pkg/logger"
 )
 
@@ -55,11 +58,11 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if strings.TrimSpace(status) == "" {
-		fmt.Println("There are no changes to commit.")
+		fmt.Println(i18n.T(i18n.MsgNoChangesToCommit))
 		return nil
 	}
 
-	apiKey, err := resolveAPIKey(ctx)
+	client, err := newAIClient(ctx, wd, 256)
 	if err != nil {
 		return err
 	}
@@ -67,26 +70,17 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	// Build a diff that represents everything that would be committed,
 	// without staging anything yet (to avoid touching the working tree
 	// before the user has seen the privacy assessment).
-	stagedDiff, err := git.GetStagedDiff(ctx, wd)
+	stagedDiff, err := git.LoadStagedDiff(ctx, wd)
 	if err != nil {
 		return err
 	}
-	workingDiff, err := git.GetWorkingTreeDiff(ctx, wd)
+	workingDiff, err := git.LoadWorkingTreeDiff(ctx, wd)
 	if err != nil {
 		return err
 	}
-
-	var diffBuilder strings.Builder
-	diffBuilder.WriteString(stagedDiff)
-	if strings.TrimSpace(workingDiff) != "" {
-		if diffBuilder.Len() > 0 {
-			diffBuilder.WriteString("\n")
-		}
-		diffBuilder.WriteString(workingDiff)
-	}
-	diff := strings.TrimSpace(diffBuilder.String())
-	if diff == "" {
-		fmt.Println("There are no changes to commit.")
+	diff := &git.Diff{Files: append(stagedDiff.Files, workingDiff.Files...)}
+	if len(diff.Files) == 0 {
+		fmt.Println(i18n.T(i18n.MsgNoChangesToCommit))
 		return nil
 	}
 
@@ -95,11 +89,19 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := ai.NewClient(apiKey, 256)
+	promptDiff, skipped, err := filterDiffForAI(ctx, wd, diff)
+	if err != nil {
+		return err
+	}
 
 	req := ai.CommitAnalysisRequest{
-		Diff:     diff,
-		RepoInfo: repoInfo,
+		Diff:         promptDiff.String(),
+		RepoInfo:     repoInfo,
+		SkippedFiles: skipped,
+		Language:     language.Tag,
+	}
+	if languageExplicit {
+		req.MessageLanguage = language.Tag
 	}
 
 	log.InfoContext(ctx, "Requesting Gemini commit message and privacy analysis")
@@ -116,7 +118,7 @@ func runCommit(cmd *cobra.Command, args []string) error {
 
 	branchName := strings.TrimSpace(analysis.BranchName)
 
-	fmt.Println("Proposed commit message:")
+	fmt.Println(i18n.T(i18n.MsgProposedCommit))
 	fmt.Println("------------------------")
 	fmt.Println(message)
 	fmt.Println("------------------------")
@@ -126,27 +128,39 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		risk = "low"
 	}
 
-	if risk == "high" || risk == "medium" {
-		fmt.Println("Potential sensitive/private information detected in this commit:")
-		for _, reason := range analysis.PrivacyReasons {
-			if strings.TrimSpace(reason) == "" {
-				continue
+	// Files the ignore rules kept out of the prompt were never seen by the
+	// AI, so they are checked for secrets locally.
+	localFindings := secretsOutsidePrompt(diff, promptDiff)
+	aiRisky := risk == "high" || risk == "medium"
+
+	if aiRisky || len(localFindings) > 0 {
+		fmt.Println(i18n.T(i18n.MsgPrivacyDetected))
+		if aiRisky {
+			for _, reason := range analysis.PrivacyReasons {
+				if strings.TrimSpace(reason) == "" {
+					continue
+				}
+				fmt.Printf("- %s\n", reason)
 			}
-			fmt.Printf("- %s\n", reason)
 		}
-		fmt.Printf("Privacy risk level reported by AI: %s\n", risk)
-		fmt.Print("Do you still want to proceed with staging and committing these changes? (y/N): ")
+		for _, finding := range localFindings {
+			fmt.Printf("- %s\n", i18n.T(i18n.MsgPrivacyNotSent, finding))
+		}
+		if aiRisky {
+			fmt.Println(i18n.T(i18n.MsgPrivacyRisk, risk))
+		}
+		fmt.Print(i18n.T(i18n.MsgPrivacyConfirm))
 
 		reader := bufio.NewReader(os.Stdin)
 		answer, _ := reader.ReadString('\n')
-		answer = strings.ToLower(strings.TrimSpace(answer))
-		if answer != "y" && answer != "yes" {
-			fmt.Println("Commit aborted due to potential sensitive information.")
+		if !i18n.IsYes(answer) {
+			fmt.Println(i18n.T(i18n.MsgPrivacyAborted))
 			return nil
 		}
 	}
 
 	protectedBranch := isProtectedBranch(repoInfo.Branch)
+	recordUndoPoint(ctx, wd, "sg cm")
 
 	// If we are on a protected branch (like main/develop), create and switch
 	// to a feature/fix branch before staging and committing.
@@ -155,23 +169,36 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		if finalBranchName == "" {
 			finalBranchName = deriveBranchNameFromCommit(message)
 		}
-		fmt.Printf("Creating and switching to branch: %s\n", finalBranchName)
-		if err := git.CreateAndCheckoutBranch(ctx, wd, finalBranchName); err != nil {
+		fmt.Println(i18n.T(i18n.MsgCreatingBranch, finalBranchName))
+		err := git.CreateAndCheckoutBranch(ctx, wd, finalBranchName)
+		recordAction(ctx, wd, "sg cm", journal.Entry{Action: journal.ActionBranchCreate, Branch: finalBranchName}, err)
+		if err != nil {
 			return err
 		}
 	}
 
 	log.InfoContext(ctx, "Staging all changes after AI analysis")
-	if err := git.AddAll(ctx, wd); err != nil {
+	err = git.AddAll(ctx, wd)
+	staged, _ := git.StagedFiles(ctx, wd)
+	recordAction(ctx, wd, "sg cm", journal.Entry{Action: journal.ActionStage, Files: staged}, err)
+	if err != nil {
 		return err
 	}
 
 	log.InfoContext(ctx, "Creating git commit with AI generated message")
-	if err := git.Commit(ctx, wd, message); err != nil {
+	err = git.Commit(ctx, wd, message)
+	branch, _ := git.CurrentBranch(ctx, wd)
+	entry := journal.Entry{Action: journal.ActionCommit, Branch: branch, Files: staged}
+	if err == nil {
+		// After a failed commit HEAD is still the previous commit.
+		entry.Commit, _ = git.RevParse(ctx, wd, "HEAD")
+	}
+	recordAction(ctx, wd, "sg cm", entry, err)
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("Commit created successfully.")
+	fmt.Println(i18n.T(i18n.MsgCommitCreated))
 	return nil
 }
 
@@ -194,30 +221,14 @@ func deriveBranchNameFromCommit(header string) string {
 	}
 
 	// Extract type, optional scope and description from "<type>(<scope>)!: desc"
-	colonIdx := strings.Index(header, ":")
-	if colonIdx == -1 {
+	parsed, ok := conventional.ParseHeader(header)
+	if !ok {
 		return "feature/" + slugify(header)
 	}
 
-	prefix := strings.TrimSpace(header[:colonIdx])
-	desc := strings.TrimSpace(header[colonIdx+1:])
-
-	// Remove optional breaking change indicator "!" before colon.
-	if strings.HasSuffix(prefix, "!") {
-		prefix = strings.TrimSuffix(prefix, "!")
-		prefix = strings.TrimSpace(prefix)
-	}
-
-	// Extract type from "<type>(scope)" or "<type>"
-	typePart := prefix
-	if parenIdx := strings.Index(prefix, "("); parenIdx != -1 {
-		typePart = prefix[:parenIdx]
-	}
-	typePart = strings.ToLower(strings.TrimSpace(typePart))
-
-	category := mapCommitTypeToBranchCategory(typePart)
+	category := mapCommitTypeToBranchCategory(parsed.Type)
 
-	descSlug := slugify(desc)
+	descSlug := slugify(parsed.Description)
 	if len(descSlug) > 40 {
 		descSlug = descSlug[:40]
 	}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// editText opens text in the user's git editor and returns the saved
+// result. pattern names the temp file (see os.CreateTemp) so editors can
+// pick syntax highlighting from its extension.
+func editText(ctx context.Context, dir, text, pattern string) (string, error) {
+	out, err := git.Run(ctx, dir, "var", "GIT_EDITOR")
+	if err != nil {
+		return "", err
+	}
+	editor := strings.TrimSpace(out)
+
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// GIT_EDITOR may carry arguments, so let the shell split it like git does.
+	cmd := exec.CommandContext(ctx, "sh", "-c", editor+` "$@"`, editor, f.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
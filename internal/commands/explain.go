@@ -0,0 +1,284 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+// explainExcerptLines is how many lines around a blamed line are shown to
+// the model on each side.
+const explainExcerptLines = 10
+
+type explainOptions struct {
+	log       string
+	count     int
+	raw       bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+var (
+	explainCmd = &cobra.Command{
+		Use:   "explain <commit> | <file>:<line> | --log <path>",
+		Short: "Explain a commit, a line of code or the history of a file",
+		Long: `Explain a commit, a line of code or the history of a file in plain
+language, the way a colleague would to someone new to the code base.
+
+  sg explain <commit>        what the commit changes and why
+  sg explain <file>:<line>   why the line exists: git blame finds the commit
+                             that last changed it, and its message and diff
+                             are explained together with the code around it
+  sg explain --log <path>    how the file evolved, from its recent commits
+
+Files excluded from AI prompts (.smartgitignore, generated and binary
+files) are not sent.`,
+		Example: `  sg explain HEAD~3
+  sg explain 1a2b3c4
+  sg explain internal/git/git.go:120
+  sg explain --log internal/git/git.go -n 30`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runExplain,
+	}
+	explainOpts explainOptions
+)
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringVar(&explainOpts.log, "log", "", "Summarize how this file evolved")
+	explainCmd.Flags().IntVarP(&explainOpts.count, "number", "n", 20, "Number of recent commits to read with --log")
+	explainCmd.Flags().BoolVar(&explainOpts.raw, "raw", false, "Print only the explanation, without header and dividers")
+	explainCmd.Flags().IntVar(&explainOpts.maxTokens, "max-tokens", 1024, "Maximum tokens for the explanation")
+	explainCmd.Flags().DurationVar(&explainOpts.timeout, "timeout", 60*time.Second, "Timeout for the explanation")
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), explainOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "explain", "path", wd)
+
+	switch {
+	case explainOpts.log != "" && len(args) > 0:
+		return errors.New("--log cannot be combined with a commit or <file>:<line>")
+	case explainOpts.log == "" && len(args) == 0:
+		return errors.New("tell sg what to explain: a commit, <file>:<line> or --log <path>")
+	case explainOpts.count <= 0:
+		return fmt.Errorf("--number must be positive, got %d", explainOpts.count)
+	}
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+	root, err := git.TopLevel(ctx, wd)
+	if err != nil {
+		return err
+	}
+	repoInfo, err := git.GetRepoInfo(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	req := ai.ExplainRequest{RepoInfo: repoInfo, Language: language.Tag}
+	var title string
+	switch {
+	case explainOpts.log != "":
+		title, err = explainHistoryRequest(ctx, wd, root, explainOpts.log, &req)
+	default:
+		if path, line, ok := parseFileLine(wd, args[0]); ok {
+			title, err = explainLineRequest(ctx, wd, root, path, line, &req)
+		} else {
+			title, err = explainCommitRequest(ctx, wd, args[0], &req)
+		}
+	}
+	if err != nil || title == "" {
+		return err
+	}
+
+	client, err := newAIClient(ctx, wd, explainOpts.maxTokens)
+	if err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "Requesting explanation", "mode", req.Mode, "target", title, "language", language.Tag)
+	text, err := client.Explain(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if explainOpts.raw {
+		fmt.Println(text)
+		return nil
+	}
+	divider := strings.Repeat("-", 60)
+	fmt.Println(divider)
+	fmt.Println(title)
+	fmt.Println(divider)
+	fmt.Println(text)
+	fmt.Println(divider)
+	return nil
+}
+
+// parseFileLine recognizes "<file>:<line>" when file exists in the working
+// tree, so that revisions such as "HEAD:README.md" are not mistaken for it.
+func parseFileLine(dir, arg string) (string, int, bool) {
+	i := strings.LastIndex(arg, ":")
+	if i <= 0 {
+		return "", 0, false
+	}
+	line, err := strconv.Atoi(arg[i+1:])
+	if err != nil || line <= 0 {
+		return "", 0, false
+	}
+	path := arg[:i]
+	if info, err := os.Stat(filepath.Join(dir, path)); err != nil || info.IsDir() {
+		return "", 0, false
+	}
+	return path, line, true
+}
+
+func explainCommitRequest(ctx context.Context, dir, rev string, req *ai.ExplainRequest) (string, error) {
+	if _, err := git.RevParse(ctx, dir, rev+"^{commit}"); err != nil {
+		return "", fmt.Errorf("%q is neither a commit nor an existing <file>:<line>", rev)
+	}
+	commits, err := git.CommitLog(ctx, dir, "-1", rev)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("commit %s not found", rev)
+	}
+	diff, err := git.LoadCommitDiff(ctx, dir, rev)
+	if err != nil {
+		return "", err
+	}
+	diff, skipped, err := filterDiffForAI(ctx, dir, diff)
+	if err != nil {
+		return "", err
+	}
+
+	req.Mode = ai.ExplainCommit
+	req.Commit = commits[0]
+	req.Diff = diff.String()
+	req.SkippedFiles = skipped
+	return fmt.Sprintf("Commit %s: %s", commits[0].ShortHash(), commits[0].Subject), nil
+}
+
+func explainLineRequest(ctx context.Context, dir, root, path string, line int, req *ai.ExplainRequest) (string, error) {
+	blame, err := git.Blame(ctx, dir, path, line)
+	if err != nil {
+		return "", err
+	}
+	if blame.Uncommitted {
+		fmt.Printf("Line %d of %s is not committed yet, so there is no history to explain.\n", line, path)
+		return "", nil
+	}
+	// The commit diff uses the file's name at that commit, the excerpt its
+	// current name; a file moved into an excluded location must stay out.
+	for _, p := range []string{repoRelative(dir, root, path), blame.Path} {
+		if excluded, reason, err := excludedFromAI(root, p); err != nil {
+			return "", err
+		} else if excluded {
+			return "", fmt.Errorf("%s is excluded from AI prompts (%s)", p, reason)
+		}
+	}
+
+	commit := blame.Commit
+	if commits, err := git.CommitLog(ctx, dir, "-1", commit.Hash); err == nil && len(commits) > 0 {
+		commit = commits[0]
+	}
+	diff, err := git.LoadCommitDiff(ctx, root, commit.Hash, blame.Path)
+	if err != nil {
+		return "", err
+	}
+	excerpt, err := fileExcerpt(filepath.Join(dir, path), line, explainExcerptLines)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("%s:%d was last changed in %s by %s, %s: %s\n", path, line, commit.ShortHash(), commit.Author, relativeTime(commit.Date), commit.Subject)
+
+	req.Mode = ai.ExplainLine
+	req.Commit = commit
+	req.Diff = diff.String()
+	req.Path = path
+	req.Line = line
+	req.LineText = blame.Text
+	req.Excerpt = excerpt
+	return fmt.Sprintf("%s:%d (from %s)", path, line, commit.ShortHash()), nil
+}
+
+// repoRelative returns path, given relative to dir, relative to the
+// repository root. Symlinks are resolved because root is.
+func repoRelative(dir, root, path string) string {
+	abs, err := filepath.EvalSymlinks(filepath.Join(dir, path))
+	if err != nil {
+		if abs, err = filepath.Abs(filepath.Join(dir, path)); err != nil {
+			return path
+		}
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func explainHistoryRequest(ctx context.Context, dir, root, path string, req *ai.ExplainRequest) (string, error) {
+	rel := repoRelative(dir, root, path)
+	if excluded, reason, err := excludedFromAI(root, rel); err != nil {
+		return "", err
+	} else if excluded {
+		return "", fmt.Errorf("%s is excluded from AI prompts (%s)", rel, reason)
+	}
+
+	history, err := git.FileHistory(ctx, dir, path, explainOpts.count)
+	if err != nil {
+		return "", err
+	}
+	if len(history) == 0 {
+		return "", fmt.Errorf("no commits changed %s", path)
+	}
+
+	req.Mode = ai.ExplainHistory
+	req.Path = filepath.ToSlash(rel)
+	req.History = history
+	oldest := history[len(history)-1].Commit
+	return fmt.Sprintf("History of %s (%d commit(s) since %s)", req.Path, len(history), oldest.Date.Format("2006-01-02")), nil
+}
+
+// fileExcerpt returns up to n lines on each side of line, numbered, with
+// the line itself marked.
+func fileExcerpt(path string, line, n int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(string(data), "\n")
+	from, to := max(1, line-n), min(len(lines), line+n)
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s%5d  %s\n", marker, i, lines[i-1])
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
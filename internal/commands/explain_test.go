@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+)
+
+// testGit runs git in dir with a fixed identity and no user configuration.
+func testGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestExplainLineRequestExclusion(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	ctx := context.Background()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testGit(t, root, "init", "--quiet")
+	write(".smartgitignore", "secrets/\nlegacy.env\n")
+	write("app.env", "TOKEN=abc\n")
+	write("legacy.env", "KEY=old\n")
+	write("src/main.go", "package main\n")
+	testGit(t, root, "add", ".")
+	testGit(t, root, "commit", "--quiet", "-m", "initial")
+	// Moved into an excluded directory; blame still reports the old name.
+	if err := os.Mkdir(filepath.Join(root, "secrets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	testGit(t, root, "mv", "app.env", "secrets/app.env")
+	// Moved out of an excluded name; the old commit's diff holds its contents.
+	testGit(t, root, "mv", "legacy.env", "config.env")
+	testGit(t, root, "commit", "--quiet", "-m", "move files")
+
+	tests := []struct {
+		dir, path string
+		excluded  string
+	}{
+		{root, "secrets/app.env", "secrets/app.env"},
+		{filepath.Join(root, "secrets"), "app.env", "secrets/app.env"},
+		{root, "config.env", "legacy.env"},
+		{root, "src/main.go", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var req ai.ExplainRequest
+			_, err := explainLineRequest(ctx, tt.dir, root, tt.path, 1, &req)
+			if tt.excluded == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if req.Excerpt == "" {
+					t.Error("no excerpt for an allowed file")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.excluded+" is excluded") {
+				t.Errorf("err = %v, want %s to be excluded", err, tt.excluded)
+			}
+			if req.Excerpt != "" || req.Diff != "" {
+				t.Error("request was filled for an excluded file")
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/forge"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/secret"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+// forgeTokenEnv is checked for a token on hosts configured under forges.
+const forgeTokenEnv = "SMARTGIT_FORGE_TOKEN"
+
+// openForge detects the forge behind remote from the config or the remote
+// host and returns a client authenticated with the configured token.
+func openForge(ctx context.Context, dir, remote string) (forge.Forge, error) {
+	remoteURL, err := git.Run(ctx, dir, "remote", "get-url", remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read URL of remote %s: %w", remote, err)
+	}
+	parsed, err := forge.ParseRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	settings := cfg.Forges[parsed.Host]
+
+	kind, ok := forge.ParseKind(settings.Type)
+	if !ok {
+		if settings.Type != "" {
+			return nil, fmt.Errorf("invalid forge type %q for %s (expected github, gitlab or gitea)", settings.Type, parsed.Host)
+		}
+		if kind, ok = forge.DetectKind(parsed.Host); !ok {
+			return nil, fmt.Errorf("%w for %s; set \"type\" under forges.%s in config.json", forge.ErrUnknownForge, parsed.Host, parsed.Host)
+		}
+	}
+
+	token, err := resolveForgeToken(ctx, cfg, parsed.Host, kind)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no %s token for %s; set %s or add the host under forges in config.json",
+			kind, parsed.Host, strings.Join(forge.TokenEnvVars(kind, parsed.Host), " or "))
+	}
+
+	return forge.New(forge.Options{
+		Kind:   kind,
+		APIURL: settings.APIURL,
+		Token:  token,
+		Remote: parsed,
+	})
+}
+
+// resolveForgeToken returns the token for host, trying the host's own
+// settings first: its token_env variable, its secret store entry and its
+// plaintext config value. After that come the forge's conventional variables
+// (GITHUB_TOKEN, ...) and, only for hosts listed under forges, the generic
+// SMARTGIT_FORGE_TOKEN, so it is never sent to a server nobody configured.
+func resolveForgeToken(ctx context.Context, cfg config.Config, host string, kind forge.Kind) (string, error) {
+	settings, configured := cfg.Forges[host]
+	if env := strings.TrimSpace(settings.TokenEnv); env != "" {
+		if token := strings.TrimSpace(os.Getenv(env)); token != "" {
+			return token, nil
+		}
+	}
+
+	store, err := openSecretStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	if store != nil {
+		token, err := store.Get(ctx, forgeSecretName(host))
+		if err != nil && !errors.Is(err, secret.ErrNotFound) {
+			return "", err
+		}
+		if token = strings.TrimSpace(token); token != "" {
+			return token, nil
+		}
+	}
+
+	if token := strings.TrimSpace(settings.Token); token != "" {
+		return token, nil
+	}
+
+	names := forge.TokenEnvVars(kind, host)
+	if configured {
+		names = append(names, forgeTokenEnv)
+	}
+	for _, name := range names {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token, nil
+		}
+	}
+	return "", nil
+}
+
+// forgeSecretName is the secret store entry holding the token for host.
+func forgeSecretName(host string) string {
+	return "forge_" + host + "_token"
+}
+
+// createPullRequest opens a pull request for branch into base (the remote's
+// default branch when empty), described by the AI. If the description cannot
+// be generated, the commit messages are used instead.
+func createPullRequest(ctx context.Context, dir, remote, branch, base string, draft bool, maxTokens int) (forge.Created, error) {
+	log := logger.L().With("path", dir, "branch", branch)
+
+	f, err := openForge(ctx, dir, remote)
+	if err != nil {
+		return forge.Created{}, err
+	}
+
+	if base == "" {
+		if base, err = git.DefaultBranch(ctx, dir, remote); err != nil {
+			return forge.Created{}, err
+		}
+	}
+
+	desc, err := describePullRequest(ctx, dir, remote, base, "", maxTokens)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return forge.Created{}, err
+		}
+		fmt.Fprintln(os.Stderr, i18n.T(i18n.MsgPullRequestFallback, err))
+		if desc, err = commitsDescription(ctx, dir, remote, base); err != nil {
+			return forge.Created{}, err
+		}
+	}
+
+	log.InfoContext(ctx, "Creating pull request", "forge", f.Name(), "base", base, "draft", draft)
+	return f.CreatePullRequest(ctx, forge.PullRequest{
+		Head:  branch,
+		Base:  base,
+		Title: desc.Title,
+		Body:  desc.Body,
+		Draft: draft,
+	})
+}
+
+// commitsDescription builds a plain description from the branch's commits.
+func commitsDescription(ctx context.Context, dir, remote, base string) (ai.PullRequestDescription, error) {
+	var desc ai.PullRequestDescription
+	commits, err := git.CommitLog(ctx, dir, git.BaseRef(ctx, dir, remote, base)+"..HEAD")
+	if err != nil {
+		return desc, err
+	}
+	if len(commits) == 0 {
+		return desc, fmt.Errorf("no commits to open a pull request for against %s", base)
+	}
+
+	// The oldest commit usually names the feature best.
+	desc.Title = commits[len(commits)-1].Subject
+	var body strings.Builder
+	for _, c := range commits {
+		body.WriteString("- " + c.Subject + "\n")
+	}
+	desc.Body = strings.TrimSpace(body.String())
+	return desc, nil
+}
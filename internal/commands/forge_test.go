@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/forge"
+)
+
+func TestResolveForgeToken(t *testing.T) {
+	configured := map[string]config.Forge{
+		"git.example.com":    {Type: "gitea", TokenEnv: "EXAMPLE_TOKEN"},
+		"gitea.example.org":  {Token: "plain-token"},
+		"github.example.com": {},
+	}
+	tests := []struct {
+		name  string
+		host  string
+		kind  forge.Kind
+		store string
+		env   map[string]string
+		want  string
+	}{
+		{"token_env first", "git.example.com", forge.KindGitea, "", map[string]string{"EXAMPLE_TOKEN": "host", "GITEA_TOKEN": "conventional", forgeTokenEnv: "generic"}, "host"},
+		{"secret store before conventional", "git.example.com", forge.KindGitea, "echo stored-{name}", map[string]string{"GITEA_TOKEN": "conventional"}, "stored-forge_git.example.com_token"},
+		{"plaintext config before conventional", "gitea.example.org", forge.KindGitea, "", map[string]string{"GITEA_TOKEN": "conventional"}, "plain-token"},
+		{"conventional before generic", "git.example.com", forge.KindGitea, "", map[string]string{"GITEA_TOKEN": "conventional", forgeTokenEnv: "generic"}, "conventional"},
+		{"generic for configured host", "git.example.com", forge.KindGitea, "", map[string]string{forgeTokenEnv: "generic"}, "generic"},
+		{"no generic for unconfigured host", "gitlab.com", forge.KindGitLab, "", map[string]string{forgeTokenEnv: "generic"}, ""},
+		{"github.com token", "github.com", forge.KindGitHub, "", map[string]string{"GH_TOKEN": "gh"}, "gh"},
+		{"no github.com token for enterprise", "github.example.com", forge.KindGitHub, "", map[string]string{"GITHUB_TOKEN": "public"}, ""},
+		{"enterprise token", "github.example.com", forge.KindGitHub, "", map[string]string{"GITHUB_TOKEN": "public", "GH_ENTERPRISE_TOKEN": "ghe"}, "ghe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			for _, name := range []string{"EXAMPLE_TOKEN", "GITEA_TOKEN", "GITLAB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", forgeTokenEnv} {
+				t.Setenv(name, tt.env[name])
+			}
+			cfg := config.Config{Forges: configured}
+			if tt.store != "" {
+				cfg.SecretStore, cfg.SecretCommand = "command", tt.store
+			}
+
+			got, err := resolveForgeToken(context.Background(), cfg, tt.host, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveForgeToken(%s) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
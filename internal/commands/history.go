@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/journal"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+type historyOptions struct {
+	count   int
+	all     bool
+	action  string
+	failed  bool
+	json    bool
+	timeout time.Duration
+}
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Show what sg has done to your repositories",
+		Long: `Show what sg has done to your repositories.
+
+Every state-changing action sg performs (branches created and deleted,
+files staged, commits, tags, pushes, rebases, stashes, resets, continued
+or aborted operations, and shell commands run from "sg cmd") is appended
+to a journal in $XDG_STATE_HOME/smartgit/journal.jsonl
+(~/.local/state/smartgit by default) with its time, repository and
+outcome.
+
+Inside a repository only its entries are shown; use --all for every
+repository.`,
+		Example: `  sg history
+  sg history -n 50 --all
+  sg history --action shell --failed
+  sg history --json | jq .`,
+		Args: cobra.NoArgs,
+		RunE: runHistory,
+	}
+	historyOpts historyOptions
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().IntVarP(&historyOpts.count, "number", "n", 20, "Number of most recent entries to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyOpts.all, "all", false, "Show entries from every repository")
+	historyCmd.Flags().StringVar(&historyOpts.action, "action", "", "Only show one action (branch-create, branch-delete, checkout, stage, commit, tag, push, rebase, stash, reset, continue, abort, bisect, shell)")
+	historyCmd.Flags().BoolVar(&historyOpts.failed, "failed", false, "Only show actions that failed or were cancelled")
+	historyCmd.Flags().BoolVar(&historyOpts.json, "json", false, "Print the entries as JSON lines")
+	historyCmd.Flags().DurationVar(&historyOpts.timeout, "timeout", 10*time.Second, "Timeout for reading the journal")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), historyOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	repo := ""
+	if !historyOpts.all {
+		// Outside a repository there is nothing to filter by.
+		repo, _ = git.TopLevel(ctx, wd)
+	}
+
+	entries, err := journal.Read()
+	if err != nil {
+		return err
+	}
+
+	var shown []journal.Entry
+	for _, e := range entries {
+		if repo != "" && e.Repo != repo {
+			continue
+		}
+		if historyOpts.action != "" && string(e.Action) != historyOpts.action {
+			continue
+		}
+		if historyOpts.failed && e.Outcome == journal.OutcomeOK {
+			continue
+		}
+		shown = append(shown, e)
+	}
+	if historyOpts.count > 0 && len(shown) > historyOpts.count {
+		shown = shown[len(shown)-historyOpts.count:]
+	}
+
+	if historyOpts.json {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range shown {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(shown) == 0 {
+		if repo != "" {
+			fmt.Println("No sg actions recorded for this repository yet (use --all for every repository).")
+		} else {
+			fmt.Println("No sg actions recorded yet.")
+		}
+		return nil
+	}
+
+	lastRepo := ""
+	for _, e := range shown {
+		if repo == "" && e.Repo != lastRepo {
+			fmt.Printf("%s%s%s\n", colorCyan, e.Repo, colorReset)
+			lastRepo = e.Repo
+		}
+		outcome := string(e.Outcome)
+		if e.Outcome != journal.OutcomeOK {
+			outcome = strings.ToUpper(outcome)
+		}
+		fmt.Printf("  %s  %-9s %-13s %-9s %s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Command, e.Action, outcome, e.Summary())
+		if e.Error != "" {
+			fmt.Printf("      %s\n", e.Error)
+		}
+	}
+	return nil
+}
+
+// recordAction appends an action sg performed on the repository at dir to
+// the journal, as failed when err is set. Journal errors are logged but
+// never stop the command.
+func recordAction(ctx context.Context, dir, command string, e journal.Entry, err error) {
+	e.Command = command
+	e.Repo = dir
+	if root, rootErr := git.TopLevel(ctx, dir); rootErr == nil {
+		e.Repo = root
+	}
+	if err != nil && e.Outcome == "" {
+		e.Outcome = journal.OutcomeFailed
+		e.Error = errorSummary(err.Error())
+	}
+	if jErr := journal.Append(e); jErr != nil {
+		logger.L().WarnContext(ctx, "Failed to write journal entry", "path", dir, "action", e.Action, "error", jErr)
+	}
+}
+
+// errorSummary keeps journal errors to one readable line. Git errors read
+// "git X failed: exit status N" followed by the command output, so the line
+// of that output explaining the failure is kept as well.
+func errorSummary(s string) string {
+	head, output, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	head = strings.TrimSpace(head)
+	if detail := failureDetail(output); detail != "" {
+		head += ": " + detail
+	}
+	if len(head) > 200 {
+		// Cut at a rune boundary so the journal stays valid UTF-8.
+		cut := 197
+		for cut > 0 && !utf8.RuneStart(head[cut]) {
+			cut--
+		}
+		head = head[:cut] + "..."
+	}
+	return head
+}
+
+// failureDetail picks the line of git output that says why a command
+// failed: the first error, fatal or push status ("! [rejected] ...") line,
+// otherwise the last line that is not a hint.
+func failureDetail(output string) string {
+	last := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "hint:"):
+			continue
+		case strings.HasPrefix(line, "error:"), strings.HasPrefix(line, "fatal:"), strings.HasPrefix(line, "! "):
+			return line
+		}
+		last = line
+	}
+	return last
+}
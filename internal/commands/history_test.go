@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestErrorSummary(t *testing.T) {
+	long := strings.Repeat("a", 196) + "äöü" + strings.Repeat("b", 10)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"single line", "  push failed  ", "push failed"},
+		{"last output line", "git push failed: exit status 1\nremote: rejected\n", "git push failed: exit status 1: remote: rejected"},
+		{"push status line", "git push origin main failed: exit status 1\nTo github.com:acme/api.git\n ! [rejected]        main -> main (fetch first)\nerror: failed to push some refs to 'github.com:acme/api.git'\nhint: Updates were rejected because the remote contains work\nhint: that you do not have locally.\n",
+			"git push origin main failed: exit status 1: ! [rejected]        main -> main (fetch first)"},
+		{"fatal line", "git commit -F - failed: exit status 128\nAuthor identity unknown\n\nfatal: unable to auto-detect email address\n", "git commit -F - failed: exit status 128: fatal: unable to auto-detect email address"},
+		{"hints only", "git rebase failed: exit status 1\nhint: see git help\n", "git rebase failed: exit status 1"},
+		{"exactly 200", strings.Repeat("x", 200), strings.Repeat("x", 200)},
+		{"ascii", strings.Repeat("x", 250), strings.Repeat("x", 197) + "..."},
+		// The cut at byte 197 falls inside "ä"; the whole rune must go.
+		{"multibyte", long, strings.Repeat("a", 196) + "..."},
+		{"long detail", "git push failed: exit status 1\nerror: " + long, ("git push failed: exit status 1: error: " + strings.Repeat("a", 196))[:197] + "..."},
+	}
+	for _, tt := range tests {
+		got := errorSummary(tt.in)
+		if got != tt.want {
+			t.Errorf("%s: errorSummary() = %q, want %q", tt.name, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("%s: errorSummary() is not valid UTF-8", tt.name)
+		}
+	}
+}
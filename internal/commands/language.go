@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+var (
+	languageFlag string
+	// language is the resolved language for CLI messages and AI output.
+	language = i18n.English
+	// languageExplicit is set when the language came from --language or the
+	// config rather than the locale. Commit messages end up in shared
+	// history, so they are only localized when asked for explicitly.
+	languageExplicit bool
+)
+
+// setupLanguage resolves the language from --language, then the config
+// "language" key, then the locale environment, and selects it for i18n.T.
+func setupLanguage(ctx context.Context) error {
+	log := logger.L()
+
+	if languageFlag != "" {
+		lang, err := i18n.Parse(languageFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --language: %w", err)
+		}
+		useLanguage(lang, true)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		// Commands that need the config report the error themselves.
+		log.DebugContext(ctx, "Could not load config for language selection", "error", err)
+	} else if cfg.Language != "" {
+		lang, err := i18n.Parse(cfg.Language)
+		if err != nil {
+			return fmt.Errorf("invalid language in config: %w", err)
+		}
+		useLanguage(lang, true)
+		return nil
+	}
+
+	if lang, ok := i18n.FromEnv(); ok {
+		useLanguage(lang, false)
+		return nil
+	}
+	log.DebugContext(ctx, "No supported language in locale environment, using English")
+	useLanguage(i18n.English, false)
+	return nil
+}
+
+func useLanguage(lang i18n.Language, explicit bool) {
+	language = lang
+	languageExplicit = explicit
+	i18n.SetLanguage(lang)
+}
@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/clipboard"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+// pullRequestTemplates are the template locations checked, in order.
+var pullRequestTemplates = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+	".gitea/pull_request_template.md",
+	".gitlab/merge_request_templates/Default.md",
+}
+
+type prDescribeOptions struct {
+	base      string
+	template  string
+	copy      bool
+	json      bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+type prCreateOptions struct {
+	base      string
+	remote    string
+	draft     bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+var (
+	prCmd = &cobra.Command{
+		Use:   "pr",
+		Short: "Pull request helpers",
+	}
+	prDescribeCmd = &cobra.Command{
+		Use:   "describe",
+		Short: "Generate a pull request title and description for the current branch",
+		Long: `Generate a pull request title and description for the current branch.
+
+The commits and the diff since the merge-base with --base are sent to the
+model. When the repository has a pull request template (for example
+.github/pull_request_template.md), the description fills it in; otherwise it
+has Summary, Changes, Testing and Risk sections.`,
+		Example: `  sg pr describe
+  sg pr describe --base develop --copy
+  sg pr describe --json | jq -r .body`,
+		Args: cobra.NoArgs,
+		RunE: runPRDescribe,
+	}
+	prDescribeOpts prDescribeOptions
+
+	prCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Open a pull request for the current branch with an AI description",
+		Long: `Open a pull request (merge request on GitLab) for the current branch.
+
+The forge is detected from the remote (origin unless --remote is given:
+GitHub, GitLab or Gitea, or the "forges" section of config.json) and the token is read from the
+environment or config. The branch must already be pushed.`,
+		Example: `  sg pr create
+  sg pr create --base develop --draft
+  sg pr create --remote upstream`,
+		Args: cobra.NoArgs,
+		RunE: runPRCreate,
+	}
+	prCreateOpts prCreateOptions
+)
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.AddCommand(prDescribeCmd)
+	prCmd.AddCommand(prCreateCmd)
+
+	prDescribeCmd.Flags().StringVar(&prDescribeOpts.base, "base", "", "Branch the pull request targets (default: the remote's default branch)")
+	prDescribeCmd.Flags().StringVar(&prDescribeOpts.template, "template", "", "Pull request template to fill in (default: detected from the repository)")
+	prDescribeCmd.Flags().BoolVar(&prDescribeOpts.copy, "copy", false, "Copy the title and description to the clipboard")
+	prDescribeCmd.Flags().BoolVar(&prDescribeOpts.json, "json", false, "Print the title and body as JSON")
+	prDescribeCmd.Flags().IntVar(&prDescribeOpts.maxTokens, "max-tokens", 1024, "Maximum tokens for the generated description")
+	prDescribeCmd.Flags().DurationVar(&prDescribeOpts.timeout, "timeout", 60*time.Second, "Timeout for the AI request")
+
+	prCreateCmd.Flags().StringVar(&prCreateOpts.base, "base", "", "Target branch (default: the remote's default branch)")
+	prCreateCmd.Flags().StringVar(&prCreateOpts.remote, "remote", "origin", "Remote hosting the pull request")
+	prCreateCmd.Flags().BoolVar(&prCreateOpts.draft, "draft", false, "Open the pull request as a draft")
+	prCreateCmd.Flags().IntVar(&prCreateOpts.maxTokens, "max-tokens", 1024, "Maximum tokens for the generated description")
+	prCreateCmd.Flags().DurationVar(&prCreateOpts.timeout, "timeout", 60*time.Second, "Timeout for the AI request and the forge API call")
+}
+
+func runPRDescribe(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), prDescribeOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+
+	desc, err := describePullRequest(ctx, wd, "origin", prDescribeOpts.base, prDescribeOpts.template, prDescribeOpts.maxTokens)
+	if err != nil {
+		return err
+	}
+
+	if prDescribeOpts.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(desc); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(formatPullRequest(desc))
+	}
+
+	if prDescribeOpts.copy {
+		if err := clipboard.Copy(ctx, formatPullRequest(desc)); err != nil {
+			return fmt.Errorf("failed to copy to clipboard: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "Copied to clipboard.")
+	}
+	return nil
+}
+
+func runPRCreate(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), prCreateOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+
+	branch, err := git.CurrentBranch(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	created, err := createPullRequest(ctx, wd, prCreateOpts.remote, branch, prCreateOpts.base, prCreateOpts.draft, prCreateOpts.maxTokens)
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.T(i18n.MsgPullRequestOpened, created.Number, created.URL))
+	return nil
+}
+
+// describePullRequest gathers the branch's commits and merge-base diff and
+// asks the model for a title and body. An empty base selects the remote's
+// default branch, and the base is compared through its remote-tracking ref
+// when one exists; an empty templatePath looks for the repository template.
+func describePullRequest(ctx context.Context, dir, remote, base, templatePath string, maxTokens int) (ai.PullRequestDescription, error) {
+	var desc ai.PullRequestDescription
+	log := logger.L().With("command", "pr describe", "path", dir)
+
+	if base == "" {
+		detected, err := git.DefaultBranch(ctx, dir, remote)
+		if err != nil {
+			return desc, fmt.Errorf("%w; pass --base", err)
+		}
+		base = detected
+	}
+
+	repoInfo, err := git.GetRepoInfo(ctx, dir)
+	if err != nil {
+		return desc, err
+	}
+	if repoInfo.Branch == base {
+		return desc, fmt.Errorf("current branch is the base branch %q; switch to the feature branch first", base)
+	}
+
+	mergeBase, err := git.MergeBase(ctx, dir, git.BaseRef(ctx, dir, remote, base), "HEAD")
+	if err != nil {
+		return desc, fmt.Errorf("failed to find merge-base with %s: %w", base, err)
+	}
+	revRange := mergeBase + "..HEAD"
+
+	commits, err := git.CommitLog(ctx, dir, revRange)
+	if err != nil {
+		return desc, err
+	}
+	if len(commits) == 0 {
+		return desc, fmt.Errorf("no commits on %s since it diverged from %s", repoInfo.Branch, base)
+	}
+
+	diff, err := git.LoadRangeDiff(ctx, dir, revRange)
+	if err != nil {
+		return desc, err
+	}
+	diff, skipped, err := filterDiffForAI(ctx, dir, diff)
+	if err != nil {
+		return desc, err
+	}
+
+	template, err := loadPullRequestTemplate(ctx, dir, templatePath)
+	if err != nil {
+		return desc, err
+	}
+
+	client, err := newAIClient(ctx, dir, maxTokens)
+	if err != nil {
+		return desc, err
+	}
+
+	log.InfoContext(ctx, "Requesting pull request description",
+		"base", base, "commits", len(commits), "template", template != "", "language", language.Tag)
+
+	return client.DescribePullRequest(ctx, ai.PullRequestRequest{
+		Diff:         diff.String(),
+		RepoInfo:     repoInfo,
+		Base:         base,
+		Commits:      commits,
+		SkippedFiles: skipped,
+		Template:     template,
+		Language:     language.Tag,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// loadPullRequestTemplate reads path, or the first template found in the
+// repository when path is empty. A missing template is not an error.
+func loadPullRequestTemplate(ctx context.Context, dir, path string) (string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read pull request template: %w", err)
+		}
+		return string(data), nil
+	}
+
+	root, err := git.TopLevel(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range pullRequestTemplates {
+		data, err := os.ReadFile(filepath.Join(root, candidate))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// formatPullRequest renders a description as the title, a blank line and the body.
+func formatPullRequest(desc ai.PullRequestDescription) string {
+	return strings.TrimSpace(desc.Title + "\n\n" + desc.Body)
+}
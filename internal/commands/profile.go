@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/secret"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+var (
+	profileCmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Show the active AI profile and all configured profiles",
+		Args:  cobra.NoArgs,
+		RunE:  runProfile,
+	}
+	// profileName is set by the global --profile flag.
+	profileName string
+)
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+}
+
+func runProfile(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	name, profile, source, err := selectProfile(ctx, cfg, wd)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		fmt.Println("Active profile: none (using top-level Gemini settings)")
+	} else {
+		fmt.Printf("Active profile: %s (selected by %s)\n", name, source)
+		fmt.Printf("  provider:  %s\n", profileProvider(profile))
+		if profile.Model != "" {
+			fmt.Printf("  model:     %s\n", profile.Model)
+		}
+		if profile.BaseURL != "" {
+			fmt.Printf("  base URL:  %s\n", profile.BaseURL)
+		}
+		fmt.Printf("  redaction: %s\n", redactionLabel(profile))
+	}
+
+	if len(cfg.Profiles) == 0 {
+		fmt.Println("No profiles configured. Add a \"profiles\" object to your SmartGit config.json.")
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for n := range cfg.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Configured profiles:")
+	for _, n := range names {
+		marker := " "
+		if n == name {
+			marker = "*"
+		}
+		p := cfg.Profiles[n]
+		line := fmt.Sprintf("%s %s  (%s", marker, n, profileProvider(p))
+		if len(p.Remotes) > 0 {
+			line += ", remotes: " + strings.Join(p.Remotes, " ")
+		}
+		fmt.Println(line + ")")
+	}
+	return nil
+}
+
+// selectProfile resolves the active profile for the repository at dir.
+func selectProfile(ctx context.Context, cfg config.Config, dir string) (string, config.Profile, config.ProfileSource, error) {
+	var remote string
+	if info, err := git.GetRepoInfo(ctx, dir); err == nil {
+		remote = info.Remote
+	}
+	return cfg.SelectProfile(profileName, remote)
+}
+
+// newAIClient builds the AI client for the active profile, falling back to
+// the top-level Gemini configuration when no profile applies.
+func newAIClient(ctx context.Context, dir string, maxTokens int) (*ai.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name, profile, source, err := selectProfile(ctx, cfg, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		apiKey, err := resolveAPIKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return ai.NewClient(apiKey, maxTokens), nil
+	}
+
+	logger.L().InfoContext(ctx, "Using AI profile", "profile", name, "source", source,
+		"provider", profileProvider(profile), "redaction", redactionLabel(profile))
+
+	apiKey, err := resolveProfileAPIKey(ctx, cfg, name, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return ai.NewClientWithOptions(ai.Options{
+		Provider:  ai.Provider(profileProvider(profile)),
+		APIKey:    apiKey,
+		Model:     profile.Model,
+		BaseURL:   profile.BaseURL,
+		MaxTokens: maxTokens,
+		Redact:    strings.EqualFold(profile.Redaction, "strict"),
+	}), nil
+}
+
+// resolveProfileAPIKey looks up the key for a profile. Profiles never fall
+// back to GEMINI_API_KEY or the top-level key, so work and personal keys
+// cannot be mixed up.
+func resolveProfileAPIKey(ctx context.Context, cfg config.Config, name string, profile config.Profile) (string, error) {
+	if env := strings.TrimSpace(profile.APIKeyEnv); env != "" {
+		if key := strings.TrimSpace(os.Getenv(env)); key != "" {
+			return key, nil
+		}
+	}
+
+	secretName := profileSecretName(name)
+	store, err := openSecretStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	if store != nil {
+		key, err := store.Get(ctx, secretName)
+		if err != nil && !errors.Is(err, secret.ErrNotFound) {
+			return "", err
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			return key, nil
+		}
+	}
+
+	if key := strings.TrimSpace(profile.APIKey); key != "" {
+		return key, nil
+	}
+
+	isGemini := profileProvider(profile) == string(ai.ProviderGemini)
+	fmt.Println(i18n.T(i18n.MsgProfileKeyMissing, name))
+	if isGemini {
+		fmt.Print(i18n.T(i18n.MsgProfileKeyEnter))
+	} else {
+		fmt.Print(i18n.T(i18n.MsgProfileKeyOptional))
+	}
+	reader := bufio.NewReader(os.Stdin)
+	key, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		if isGemini {
+			return "", errors.New("API key must not be empty")
+		}
+		return "", nil
+	}
+
+	if store != nil {
+		err := store.Set(ctx, secretName, key)
+		if errors.Is(err, secret.ErrReadOnly) {
+			fmt.Printf("The %s secret store is read-only; add %s with your credential helper to avoid this prompt.\n", store.Name(), secretName)
+			return key, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Printf("API key saved to the %s secret store.\n", store.Name())
+		return key, nil
+	}
+
+	profile.APIKey = key
+	cfg.Profiles[name] = profile
+	if err := config.Save(cfg); err != nil {
+		return "", err
+	}
+	fmt.Printf("API key saved to SmartGit config for profile %q.\n", name)
+	return key, nil
+}
+
+// profileSecretName is the secret store entry holding a profile's API key.
+// The prefix keeps a profile named "gemini" from sharing the top-level key.
+func profileSecretName(name string) string {
+	return "profile_" + name + "_api_key"
+}
+
+func profileProvider(p config.Profile) string {
+	provider := strings.ToLower(strings.TrimSpace(p.Provider))
+	if provider == "" {
+		return string(ai.ProviderGemini)
+	}
+	return provider
+}
+
+func redactionLabel(p config.Profile) string {
+	if strings.EqualFold(p.Redaction, "strict") {
+		return "strict"
+	}
+	return "off"
+}
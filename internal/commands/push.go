@@ -10,19 +10,46 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
 	"github.com/vinhtran/git-smart/pkg/logger"
 )
 
 type pushOptions struct {
+	force   bool
+	remote  string
+	to      string
+	pr      bool
+	noPR    bool
+	draft   bool
+	base    string
 	timeout time.Duration
+	// prMaxTokens and prTimeout apply to the pull request description.
+	prMaxTokens int
+	prTimeout   time.Duration
 }
 
 var (
 	pushCmd = &cobra.Command{
 		Use:     "push",
 		Aliases: []string{"p"},
-		Short:   "Push the current branch to origin, setting upstream if needed",
-		RunE:    runPush,
+		Short:   "Push the current branch, setting upstream if needed",
+		Long: `Push the current branch, setting upstream if needed.
+
+Before pushing, sg fetches the remote branch. If it has commits your
+branch lacks, sg offers to rebase onto it instead of letting the push be
+rejected.
+
+--force is always a --force-with-lease against the remote commit sg just
+fetched: the push fails if someone pushed in the meantime, and sg lists
+the remote commits that would be overwritten before asking. Protected
+branches (main, master, develop, dev) are never force-pushed.`,
+		Example: `  sg p
+  sg p --force
+  sg p --remote fork
+  sg p --to feature/login-v2`,
+		Args: cobra.NoArgs,
+		RunE: runPush,
 	}
 	pushOpts pushOptions
 )
@@ -30,7 +57,17 @@ var (
 func init() {
 	rootCmd.AddCommand(pushCmd)
 
+	pushCmd.Flags().BoolVarP(&pushOpts.force, "force", "f", false, "Overwrite the remote branch safely (--force-with-lease), e.g. after a rebase")
+	pushCmd.Flags().StringVar(&pushOpts.remote, "remote", "", "Remote to push to (default: the branch's remote, or origin)")
+	pushCmd.Flags().StringVar(&pushOpts.to, "to", "", "Branch on the remote to push to (default: the same name as the local branch)")
+	pushCmd.Flags().BoolVar(&pushOpts.pr, "pr", false, "Open a pull request after pushing without asking")
+	pushCmd.Flags().BoolVar(&pushOpts.noPR, "no-pr", false, "Do not offer to open a pull request")
+	pushCmd.Flags().BoolVar(&pushOpts.draft, "draft", false, "Open the pull request as a draft")
+	pushCmd.Flags().StringVar(&pushOpts.base, "base", "", "Target branch for the pull request (default: the remote's default branch)")
 	pushCmd.Flags().DurationVar(&pushOpts.timeout, "timeout", 45*time.Second, "Timeout for the git push operation")
+	pushCmd.Flags().IntVar(&pushOpts.prMaxTokens, "pr-max-tokens", 1024, "Maximum tokens for the pull request description")
+	pushCmd.Flags().DurationVar(&pushOpts.prTimeout, "pr-timeout", 60*time.Second, "Timeout for opening the pull request, including its AI description")
+	pushCmd.MarkFlagsMutuallyExclusive("pr", "no-pr")
 }
 
 func runPush(cmd *cobra.Command, args []string) error {
@@ -52,29 +89,39 @@ func runPush(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if branch == "" {
+	if branch == "" || branch == "HEAD" {
 		return fmt.Errorf("could not determine current branch")
 	}
 
+	remote := pushOpts.remote
+	if remote == "" {
+		if remote = git.BranchRemote(ctx, wd, branch); remote == "" || remote == "." {
+			remote = "origin"
+		}
+	}
+
 	// If we are on a protected branch (main/master/develop/dev), suggest creating
 	// a feature branch derived from the latest commit message and pushing that
 	// instead of pushing directly to the protected branch.
-	if isProtectedBranch(branch) {
+	if pushOpts.to == "" && isProtectedBranch(branch) {
+		if pushOpts.force {
+			return fmt.Errorf("refusing to force-push protected branch %q", branch)
+		}
+
 		subject, err := git.LastCommitSubject(ctx, wd)
 		if err != nil {
 			return err
 		}
 		suggested := deriveBranchNameFromCommit(subject)
 
-		fmt.Printf("On protected branch '%s'.\n", branch)
-		fmt.Printf("Suggested branch: %s\n", suggested)
-		fmt.Print("Create, switch, and push this branch to origin? (Y/n): ")
+		fmt.Println(i18n.T(i18n.MsgProtectedBranch, branch))
+		fmt.Println(i18n.T(i18n.MsgSuggestedBranch, suggested))
+		fmt.Print(i18n.T(i18n.MsgCreateAndPush, remote))
 
 		reader := bufio.NewReader(os.Stdin)
 		answer, _ := reader.ReadString('\n')
-		answer = strings.ToLower(strings.TrimSpace(answer))
-		if answer != "" && answer != "y" && answer != "yes" {
-			fmt.Println("Push aborted. No branch was created or pushed.")
+		if strings.TrimSpace(answer) != "" && !i18n.IsYes(answer) {
+			fmt.Println(i18n.T(i18n.MsgPushAborted))
 			return nil
 		}
 
@@ -87,24 +134,172 @@ func runPush(cmd *cobra.Command, args []string) error {
 		branch = suggested
 	}
 
+	dest := pushOpts.to
+	if dest == "" {
+		dest = branch
+	}
+	if pushOpts.force && isProtectedBranch(dest) {
+		return fmt.Errorf("refusing to force-push protected branch %q", dest)
+	}
+
+	log.InfoContext(ctx, "Fetching remote branch before pushing", "remote", remote, "dest", dest)
+	remoteHead, err := git.FetchBranch(ctx, wd, remote, dest)
+	if err != nil {
+		return err
+	}
+	if remoteHead != "" {
+		proceed, err := checkRemoteBranch(ctx, wd, remote+"/"+dest)
+		if err != nil || !proceed {
+			return err
+		}
+	}
+
 	hasUpstream, err := git.HasUpstream(ctx, wd)
 	if err != nil {
 		return err
 	}
 
+	target := remote
+	if dest != branch {
+		target = remote + "/" + dest
+	}
+
+	log.InfoContext(ctx, "Pushing branch", "branch", branch, "remote", remote, "dest", dest,
+		"force_with_lease", pushOpts.force, "set_upstream", !hasUpstream)
+	err = git.Push(ctx, wd, git.PushOptions{
+		Remote:         remote,
+		Branch:         branch,
+		Dest:           dest,
+		SetUpstream:    !hasUpstream,
+		ForceWithLease: pushOpts.force,
+		Expect:         remoteHead,
+	})
+	pushed := fmt.Sprintf("%s %s:%s", remote, branch, dest)
+	if pushOpts.force {
+		pushed += " (force-with-lease)"
+	}
+	head, _ := git.RevParse(ctx, wd, "HEAD")
+	recordAction(ctx, wd, "sg p", journal.Entry{Action: journal.ActionPush, Branch: branch, Commit: head, Target: pushed}, err)
+	if err != nil {
+		if pushOpts.force && strings.Contains(err.Error(), "stale info") {
+			return fmt.Errorf("%s/%s changed while pushing; nothing was overwritten. Run 'sg p --force' again to review the new commits: %w", remote, dest, err)
+		}
+		return err
+	}
+
 	if hasUpstream {
-		log.InfoContext(ctx, "Pushing current branch to origin", "branch", branch)
-		if err := git.PushCurrentBranch(ctx, wd, false); err != nil {
-			return err
+		fmt.Println(i18n.T(i18n.MsgPushed, branch, target))
+	} else {
+		fmt.Println(i18n.T(i18n.MsgPushedWithUpstream, branch, target))
+	}
+	return offerPullRequest(cmd.Context(), wd, remote, dest, !hasUpstream)
+}
+
+// checkRemoteBranch compares HEAD with the freshly fetched remote branch
+// ref. When the remote has commits HEAD lacks, a normal push offers to
+// rebase first (by default only when HEAD is purely behind) and a force push
+// lists what would be overwritten. It reports whether the push should go
+// ahead.
+func checkRemoteBranch(ctx context.Context, dir, ref string) (bool, error) {
+	ahead, behind, err := git.AheadBehind(ctx, dir, "HEAD", ref)
+	if err != nil || behind == 0 {
+		return err == nil, err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	if pushOpts.force {
+		lost, err := git.CommitLog(ctx, dir, "HEAD.."+ref)
+		if err != nil {
+			return false, err
+		}
+		fmt.Println(i18n.T(i18n.MsgForceDiscard, len(lost), ref))
+		for _, c := range lost {
+			fmt.Printf("  %s %s (%s)\n", c.ShortHash(), c.Subject, c.Author)
+		}
+		fmt.Print(i18n.T(i18n.MsgForceConfirm))
+		answer, _ := reader.ReadString('\n')
+		if !i18n.IsYes(answer) {
+			fmt.Println(i18n.T(i18n.MsgForceAborted))
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if ahead > 0 {
+		// Diverged history usually means local commits were amended or
+		// rebased, where replaying them onto the old ones would duplicate
+		// work, so rebasing is offered but not the default.
+		fmt.Println(i18n.T(i18n.MsgBranchDiverged, ref, ahead, behind))
+		fmt.Println(i18n.T(i18n.MsgDivergedForceHint, ref))
+		fmt.Print(i18n.T(i18n.MsgRebaseDiverged, ref))
+		answer, _ := reader.ReadString('\n')
+		if !i18n.IsYes(answer) {
+			return false, fmt.Errorf("%s has diverged from your branch; run 'sg p --force' to overwrite it, or rebase onto it first", ref)
 		}
-		fmt.Printf("Pushed branch '%s' to origin.\n", branch)
+	} else {
+		fmt.Println(i18n.T(i18n.MsgBranchBehind, behind, ref))
+		fmt.Print(i18n.T(i18n.MsgRebaseBeforePush, ref))
+		answer, _ := reader.ReadString('\n')
+		if strings.TrimSpace(answer) != "" && !i18n.IsYes(answer) {
+			return false, fmt.Errorf("%s has commits your branch does not; rebase onto it first, or use --force to overwrite them", ref)
+		}
+	}
+
+	logger.L().InfoContext(ctx, "Rebasing before push", "path", dir, "onto", ref)
+	recordUndoPoint(ctx, dir, "sg p")
+	err = git.Rebase(ctx, dir, ref)
+	recordAction(ctx, dir, "sg p", journal.Entry{Action: journal.ActionRebase, Target: ref}, err)
+	if err != nil {
+		if op, _ := git.InProgress(ctx, dir); op == git.OperationRebase {
+			return false, fmt.Errorf("rebase stopped on conflicts; run 'sg resolve', then 'sg p' again: %w", err)
+		}
+		return false, err
+	}
+	fmt.Println(i18n.T(i18n.MsgRebasedOnto, ref))
+	return true, nil
+}
+
+// offerPullRequest opens a pull request after a push. With --pr it does so
+// unconditionally; otherwise it asks after the first push of a branch, when
+// the forge can be detected and a token is configured.
+func offerPullRequest(parent context.Context, dir, remote, branch string, firstPush bool) error {
+	if pushOpts.noPR || (!pushOpts.pr && (!firstPush || isProtectedBranch(branch))) {
 		return nil
 	}
 
-	log.InfoContext(ctx, "No upstream set for current branch, pushing with -u", "branch", branch)
-	if err := git.PushCurrentBranch(ctx, wd, true); err != nil {
-		return err
+	log := logger.L().With("command", "push", "path", dir)
+
+	if !pushOpts.pr {
+		ctx := parent
+		f, err := openForge(ctx, dir, remote)
+		if err != nil {
+			log.InfoContext(ctx, "Not offering a pull request", "error", err)
+			return nil
+		}
+		base := pushOpts.base
+		if base == "" {
+			if base, err = git.DefaultBranch(ctx, dir, remote); err != nil {
+				log.InfoContext(ctx, "Not offering a pull request", "error", err)
+				return nil
+			}
+		}
+		fmt.Print(i18n.T(i18n.MsgOpenPullRequest, f.Name(), branch, base))
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		if !i18n.IsYes(answer) {
+			return nil
+		}
+	}
+
+	// The timeout starts after the prompt so waiting for an answer does not
+	// eat into the time for the description.
+	ctx, cancel := context.WithTimeout(parent, pushOpts.prTimeout)
+	defer cancel()
+	created, err := createPullRequest(ctx, dir, remote, branch, pushOpts.base, pushOpts.draft, pushOpts.prMaxTokens)
+	if err != nil {
+		return fmt.Errorf("branch was pushed, but opening the pull request failed: %w", err)
 	}
-	fmt.Printf("Pushed branch '%s' to origin and set upstream tracking.\n", branch)
+	fmt.Println(i18n.T(i18n.MsgPullRequestOpened, created.Number, created.URL))
 	return nil
 }
@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/changelog"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
+	"github.com/vinhtran/git-smart/internal/release"
+	"github.com/vinhtran/git-smart/internal/semver"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+type releaseOptions struct {
+	bump      string
+	version   string
+	changelog bool
+	push      bool
+	remote    string
+	dryRun    bool
+	yes       bool
+	timeout   time.Duration
+}
+
+var (
+	releaseCmd = &cobra.Command{
+		Use:   "release",
+		Short: "Bump the version, commit, tag and optionally push a release",
+		Long: `Bump the version, commit, tag and optionally push a release.
+
+The next version is derived from the commits since the latest tag: a
+breaking change ("!" or a BREAKING CHANGE footer) bumps the major version,
+a feat commit the minor version, anything else the patch version.
+
+The version is written to VERSION and to "Current" constants under
+internal/version by default. List other files in .smartgit/release.json:
+
+  {"files": [{"path": "VERSION"},
+             {"path": "internal/version/version.go", "pattern": "const Current = \"{version}\""}],
+   "tag_prefix": "v"}`,
+		Example: `  sg release --dry-run
+  sg release
+  sg release --bump minor --push
+  sg release --version 1.0.0-rc.1`,
+		Args: cobra.NoArgs,
+		RunE: runRelease,
+	}
+	releaseOpts releaseOptions
+)
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+
+	releaseCmd.Flags().StringVar(&releaseOpts.bump, "bump", "", "Force the increment (major|minor|patch) instead of deriving it from commits")
+	releaseCmd.Flags().StringVar(&releaseOpts.version, "version", "", "Release this exact version")
+	releaseCmd.Flags().BoolVar(&releaseOpts.changelog, "changelog", true, "Add a section to CHANGELOG.md when the file exists")
+	releaseCmd.Flags().BoolVar(&releaseOpts.push, "push", false, "Push the release commit and tag")
+	releaseCmd.Flags().StringVar(&releaseOpts.remote, "remote", "origin", "Remote to push to with --push")
+	releaseCmd.Flags().BoolVar(&releaseOpts.dryRun, "dry-run", false, "Show the planned release without changing anything")
+	releaseCmd.Flags().BoolVarP(&releaseOpts.yes, "yes", "y", false, "Do not ask for confirmation")
+	releaseCmd.Flags().DurationVar(&releaseOpts.timeout, "timeout", 60*time.Second, "Timeout for the release")
+	releaseCmd.MarkFlagsMutuallyExclusive("bump", "version")
+}
+
+func runRelease(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), releaseOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "release", "path", wd)
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+	root, err := git.TopLevel(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	status, err := git.StatusPorcelain(ctx, wd)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) != "" && !releaseOpts.dryRun {
+		return errors.New("working tree has uncommitted changes; commit or stash them before releasing")
+	}
+
+	cfg, explicit, err := release.LoadConfig(root)
+	if err != nil {
+		return err
+	}
+	files := cfg.Files
+	if len(files) == 0 {
+		files, explicit = release.DefaultFiles, false
+	}
+
+	// The current version is the latest tag, or the version files for the
+	// first release.
+	lastTag, err := git.LatestTag(ctx, wd, "HEAD")
+	if err != nil {
+		return err
+	}
+	var current semver.Version
+	revRange := "HEAD"
+	if lastTag != "" {
+		if current, err = semver.Parse(strings.TrimPrefix(lastTag, cfg.Prefix())); err != nil {
+			return fmt.Errorf("latest tag %s is not a semantic version: %w", lastTag, err)
+		}
+		revRange = lastTag + "..HEAD"
+	} else if v, ok := release.CurrentFromFiles(root, files); ok {
+		current = v
+	}
+
+	commits, err := git.CommitLog(ctx, wd, revRange)
+	if err != nil {
+		return err
+	}
+
+	next, bump, err := nextVersion(current, commits)
+	if err != nil {
+		return err
+	}
+	next.Prefix = ""
+	tag := cfg.Prefix() + next.String()
+	if git.IsTag(ctx, wd, tag) {
+		return fmt.Errorf("tag %s already exists", tag)
+	}
+
+	changes, err := release.PlanFiles(root, files, explicit, next)
+	if err != nil {
+		return err
+	}
+
+	var section changelog.Release
+	changelogPath := filepath.Join(root, "CHANGELOG.md")
+	_, statErr := os.Stat(changelogPath)
+	withChangelog := releaseOpts.changelog && statErr == nil
+	if withChangelog {
+		section = changelog.Build(next.String(), time.Now(), commits, changelog.Options{})
+	}
+
+	from := lastTag
+	if from == "" {
+		from = "(no tag)"
+	}
+	fmt.Printf("Release %s -> %s (%s bump, %d commits since %s)\n", displayVersion(current, lastTag), tag, bump, len(commits), from)
+	for _, c := range changes {
+		fmt.Printf("  update %s\n", c.Path)
+	}
+	if withChangelog {
+		fmt.Printf("  update CHANGELOG.md (%d entries)\n", len(section.Entries))
+	}
+	fmt.Printf("  commit \"chore(release): %s\" and tag %s\n", tag, tag)
+	if releaseOpts.push {
+		fmt.Printf("  push HEAD and %s to %s\n", tag, releaseOpts.remote)
+	}
+
+	if releaseOpts.dryRun {
+		return nil
+	}
+	if !releaseOpts.yes {
+		fmt.Print(i18n.T(i18n.MsgProceed))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !i18n.IsYes(answer) {
+			fmt.Println(i18n.T(i18n.MsgReleaseAborted))
+			return nil
+		}
+	}
+
+	var staged []string
+	for _, c := range changes {
+		if err := os.WriteFile(filepath.Join(root, c.Path), []byte(c.Content), 0o644); err != nil {
+			return err
+		}
+		staged = append(staged, c.Path)
+	}
+	if withChangelog {
+		existing, err := os.ReadFile(changelogPath)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(changelogPath, []byte(changelog.Prepend(string(existing), section)), 0o644); err != nil {
+			return err
+		}
+		staged = append(staged, "CHANGELOG.md")
+	}
+
+	message := "chore(release): " + tag
+	if len(staged) > 0 {
+		log.InfoContext(ctx, "Committing release", "files", staged)
+		recordUndoPoint(ctx, root, "sg release")
+		err := git.AddPaths(ctx, root, staged...)
+		recordAction(ctx, root, "sg release", journal.Entry{Action: journal.ActionStage, Files: staged}, err)
+		if err != nil {
+			return err
+		}
+		err = git.Commit(ctx, root, message)
+		entry := journal.Entry{Action: journal.ActionCommit, Files: staged}
+		if err == nil {
+			entry.Commit, _ = git.RevParse(ctx, root, "HEAD")
+		}
+		recordAction(ctx, root, "sg release", entry, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	tagMessage := "Release " + tag
+	if withChangelog && len(section.Entries) > 0 {
+		tagMessage += "\n\n" + section.Markdown()
+	}
+	err = git.CreateAnnotatedTag(ctx, root, tag, tagMessage)
+	recordAction(ctx, root, "sg release", journal.Entry{Action: journal.ActionTag, Target: tag}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created release commit and tag %s.\n", tag)
+
+	if !releaseOpts.push {
+		fmt.Printf("Push it with: git push %s HEAD %s\n", releaseOpts.remote, tag)
+		return nil
+	}
+	log.InfoContext(ctx, "Pushing release", "remote", releaseOpts.remote, "tag", tag)
+	err = git.PushRefs(ctx, root, releaseOpts.remote, "HEAD", tag)
+	recordAction(ctx, root, "sg release", journal.Entry{Action: journal.ActionPush, Target: releaseOpts.remote + " HEAD " + tag}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pushed %s to %s.\n", tag, releaseOpts.remote)
+	return nil
+}
+
+// nextVersion applies --version, --bump or the bump derived from commits.
+func nextVersion(current semver.Version, commits []git.LogEntry) (semver.Version, semver.Bump, error) {
+	if releaseOpts.version != "" {
+		v, err := semver.Parse(releaseOpts.version)
+		if err != nil {
+			return v, semver.BumpNone, err
+		}
+		if semver.Compare(v, current) <= 0 {
+			return v, semver.BumpNone, fmt.Errorf("version %s is not newer than %s", v, current)
+		}
+		return v, semver.BumpNone, nil
+	}
+
+	if releaseOpts.bump != "" {
+		bump, err := semver.ParseBump(releaseOpts.bump)
+		if err != nil {
+			return semver.Version{}, bump, err
+		}
+		return current.Next(bump), bump, nil
+	}
+
+	bump := release.NextBump(commits)
+	if bump == semver.BumpNone {
+		return semver.Version{}, bump, errors.New("no commits since the last release; nothing to release")
+	}
+	return current.Next(bump), bump, nil
+}
+
+func displayVersion(v semver.Version, tag string) string {
+	if tag != "" {
+		return tag
+	}
+	v.Prefix = ""
+	return v.String()
+}
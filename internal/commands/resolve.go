@@ -0,0 +1,468 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/conflict"
+	"github.com/vinhtran/git-smart/internal/filter"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+// conflictContextLines is how many unconflicted lines around a hunk are
+// sent to the model.
+const conflictContextLines = 15
+
+type resolveOptions struct {
+	cont      bool
+	abort     bool
+	yes       bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+var (
+	resolveCmd = &cobra.Command{
+		Use:   "resolve",
+		Short: "Resolve merge and rebase conflicts with AI suggestions",
+		Long: `Resolve merge and rebase conflicts with AI suggestions.
+
+For every conflicted region the model proposes merged code and explains
+why. Accept it, edit it in your git editor, take one side, or skip it.
+Files whose conflicts are all resolved are staged; once nothing is left,
+sg offers to continue (or abort) the rebase, merge or cherry-pick.
+
+Conflicts are read with their common ancestor (diff3 style) so both
+sides' intent is visible. During a rebase "ours" is the branch being
+rebased onto and "theirs" is your commit being replayed.`,
+		Example: `  sg resolve
+  sg resolve --continue
+  sg resolve --yes --continue
+  sg resolve --abort`,
+		Args: cobra.NoArgs,
+		RunE: runResolve,
+	}
+	resolveOpts resolveOptions
+
+	// errResolveQuit stops the session when the user quits at a prompt.
+	errResolveQuit = errors.New("resolve stopped by user")
+)
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+
+	resolveCmd.Flags().BoolVar(&resolveOpts.cont, "continue", false, "Continue the rebase or merge without asking once all conflicts are resolved")
+	resolveCmd.Flags().BoolVar(&resolveOpts.abort, "abort", false, "Abort the rebase or merge in progress and restore the previous state")
+	resolveCmd.Flags().BoolVarP(&resolveOpts.yes, "yes", "y", false, "Accept AI resolutions without asking (low-confidence ones are left for you)")
+	resolveCmd.Flags().IntVar(&resolveOpts.maxTokens, "max-tokens", 2048, "Maximum tokens for each AI resolution")
+	resolveCmd.Flags().DurationVar(&resolveOpts.timeout, "timeout", 30*time.Minute, "Timeout for the whole resolve session")
+	resolveCmd.MarkFlagsMutuallyExclusive("abort", "continue")
+}
+
+// resolveSession is the state shared by every file of one sg resolve run.
+type resolveSession struct {
+	dir      string
+	root     string
+	op       git.Operation
+	incoming *git.LogEntry
+	client   *ai.Client
+	matcher  *filter.Matcher
+	reader   *bufio.Reader
+	log      *slog.Logger
+}
+
+func runResolve(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), resolveOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "resolve", "path", wd)
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+
+	op, err := git.InProgress(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	if resolveOpts.abort {
+		err := git.AbortOperation(ctx, wd, op)
+		recordAction(ctx, wd, "sg resolve", journal.Entry{Action: journal.ActionAbort, Target: string(op)}, err)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Aborted the %s; the branch is back where it was before it started.\n", op)
+		return nil
+	}
+
+	root, err := git.TopLevel(ctx, wd)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	matcher, err := filter.Load(root, cfg.Ignore)
+	if err != nil {
+		return err
+	}
+
+	s := &resolveSession{
+		dir:     wd,
+		root:    root,
+		matcher: matcher,
+		reader:  bufio.NewReader(os.Stdin),
+		log:     log,
+	}
+
+	// A rebase can stop again at a later commit, so keep going until it
+	// finishes or the user leaves.
+	for {
+		s.op = op
+		s.incoming = incomingCommit(ctx, wd, op)
+
+		files, err := git.ConflictedFiles(ctx, wd)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 && op == git.OperationNone {
+			fmt.Println("No conflicts to resolve.")
+			return nil
+		}
+
+		if len(files) > 0 {
+			if s.client == nil {
+				if s.client, err = newAIClient(ctx, wd, resolveOpts.maxTokens); err != nil {
+					return err
+				}
+			}
+			log.InfoContext(ctx, "Resolving conflicts", "operation", op, "files", len(files))
+
+			for _, path := range files {
+				if err := s.resolveFile(ctx, path); err != nil {
+					if errors.Is(err, errResolveQuit) {
+						break
+					}
+					return err
+				}
+			}
+
+			if files, err = git.ConflictedFiles(ctx, wd); err != nil {
+				return err
+			}
+			if len(files) > 0 {
+				fmt.Println()
+				fmt.Println("Still conflicted:")
+				for _, f := range files {
+					fmt.Printf("  %s\n", f)
+				}
+				fmt.Println("Fix them and run 'sg resolve' again, or undo everything with 'sg resolve --abort'.")
+				return nil
+			}
+		}
+
+		if op == git.OperationNone {
+			fmt.Println("All conflicts resolved and staged.")
+			return nil
+		}
+
+		if !resolveOpts.cont {
+			fmt.Print(i18n.T(i18n.MsgResolveContinue, op))
+			answer, _ := s.reader.ReadString('\n')
+			switch {
+			case strings.EqualFold(strings.TrimSpace(answer), "a"):
+				err := git.AbortOperation(ctx, wd, op)
+				recordAction(ctx, wd, "sg resolve", journal.Entry{Action: journal.ActionAbort, Target: string(op)}, err)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Aborted the %s.\n", op)
+				return nil
+			case !i18n.IsYes(answer):
+				fmt.Printf("Left the %s paused. Continue later with 'sg resolve --continue' or 'git %s --continue'.\n", op, op)
+				return nil
+			}
+		}
+
+		log.InfoContext(ctx, "Continuing operation", "operation", op)
+		err = git.ContinueOperation(ctx, wd, op)
+		recordAction(ctx, wd, "sg resolve", journal.Entry{Action: journal.ActionContinue, Target: string(op)}, err)
+		if err != nil {
+			return err
+		}
+
+		next, err := git.InProgress(ctx, wd)
+		if err != nil {
+			return err
+		}
+		if next == git.OperationNone {
+			fmt.Printf("The %s completed.\n", op)
+			return nil
+		}
+		fmt.Printf("\nThe %s stopped again with new conflicts.\n", next)
+		op = next
+	}
+}
+
+// resolveFile walks the conflicts of one file. The file is rewritten with
+// whatever was resolved and staged once no conflict is left.
+func (s *resolveSession) resolveFile(ctx context.Context, path string) error {
+	full := filepath.Join(s.root, path)
+	fmt.Printf("\n== %s\n", path)
+
+	data, err := os.ReadFile(full)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("Deleted on one side and changed on the other. Keep it with 'git add', or delete it with 'git rm'.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		fmt.Printf("Binary file. Pick a side with 'git checkout --ours -- %s' or '--theirs', then 'git add' it.\n", path)
+		return nil
+	}
+
+	file, err := conflict.Parse(string(data))
+	if err != nil {
+		fmt.Printf("Cannot parse the conflict markers (%v); resolve it by hand.\n", err)
+		return nil
+	}
+	if len(file.Hunks()) == 0 {
+		fmt.Print(i18n.T(i18n.MsgResolveStage))
+		answer, _ := s.reader.ReadString('\n')
+		if i18n.IsYes(answer) {
+			err := git.AddPaths(ctx, s.root, path)
+			recordAction(ctx, s.root, "sg resolve", journal.Entry{Action: journal.ActionStage, Files: []string{path}}, err)
+			return err
+		}
+		return nil
+	}
+	if ignored, reason := s.matcher.Match(path); ignored {
+		fmt.Printf("Not sent to AI (%s); resolve it by hand.\n", reason)
+		return nil
+	}
+	if !file.HasDiff3() {
+		file = s.withBase(ctx, path, string(data), file)
+	}
+
+	info, err := os.Stat(full)
+	if err != nil {
+		return err
+	}
+
+	hunks := file.Hunks()
+	resolutions := make(map[*conflict.Hunk]string)
+	var quit error
+	for i, h := range hunks {
+		res, ok, err := s.resolveHunk(ctx, path, file, h, i+1, len(hunks))
+		if err != nil {
+			quit = err
+			break
+		}
+		if ok {
+			resolutions[h] = res
+		}
+	}
+
+	if len(resolutions) > 0 {
+		if err := os.WriteFile(full, []byte(file.Render(resolutions)), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if len(resolutions) == len(hunks) {
+		err := git.AddPaths(ctx, s.root, path)
+		recordAction(ctx, s.root, "sg resolve", journal.Entry{Action: journal.ActionStage, Files: []string{path}}, err)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Resolved and staged %s.\n", path)
+	} else if len(resolutions) > 0 {
+		fmt.Printf("Resolved %d of %d conflicts in %s; the rest keep their markers.\n", len(resolutions), len(hunks), path)
+	}
+	return quit
+}
+
+// resolveHunk shows one conflict with the AI proposal and asks what to do.
+// It reports false when the hunk is skipped.
+func (s *resolveSession) resolveHunk(ctx context.Context, path string, file conflict.File, h *conflict.Hunk, n, total int) (string, bool, error) {
+	fmt.Printf("\n-- conflict %d of %d (line %d)\n", n, total, h.StartLine)
+	fmt.Print(h.Markers())
+
+	before, after := file.Context(h, conflictContextLines)
+	req := ai.ConflictRequest{
+		Path:        path,
+		Operation:   string(s.op),
+		Ours:        strings.Join(h.Ours, ""),
+		Base:        strings.Join(h.Base, ""),
+		Theirs:      strings.Join(h.Theirs, ""),
+		HasBase:     h.HasBase,
+		OursLabel:   h.OursLabel,
+		TheirsLabel: h.TheirsLabel,
+		Incoming:    s.incoming,
+		Before:      before,
+		After:       after,
+		Language:    language.Tag,
+	}
+
+	s.log.InfoContext(ctx, "Requesting conflict resolution", "file", path, "hunk", n)
+	proposal, err := s.client.ResolveConflict(ctx, req)
+	hasProposal := err == nil
+	if hasProposal {
+		confidence := proposal.Confidence
+		if confidence == "" {
+			confidence = "unknown"
+		}
+		fmt.Printf("\nProposed resolution (confidence: %s):\n", confidence)
+		if proposal.Resolution == "" {
+			fmt.Println("(remove the whole region)")
+		}
+		fmt.Print(proposal.Resolution)
+		if proposal.Explanation != "" {
+			fmt.Printf("Why: %s\n", proposal.Explanation)
+		}
+	} else {
+		s.log.WarnContext(ctx, "AI resolution failed", "file", path, "hunk", n, "error", err)
+		fmt.Printf("\nNo AI proposal: %v\n", err)
+	}
+
+	if resolveOpts.yes {
+		if hasProposal && proposal.Confidence != "low" {
+			return proposal.Resolution, true, nil
+		}
+		fmt.Println("Left for manual resolution.")
+		return "", false, nil
+	}
+
+	for {
+		if hasProposal {
+			fmt.Print(i18n.T(i18n.MsgResolveChoice))
+		} else {
+			fmt.Print(i18n.T(i18n.MsgResolveChoiceManual))
+		}
+		answer, _ := s.reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a":
+			if hasProposal {
+				return proposal.Resolution, true, nil
+			}
+		case "e":
+			start := h.Markers()
+			if hasProposal {
+				start = proposal.Resolution
+			}
+			edited, err := editText(ctx, s.dir, start, "sg-resolve-*"+filepath.Ext(path))
+			if err != nil {
+				fmt.Printf("Editor failed: %v\n", err)
+				continue
+			}
+			if _, err := conflict.Parse(edited); err != nil || strings.Contains(edited, "<<<<<<<") {
+				fmt.Println("The edited text still contains conflict markers; try again or skip.")
+				continue
+			}
+			return edited, true, nil
+		case "o":
+			return strings.Join(h.Ours, ""), true, nil
+		case "t":
+			return strings.Join(h.Theirs, ""), true, nil
+		case "s":
+			return "", false, nil
+		case "q":
+			return "", false, errResolveQuit
+		}
+	}
+}
+
+// withBase rewrites path with diff3 markers when it still holds exactly
+// the conflict git produced, so the model sees the common ancestor. Files
+// the user already edited are left alone.
+func (s *resolveSession) withBase(ctx context.Context, path, current string, file conflict.File) conflict.File {
+	full := filepath.Join(s.root, path)
+	info, err := os.Stat(full)
+	if err != nil {
+		return file
+	}
+	restore := func() {
+		if err := os.WriteFile(full, []byte(current), info.Mode().Perm()); err != nil {
+			s.log.WarnContext(ctx, "Failed to restore file", "file", path, "error", err)
+		}
+	}
+
+	if _, err := git.Run(ctx, s.root, "checkout", "--conflict=merge", "--", path); err != nil {
+		return file
+	}
+	pristine, err := os.ReadFile(full)
+	if err != nil || stripMarkerLabels(string(pristine)) != stripMarkerLabels(current) {
+		restore()
+		return file
+	}
+	if err := git.RecreateConflict(ctx, s.root, path); err != nil {
+		restore()
+		return file
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		restore()
+		return file
+	}
+	parsed, err := conflict.Parse(string(data))
+	if err != nil || len(parsed.Hunks()) != len(file.Hunks()) {
+		restore()
+		return file
+	}
+	// Keep git's descriptive labels (e.g. the commit being replayed)
+	// instead of the generic ones checkout writes.
+	for i, h := range parsed.Hunks() {
+		h.OursLabel = file.Hunks()[i].OursLabel
+		h.TheirsLabel = file.Hunks()[i].TheirsLabel
+	}
+	s.log.DebugContext(ctx, "Recreated conflict with diff3 markers", "file", path)
+	return parsed
+}
+
+var markerLabelPattern = regexp.MustCompile(`(?m)^(<<<<<<<|\|\|\|\|\|\|\||>>>>>>>) .*$`)
+
+func stripMarkerLabels(s string) string {
+	return markerLabelPattern.ReplaceAllString(s, "$1")
+}
+
+// incomingCommit returns the commit being applied by op, if any.
+func incomingCommit(ctx context.Context, dir string, op git.Operation) *git.LogEntry {
+	refs := map[git.Operation]string{
+		git.OperationRebase:     "REBASE_HEAD",
+		git.OperationMerge:      "MERGE_HEAD",
+		git.OperationCherryPick: "CHERRY_PICK_HEAD",
+		git.OperationRevert:     "REVERT_HEAD",
+	}
+	ref, ok := refs[op]
+	if !ok {
+		return nil
+	}
+	commits, err := git.CommitLog(ctx, dir, "-1", ref)
+	if err != nil || len(commits) == 0 {
+		return nil
+	}
+	return &commits[0]
+}
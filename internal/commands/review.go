@@ -13,24 +13,53 @@ import (
 	"github.com/vinhtran/git-smart/internal/ai"
 	"github.com/vinhtran/git-smart/internal/config"
 	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/repocontext"
+	"github.com/vinhtran/git-smart/internal/report"
+	"github.com/vinhtran/git-smart/internal/secret"
 	"github.com/vinhtran/git-smart/pkg/logger"
 )
 
 type reviewOptions struct {
 	lastCommit bool
+	commit     string
+	base       string
+	files      []string
 	short      bool
 	raw        bool
-	language   string
+	format     string
+	failOn     string
+	baseline   string
+	update     bool
+	focus      []string
+	context    []string
+	budget     int
 	maxTokens  int
 	timeout    time.Duration
 }
 
 var (
 	reviewCmd = &cobra.Command{
-		Use:     "review",
+		Use:     "review [rev-range]",
 		Aliases: []string{"rv"},
 		Short:   "AI review for git diffs or commits",
-		RunE:    runReview,
+		Long: `AI review for git diffs or commits.
+
+Without arguments, staged changes are reviewed (or the working tree when
+nothing is staged). A revision range such as "main..feature" reviews the
+diff between the two revisions; a single revision such as "main" is
+treated as "main..HEAD" (use --commit to review a single commit instead).
+Revisions starting with "-" are rejected.`,
+		Example: `  sg rv
+  sg rv main..HEAD
+  sg rv main            # same as main..HEAD
+  sg rv --base main
+  sg rv --commit 1a2b3c4
+  sg rv --files internal/git/git.go,README.md
+  sg rv --base main --context functions,history
+  sg rv --base main --focus security,api`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runReview,
 	}
 	opts reviewOptions
 )
@@ -39,11 +68,34 @@ func init() {
 	rootCmd.AddCommand(reviewCmd)
 
 	reviewCmd.Flags().BoolVar(&opts.lastCommit, "last-commit", false, "Review the latest commit instead of staged changes")
+	reviewCmd.Flags().StringVar(&opts.commit, "commit", "", "Review a single commit by SHA or revision")
+	reviewCmd.Flags().StringVar(&opts.base, "base", "", "Review everything since the merge-base with this branch (e.g. main)")
+	reviewCmd.Flags().StringSliceVar(&opts.files, "files", nil, "Limit the review to these paths (comma-separated or repeated)")
 	reviewCmd.Flags().BoolVar(&opts.short, "short", true, "Return a concise summary instead of a full review")
 	reviewCmd.Flags().BoolVar(&opts.raw, "raw", false, "Print the raw response from Gemini without formatting")
-	reviewCmd.Flags().StringVar(&opts.language, "language", "en", "Language for the review response (en|vi)")
+	reviewCmd.Flags().StringVar(&opts.format, "format", "text", "Output format (text|prose|json|sarif|markdown)")
+	reviewCmd.Flags().StringVar(&opts.failOn, "fail-on", "", "Exit with status 2 when a new finding has at least this severity (info|low|medium|high|critical)")
+	reviewCmd.Flags().StringVar(&opts.baseline, "baseline", "", "Baseline file of accepted findings that should not fail the build")
+	reviewCmd.Flags().BoolVar(&opts.update, "update-baseline", false, "Write the current findings to the --baseline file")
+	reviewCmd.Flags().StringSliceVar(&opts.focus, "focus", nil, "Focus the review on these areas (security|perf|tests|api)")
+	reviewCmd.Flags().StringSliceVar(&opts.context, "context", nil, "Extra repository context for the reviewer (functions|files|readme|history|none)")
+	reviewCmd.Flags().IntVar(&opts.budget, "context-budget", 4000, "Approximate token budget for --context")
 	reviewCmd.Flags().IntVar(&opts.maxTokens, "max-tokens", 1024, "Maximum tokens for Gemini 2.5 Flash output")
 	reviewCmd.Flags().DurationVar(&opts.timeout, "timeout", 45*time.Second, "Timeout for the Gemini review request")
+
+	reviewCmd.MarkFlagsMutuallyExclusive("last-commit", "commit", "base")
+}
+
+// reviewTarget is the diff selected for review plus the context describing it.
+type reviewTarget struct {
+	diff    *git.Diff
+	mode    string
+	label   string
+	commits []git.LogEntry
+	// skipped summarizes files filtered out of diff before prompting.
+	skipped []string
+	// source locates the new file contents and earlier history for --context.
+	source repocontext.Source
 }
 
 func runReview(cmd *cobra.Command, args []string) error {
@@ -56,6 +108,34 @@ func runReview(cmd *cobra.Command, args []string) error {
 	}
 
 	log := logger.L().With("command", "review", "path", wd)
+
+	format, err := report.ParseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+
+	var failOn ai.Severity
+	if strings.TrimSpace(opts.failOn) != "" {
+		sev, ok := ai.ParseSeverity(opts.failOn)
+		if !ok {
+			return fmt.Errorf("invalid --fail-on severity %q (expected info, low, medium, high or critical)", opts.failOn)
+		}
+		failOn = sev
+	}
+	focus, err := parseFocus(opts.focus)
+	if err != nil {
+		return err
+	}
+	contextOpts, err := parseContextOptions(opts.context, opts.budget)
+	if err != nil {
+		return err
+	}
+	if opts.update && opts.baseline == "" {
+		return errors.New("--update-baseline requires --baseline <path>")
+	}
+	// Gating and baselines work on findings, so they always need a structured review.
+	structured := format.Structured() || failOn != "" || opts.baseline != ""
+
 	if err := git.EnsureRepository(ctx, wd); err != nil {
 		return err
 	}
@@ -65,60 +145,277 @@ func runReview(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	diff, mode, err := selectDiff(ctx, wd)
+	target, err := selectDiff(ctx, wd, args)
 	if err != nil {
 		return err
 	}
 
-	if strings.TrimSpace(diff) == "" {
-		fmt.Println("There are no changes to review.")
+	if len(target.diff.Files) > 0 {
+		target.diff, target.skipped, err = filterDiffForAI(ctx, wd, target.diff)
+		if err != nil {
+			return err
+		}
+		if len(target.diff.Files) == 0 && len(target.skipped) > 0 {
+			fmt.Fprintln(os.Stderr, i18n.T(i18n.MsgOnlySkippedChanged))
+			for _, s := range target.skipped {
+				fmt.Fprintf(os.Stderr, "- %s\n", s)
+			}
+		}
+	}
+
+	if len(target.diff.Files) == 0 {
+		if structured {
+			return report.Write(os.Stdout, format, report.Review{Target: target.label, Summary: i18n.T(i18n.MsgNoChangesToReview)})
+		}
+		fmt.Println(i18n.T(i18n.MsgNoChangesToReview))
 		return nil
 	}
 
-	apiKey, err := resolveAPIKey(ctx)
+	var bundle repocontext.Bundle
+	if contextOpts.Enabled() {
+		bundle, err = buildReviewContext(ctx, wd, target, contextOpts)
+		if err != nil {
+			return err
+		}
+		log.InfoContext(ctx, "Gathered repository context", "snippets", len(bundle.Snippets),
+			"history", len(bundle.History), "readme", bundle.Readme != nil, "truncated", bundle.Truncated)
+	}
+
+	client, err := newAIClient(ctx, wd, opts.maxTokens)
 	if err != nil {
 		return err
 	}
 
-	client := ai.NewClient(apiKey, opts.maxTokens)
-
 	request := ai.ReviewRequest{
-		Diff:      diff,
-		RepoInfo:  repoInfo,
-		Mode:      mode,
-		Language:  opts.language,
-		Short:     opts.short,
-		CreatedAt: time.Now(),
+		Diff:         target.diff.String(),
+		RepoInfo:     repoInfo,
+		Mode:         target.mode,
+		Target:       target.label,
+		Commits:      target.commits,
+		SkippedFiles: target.skipped,
+		Context:      bundle,
+		Focus:        focus,
+		Language:     language.Tag,
+		Short:        opts.short,
+		Structured:   structured,
+		CreatedAt:    time.Now(),
 	}
 
 	log.InfoContext(ctx, "Requesting Gemini 2.5 Flash review",
-		"mode", mode, "language", opts.language)
+		"mode", target.mode, "target", target.label, "commits", len(target.commits), "language", language.Tag, "format", format, "focus", focus)
 
 	resp, err := client.ReviewDiff(ctx, request)
 	if err != nil {
 		return err
 	}
 
-	printReview(resp.Text)
-	return nil
+	if !structured {
+		printReview(resp.Text)
+		return nil
+	}
+
+	topLevel, err := git.TopLevel(ctx, wd)
+	if err != nil {
+		return err
+	}
+	// Annotations show the reviewed revision of each file, not whatever is
+	// in the working tree now.
+	source := target.source
+	source.Root = topLevel
+
+	findings := resp.Findings
+	report.ResolveLocations(findings, target.diff)
+	var suppressed []ai.Finding
+	if opts.baseline != "" {
+		if opts.update {
+			if err := report.SaveBaseline(opts.baseline, findings); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Baseline updated with %d finding(s): %s\n", len(findings), opts.baseline)
+			return nil
+		}
+
+		baseline, err := report.LoadBaseline(opts.baseline)
+		if err != nil {
+			return err
+		}
+		findings, suppressed = baseline.Filter(findings)
+		log.InfoContext(ctx, "Applied review baseline", "baseline", opts.baseline,
+			"new", len(findings), "suppressed", len(suppressed))
+	}
+
+	if opts.raw {
+		printReview(resp.Text)
+	} else if err := report.Write(os.Stdout, format, report.Review{
+		Target:     target.label,
+		Summary:    resp.Summary,
+		Findings:   findings,
+		Suppressed: len(suppressed),
+		Diff:       target.diff,
+		Files: func(path string) (string, error) {
+			return source.ReadFile(ctx, path)
+		},
+	}); err != nil {
+		return err
+	}
+
+	return checkFailOn(cmd, findings, failOn)
+}
+
+// checkFailOn returns an exit code 2 error when any finding meets the
+// --fail-on threshold, so `sg rv` can be used as a CI gate.
+func checkFailOn(cmd *cobra.Command, findings []ai.Finding, threshold ai.Severity) error {
+	if threshold == "" {
+		return nil
+	}
+
+	failing := 0
+	for _, f := range findings {
+		if f.Severity.Rank() >= threshold.Rank() {
+			failing++
+		}
+	}
+	if failing == 0 {
+		return nil
+	}
+
+	// The usage text is noise in CI logs; the findings were already printed.
+	cmd.SilenceUsage = true
+	return &exitCodeError{
+		code: 2,
+		err:  fmt.Errorf("review failed: %d new finding(s) at or above %s severity", failing, threshold),
+	}
 }
 
-func selectDiff(ctx context.Context, dir string) (string, string, error) {
-	if opts.lastCommit {
-		diff, err := git.GetLastCommitDiff(ctx, dir)
-		return diff, "last-commit", err
+func selectDiff(ctx context.Context, dir string, args []string) (reviewTarget, error) {
+	var target reviewTarget
+
+	if len(args) > 0 && (opts.lastCommit || opts.commit != "" || opts.base != "") {
+		return target, errors.New("a revision range cannot be combined with --last-commit, --commit or --base")
+	}
+
+	switch {
+	case opts.lastCommit:
+		return commitTarget(ctx, dir, "HEAD", "last-commit", "latest commit")
+	case opts.commit != "":
+		return commitTarget(ctx, dir, opts.commit, "commit", "commit "+opts.commit)
+	case opts.base != "":
+		mergeBase, err := git.MergeBase(ctx, dir, opts.base, "HEAD")
+		if err != nil {
+			return target, fmt.Errorf("failed to find merge-base with %s: %w", opts.base, err)
+		}
+		label := fmt.Sprintf("changes since merge-base with %s (%s)", opts.base, shortRev(mergeBase))
+		return rangeTarget(ctx, dir, mergeBase+"..HEAD", "base", label)
+	case len(args) > 0:
+		revRange := strings.TrimSpace(args[0])
+		// A single revision reviews everything HEAD adds on top of it, like
+		// "git log main..": use --commit to review one commit's own patch.
+		if !strings.Contains(revRange, "..") {
+			revRange += "..HEAD"
+		}
+		return rangeTarget(ctx, dir, revRange, "range", "revision range "+revRange)
 	}
+
 	// Prefer staged changes; if none, fall back to working tree diff.
-	stagedDiff, err := git.GetStagedDiff(ctx, dir)
+	stagedDiff, err := git.LoadStagedDiff(ctx, dir, opts.files...)
+	if err != nil {
+		return target, err
+	}
+	if len(stagedDiff.Files) > 0 {
+		return reviewTarget{diff: stagedDiff, mode: "staged", label: "staged changes",
+			source: repocontext.Source{Rev: ":", HistoryRev: "HEAD"}}, nil
+	}
+
+	wtDiff, err := git.LoadWorkingTreeDiff(ctx, dir, opts.files...)
+	return reviewTarget{diff: wtDiff, mode: "working-tree", label: "working tree changes",
+		source: repocontext.Source{HistoryRev: "HEAD"}}, err
+}
+
+// commitTarget selects the patch of a single commit together with its message.
+func commitTarget(ctx context.Context, dir, rev, mode, label string) (reviewTarget, error) {
+	target := reviewTarget{mode: mode, label: label,
+		source: repocontext.Source{Rev: rev, HistoryRev: rev + "^"}}
+
+	diff, err := git.LoadCommitDiff(ctx, dir, rev, opts.files...)
+	if err != nil {
+		return target, err
+	}
+	target.diff = diff
+
+	commits, err := git.CommitLog(ctx, dir, "-1", rev)
+	if err != nil {
+		return target, err
+	}
+	target.commits = commits
+	return target, nil
+}
+
+// rangeTarget selects the diff of a revision range and the commits it contains.
+func rangeTarget(ctx context.Context, dir, revRange, mode, label string) (reviewTarget, error) {
+	from, to := splitRange(revRange)
+	target := reviewTarget{mode: mode, label: label,
+		source: repocontext.Source{Rev: to, HistoryRev: from}}
+
+	diff, err := git.LoadRangeDiff(ctx, dir, revRange, opts.files...)
 	if err != nil {
-		return "", "", err
+		return target, err
 	}
-	if strings.TrimSpace(stagedDiff) != "" {
-		return stagedDiff, "staged", nil
+	target.diff = diff
+
+	logArgs := []string{revRange}
+	if len(opts.files) > 0 {
+		logArgs = append(logArgs, "--")
+		logArgs = append(logArgs, opts.files...)
+	}
+	commits, err := git.CommitLog(ctx, dir, logArgs...)
+	if err != nil {
+		return target, err
+	}
+	target.commits = commits
+	return target, nil
+}
+
+// parseFocus validates --focus values, dropping duplicates.
+func parseFocus(values []string) ([]ai.Focus, error) {
+	var focus []ai.Focus
+	seen := make(map[ai.Focus]bool)
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		f, ok := ai.ParseFocus(v)
+		if !ok {
+			return nil, fmt.Errorf("invalid --focus value %q (expected security, perf, tests or api)", v)
+		}
+		if !seen[f] {
+			seen[f] = true
+			focus = append(focus, f)
+		}
+	}
+	return focus, nil
+}
+
+// splitRange returns both ends of "a..b" or "a...b", defaulting to HEAD.
+func splitRange(revRange string) (string, string) {
+	sep := ".."
+	if strings.Contains(revRange, "...") {
+		sep = "..."
+	}
+	from, to, _ := strings.Cut(revRange, sep)
+	if from == "" {
+		from = "HEAD"
+	}
+	if to == "" {
+		to = "HEAD"
 	}
+	return from, to
+}
 
-	wtDiff, err := git.GetWorkingTreeDiff(ctx, dir)
-	return wtDiff, "working-tree", err
+func shortRev(rev string) string {
+	if len(rev) > 7 {
+		return rev[:7]
+	}
+	return rev
 }
 
 func printReview(text string) {
@@ -129,7 +426,7 @@ func printReview(text string) {
 
 	divider := strings.Repeat("-", 60)
 	fmt.Println(divider)
-	fmt.Println("AI Review:")
+	fmt.Println(i18n.T(i18n.MsgReviewHeader))
 	fmt.Println(divider)
 	fmt.Println(text)
 	fmt.Println(divider)
@@ -144,13 +441,28 @@ func resolveAPIKey(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", err
 	}
+
+	store, err := openSecretStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	if store != nil {
+		key, err := store.Get(ctx, geminiAPIKeySecret)
+		if err != nil && !errors.Is(err, secret.ErrNotFound) {
+			return "", err
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			return key, nil
+		}
+	}
+
 	if key := strings.TrimSpace(cfg.GeminiAPIKey); key != "" {
 		return key, nil
 	}
 
-	fmt.Println("Gemini API key is not configured.")
-	fmt.Println("You can create a Gemini API key at: https://aistudio.google.com/api-keys")
-	fmt.Print("Enter your Gemini API key (it will be stored for future use): ")
+	fmt.Println(i18n.T(i18n.MsgAPIKeyMissing))
+	fmt.Println(i18n.T(i18n.MsgAPIKeyCreateAt, "https://aistudio.google.com/api-keys"))
+	fmt.Print(i18n.T(i18n.MsgAPIKeyEnter))
 	reader := bufio.NewReader(os.Stdin)
 	key, err := reader.ReadString('\n')
 	if err != nil {
@@ -161,6 +473,19 @@ func resolveAPIKey(ctx context.Context) (string, error) {
 		return "", errors.New("API key must not be empty")
 	}
 
+	if store != nil {
+		err := store.Set(ctx, geminiAPIKeySecret, key)
+		if errors.Is(err, secret.ErrReadOnly) {
+			fmt.Printf("The %s secret store is read-only; add %s with your credential helper to avoid this prompt.\n", store.Name(), geminiAPIKeySecret)
+			return key, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Printf("API key saved to the %s secret store.\n", store.Name())
+		return key, nil
+	}
+
 	cfg.GeminiAPIKey = key
 	if model := strings.TrimSpace(os.Getenv("GEMINI_MODEL")); model != "" {
 		cfg.GeminiModel = model
@@ -171,5 +496,6 @@ func resolveAPIKey(ctx context.Context) (string, error) {
 	}
 
 	fmt.Println("API key saved to SmartGit config.")
+	fmt.Println("Tip: run 'sg secrets migrate --to keyring' to keep it out of plaintext config.")
 	return key, nil
 }
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+)
+
+func TestParseFocus(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []ai.Focus
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{"security", []string{"security"}, []ai.Focus{ai.FocusSecurity}, false},
+		{"aliases", []string{"perf", "tests", "api"}, []ai.Focus{ai.FocusPerformance, ai.FocusTests, ai.FocusAPI}, false},
+		{"order kept", []string{"api", "sec"}, []ai.Focus{ai.FocusAPI, ai.FocusSecurity}, false},
+		{"duplicates dropped", []string{"perf", "performance", "Perf"}, []ai.Focus{ai.FocusPerformance}, false},
+		{"blank values skipped", []string{"", " ", "tests"}, []ai.Focus{ai.FocusTests}, false},
+		{"unknown", []string{"security", "style"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFocus(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFocus(%q) error = %v, wantErr %v", tt.values, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFocus(%q) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
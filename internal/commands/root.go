@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -19,6 +20,9 @@ var (
 			if err := setupLogger(cmd.Context()); err != nil {
 				return err
 			}
+			if err := setupLanguage(cmd.Context()); err != nil {
+				return err
+			}
 			// Best-effort version check; never fail the actual command.
 			checkForUpdateOnStartup(cmd.Context())
 			return nil
@@ -28,10 +32,29 @@ var (
 	debug   bool
 )
 
+// exitCodeError carries a specific process exit code, e.g. so CI can tell
+// a failed quality gate (2) apart from a tool error (1).
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 // Execute runs the root command for SmartGit.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		var codeErr *exitCodeError
+		if errors.As(err, &codeErr) {
+			os.Exit(codeErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -39,6 +62,8 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
+	rootCmd.PersistentFlags().StringVar(&languageFlag, "language", "", "Language for messages and AI output as a BCP-47 tag, e.g. en, de, ja, pt-BR (default: config or LANG)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "AI profile to use (overrides SMARTGIT_PROFILE and remote matching)")
 }
 
 func setupLogger(ctx context.Context) error {
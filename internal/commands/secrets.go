@@ -0,0 +1,245 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/secret"
+	"github.com/vinhtran/git-smart/pkg/logger"
+	"golang.org/x/term"
+)
+
+// geminiAPIKeySecret is the name under which the Gemini key is stored.
+const geminiAPIKeySecret = "gemini_api_key"
+
+// cachedPassphrase keeps the encrypted store passphrase for the rest of the
+// process so the user is prompted at most once per command.
+var cachedPassphrase string
+
+// passphraseReader reads passphrases from stdin when it is not a terminal.
+var passphraseReader *bufio.Reader
+
+type secretsMigrateOptions struct {
+	to      string
+	command string
+	timeout time.Duration
+}
+
+var (
+	secretsCmd = &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage where SmartGit keeps API keys",
+	}
+	secretsMigrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Move plaintext API keys from config.json into a secret store",
+		Long: `Move plaintext API keys and forge tokens from config.json into a secret store.
+
+Supported stores:
+  keyring  freedesktop Secret Service via secret-tool (GNOME Keyring, KWallet, KeePassXC)
+  file     AES-256-GCM encrypted file protected by a passphrase
+           (read from SMARTGIT_SECRET_PASSPHRASE or prompted)
+  command  read-only credential helper, e.g. --command "pass show smartgit/{name}"`,
+		Args: cobra.NoArgs,
+		RunE: runSecretsMigrate,
+	}
+	secretsMigrateOpts secretsMigrateOptions
+)
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.AddCommand(secretsMigrateCmd)
+
+	secretsMigrateCmd.Flags().StringVar(&secretsMigrateOpts.to, "to", "", "Target secret store (keyring|file|command); defaults to secret_store from config")
+	secretsMigrateCmd.Flags().StringVar(&secretsMigrateOpts.command, "command", "", "Credential helper command for the command store ({name} is replaced with the secret name)")
+	secretsMigrateCmd.Flags().DurationVar(&secretsMigrateOpts.timeout, "timeout", 30*time.Second, "Timeout for secret store operations")
+}
+
+func runSecretsMigrate(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), secretsMigrateOpts.timeout)
+	defer cancel()
+
+	log := logger.L().With("command", "secrets-migrate")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if to := strings.TrimSpace(secretsMigrateOpts.to); to != "" {
+		cfg.SecretStore = strings.ToLower(to)
+	}
+	if command := strings.TrimSpace(secretsMigrateOpts.command); command != "" {
+		cfg.SecretCommand = command
+	}
+	if cfg.SecretStore == secret.BackendPlain {
+		return errors.New("no target store selected; pass --to keyring|file|command")
+	}
+
+	store, err := openSecretStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	moved := 0
+	if key := strings.TrimSpace(cfg.GeminiAPIKey); key != "" {
+		log.InfoContext(ctx, "Moving plaintext API key into secret store", "store", store.Name(), "secret", geminiAPIKeySecret)
+		if err := moveSecret(ctx, store, geminiAPIKeySecret, key); err != nil {
+			return err
+		}
+		cfg.GeminiAPIKey = ""
+		fmt.Printf("Moved %s into the %s secret store.\n", geminiAPIKeySecret, store.Name())
+		moved++
+	}
+
+	for name, profile := range cfg.Profiles {
+		key := strings.TrimSpace(profile.APIKey)
+		if key == "" {
+			continue
+		}
+		secretName := profileSecretName(name)
+		log.InfoContext(ctx, "Moving plaintext API key into secret store", "store", store.Name(), "secret", secretName)
+		if err := moveSecret(ctx, store, secretName, key); err != nil {
+			return err
+		}
+		profile.APIKey = ""
+		cfg.Profiles[name] = profile
+		fmt.Printf("Moved %s into the %s secret store.\n", secretName, store.Name())
+		moved++
+	}
+
+	for host, settings := range cfg.Forges {
+		token := strings.TrimSpace(settings.Token)
+		if token == "" {
+			continue
+		}
+		secretName := forgeSecretName(host)
+		log.InfoContext(ctx, "Moving plaintext forge token into secret store", "store", store.Name(), "secret", secretName)
+		if err := moveSecret(ctx, store, secretName, token); err != nil {
+			return err
+		}
+		settings.Token = ""
+		cfg.Forges[host] = settings
+		fmt.Printf("Moved %s into the %s secret store.\n", secretName, store.Name())
+		moved++
+	}
+
+	if moved == 0 {
+		fmt.Println("No plaintext API keys found in config.")
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("SmartGit now reads API keys from the %s secret store.\n", store.Name())
+	return nil
+}
+
+// moveSecret writes value into store and verifies it can be read back.
+// Read-only stores are only verified, since the user must populate them.
+func moveSecret(ctx context.Context, store secret.Store, name, value string) error {
+	err := store.Set(ctx, name, value)
+	if err != nil && !errors.Is(err, secret.ErrReadOnly) {
+		return fmt.Errorf("failed to write %s to %s store: %w", name, store.Name(), err)
+	}
+
+	stored, getErr := store.Get(ctx, name)
+	if errors.Is(err, secret.ErrReadOnly) && (getErr != nil || stored != value) {
+		return fmt.Errorf("the %s store is read-only; add %s with your credential helper first, then re-run the migration", store.Name(), name)
+	}
+	if getErr != nil {
+		return fmt.Errorf("failed to verify %s in %s store: %w", name, store.Name(), getErr)
+	}
+	if stored != value {
+		return fmt.Errorf("%s read back from %s store does not match; plaintext key was kept", name, store.Name())
+	}
+	return nil
+}
+
+// openSecretStore builds the secret store configured in cfg. It returns a
+// nil store when keys are kept in plaintext config.
+func openSecretStore(cfg config.Config) (secret.Store, error) {
+	filePath, err := config.SecretsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return secret.New(secret.Options{
+		Backend:    cfg.SecretStore,
+		Command:    cfg.SecretCommand,
+		FilePath:   filePath,
+		Passphrase: secretPassphrase,
+	})
+}
+
+// secretPassphrase returns the passphrase for the encrypted file store from
+// SMARTGIT_SECRET_PASSPHRASE, prompting once when it is not set. The
+// passphrase is read without echo and asked twice when the store does not
+// exist yet, so a typo cannot lock the user out of the keys it protects.
+func secretPassphrase() (string, error) {
+	if passphrase := os.Getenv("SMARTGIT_SECRET_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	if cachedPassphrase != "" {
+		return cachedPassphrase, nil
+	}
+
+	filePath, err := config.SecretsFilePath()
+	if err != nil {
+		return "", err
+	}
+	_, statErr := os.Stat(filePath)
+	creating := errors.Is(statErr, os.ErrNotExist)
+
+	prompt := "Enter the SmartGit secrets passphrase: "
+	if creating {
+		prompt = "Choose a passphrase for the SmartGit secrets file: "
+	}
+	passphrase, err := readPassphrase(prompt)
+	if err != nil {
+		return "", err
+	}
+	if creating {
+		confirm, err := readPassphrase("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if confirm != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	cachedPassphrase = passphrase
+	return cachedPassphrase, nil
+}
+
+// readPassphrase prints prompt and reads a line from stdin without echoing
+// it when stdin is a terminal.
+func readPassphrase(prompt string) (string, error) {
+	fmt.Print(prompt)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		return string(passphrase), nil
+	}
+
+	// Piped input is read through one shared reader so the confirmation
+	// line is not lost to the first read's buffering.
+	if passphraseReader == nil {
+		passphraseReader = bufio.NewReader(os.Stdin)
+	}
+	passphrase, err := passphraseReader.ReadString('\n')
+	if err != nil && passphrase == "" {
+		return "", err
+	}
+	return strings.TrimRight(passphrase, "\r\n"), nil
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/secret"
+)
+
+func TestSecretsMigrateToFileStore(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SMARTGIT_SECRET_PASSPHRASE", "migrate-pass")
+
+	if err := config.Save(config.Config{
+		GeminiAPIKey: "AIza-legacy",
+		Profiles: map[string]config.Profile{
+			"work":     {Provider: "openai", APIKey: "sk-work"},
+			"personal": {APIKeyEnv: "PERSONAL_KEY"},
+		},
+		Forges: map[string]config.Forge{
+			"git.example.com": {Type: "gitea", Token: "gitea-token"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := secretsMigrateOpts
+	t.Cleanup(func() { secretsMigrateOpts = saved })
+	secretsMigrateOpts = secretsMigrateOptions{to: "file", timeout: saved.timeout}
+	secretsMigrateCmd.SetContext(context.Background())
+
+	if err := runSecretsMigrate(secretsMigrateCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SecretStore != secret.BackendFile {
+		t.Errorf("SecretStore = %q, want %q", cfg.SecretStore, secret.BackendFile)
+	}
+	if cfg.GeminiAPIKey != "" || cfg.Profiles["work"].APIKey != "" || cfg.Forges["git.example.com"].Token != "" {
+		t.Errorf("plaintext keys left in config: %+v", cfg)
+	}
+	if cfg.Profiles["personal"].APIKeyEnv != "PERSONAL_KEY" {
+		t.Errorf("unrelated profile settings changed: %+v", cfg.Profiles["personal"])
+	}
+
+	store, err := openSecretStore(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		geminiAPIKeySecret:                 "AIza-legacy",
+		profileSecretName("work"):          "sk-work",
+		forgeSecretName("git.example.com"): "gitea-token",
+	} {
+		got, err := store.Get(context.Background(), name)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", name, err)
+		}
+		if got != want {
+			t.Errorf("Get(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSecretsMigrateKeepsProfileNamedGemini(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SMARTGIT_SECRET_PASSPHRASE", "migrate-pass")
+
+	if err := config.Save(config.Config{
+		GeminiAPIKey: "AIza-top-level",
+		Profiles: map[string]config.Profile{
+			"gemini": {APIKey: "AIza-profile"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := secretsMigrateOpts
+	t.Cleanup(func() { secretsMigrateOpts = saved })
+	secretsMigrateOpts = secretsMigrateOptions{to: "file", timeout: saved.timeout}
+	secretsMigrateCmd.SetContext(context.Background())
+
+	if err := runSecretsMigrate(secretsMigrateCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := openSecretStore(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if got, err := store.Get(ctx, geminiAPIKeySecret); err != nil || got != "AIza-top-level" {
+		t.Errorf("top-level key = %q, %v; want %q", got, err, "AIza-top-level")
+	}
+	got, err := resolveProfileAPIKey(ctx, cfg, "gemini", cfg.Profiles["gemini"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "AIza-profile" {
+		t.Errorf("profile key = %q, want %q", got, "AIza-profile")
+	}
+}
@@ -0,0 +1,301 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+type standupOptions struct {
+	since     string
+	until     string
+	author    string
+	repos     []string
+	format    string
+	noAI      bool
+	maxTokens int
+	timeout   time.Duration
+}
+
+var (
+	standupCmd = &cobra.Command{
+		Use:   "standup",
+		Short: "Summarize recent commits for a standup",
+		Long: `Summarize recent commits for a standup.
+
+Commits on every local and remote-tracking branch are collected from the
+repositories in the "repos" list of the config file (the current
+repository when the list is empty, or the --repo flags), grouped by
+repository and branch, and summarized by AI. Only commit messages are
+sent, never diffs. With --no-ai the grouped commits are printed as they are.
+
+--author "me" matches user.email in each repository; any other value is
+passed to git log --author.`,
+		Example: `  sg standup
+  sg standup --since "last friday" --format markdown
+  sg standup --since 2024-05-01 --until 2024-05-08 --no-ai
+  sg standup --repo ~/src/api --repo ~/src/web --author all`,
+		Args: cobra.NoArgs,
+		RunE: runStandup,
+	}
+	standupOpts standupOptions
+)
+
+func init() {
+	rootCmd.AddCommand(standupCmd)
+
+	standupCmd.Flags().StringVar(&standupOpts.since, "since", "yesterday", "Start of the period, in any format git log accepts")
+	standupCmd.Flags().StringVar(&standupOpts.until, "until", "", "End of the period (default: now)")
+	standupCmd.Flags().StringVar(&standupOpts.author, "author", "me", `Whose commits to include: "me", "all" or a git log --author pattern`)
+	standupCmd.Flags().StringSliceVar(&standupOpts.repos, "repo", nil, "Repository to include (repeatable; overrides the config list)")
+	standupCmd.Flags().StringVar(&standupOpts.format, "format", "text", "Output format (text|markdown)")
+	standupCmd.Flags().BoolVar(&standupOpts.noAI, "no-ai", false, "List the commits instead of asking AI for a summary")
+	standupCmd.Flags().IntVar(&standupOpts.maxTokens, "max-tokens", 1024, "Maximum tokens for the AI summary")
+	standupCmd.Flags().DurationVar(&standupOpts.timeout, "timeout", 60*time.Second, "Timeout for collecting commits and the AI summary")
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), standupOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "standup", "path", wd)
+
+	var markdown bool
+	switch strings.ToLower(standupOpts.format) {
+	case "text", "txt":
+	case "markdown", "md":
+		markdown = true
+	default:
+		return fmt.Errorf("invalid --format %q (expected text or markdown)", standupOpts.format)
+	}
+
+	dirs, err := standupRepos(ctx, wd)
+	if err != nil {
+		return err
+	}
+
+	var repos []ai.StandupRepo
+	for _, dir := range dirs {
+		repo, err := collectStandupRepo(ctx, dir)
+		if err != nil {
+			log.WarnContext(ctx, "Skipping repository", "repo", dir, "error", err)
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", dir, err)
+			continue
+		}
+		if len(repo.Branches) > 0 {
+			repos = append(repos, repo)
+		}
+	}
+
+	period := "since " + standupOpts.since
+	if standupOpts.until != "" {
+		period += " until " + standupOpts.until
+	}
+	if len(repos) == 0 {
+		fmt.Printf("No commits %s.\n", period)
+		return nil
+	}
+
+	if !standupOpts.noAI {
+		groups, err := standupGroups(ctx, repos)
+		if err != nil {
+			log.WarnContext(ctx, "AI standup summary failed", "error", err)
+			fmt.Fprintf(os.Stderr, "AI summary failed (%v); listing commits instead.\n", err)
+		} else {
+			// Each profile only sees the commit messages of its own
+			// repositories, so work and personal setups never mix.
+			var unsummarized []ai.StandupRepo
+			printed := false
+			for _, group := range groups {
+				req := ai.StandupRequest{Period: period, Repos: group.repos, Markdown: markdown, Language: language.Tag}
+				summary, err := standupSummary(ctx, group.repos[0].Info.Path, req)
+				if err != nil {
+					log.WarnContext(ctx, "AI standup summary failed", "profile", group.profile, "error", err)
+					fmt.Fprintf(os.Stderr, "AI summary failed for %s (%v); listing their commits instead.\n", standupRepoNames(group.repos), err)
+					unsummarized = append(unsummarized, group.repos...)
+					continue
+				}
+				if printed {
+					fmt.Println()
+				}
+				fmt.Println(summary)
+				printed = true
+			}
+			if len(unsummarized) == 0 {
+				return nil
+			}
+			if printed {
+				fmt.Println()
+			}
+			repos = unsummarized
+		}
+	}
+
+	if markdown {
+		printStandupMarkdown(period, repos)
+	} else {
+		printStandupText(period, repos)
+	}
+	return nil
+}
+
+// standupRepos returns the repositories to look at: --repo, the config
+// list, or the current repository.
+func standupRepos(ctx context.Context, wd string) ([]string, error) {
+	paths := standupOpts.repos
+	if len(paths) == 0 {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, err
+		}
+		paths = cfg.Repos
+	}
+	if len(paths) == 0 {
+		root, err := git.TopLevel(ctx, wd)
+		if err != nil {
+			return nil, fmt.Errorf(`not inside a git repository and no "repos" are configured; pass --repo <path>`)
+		}
+		return []string{root}, nil
+	}
+
+	home, _ := os.UserHomeDir()
+	dirs := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if rest, ok := strings.CutPrefix(p, "~/"); ok && home != "" {
+			p = filepath.Join(home, rest)
+		}
+		dirs = append(dirs, p)
+	}
+	return dirs, nil
+}
+
+func collectStandupRepo(ctx context.Context, dir string) (ai.StandupRepo, error) {
+	repo := ai.StandupRepo{}
+	info, err := git.GetRepoInfo(ctx, dir)
+	if err != nil {
+		return repo, err
+	}
+	if root, err := git.TopLevel(ctx, dir); err == nil {
+		info.Path = root
+	}
+	repo.Info = info
+
+	author := standupOpts.author
+	switch strings.ToLower(author) {
+	case "all", "":
+		author = ""
+	case "me":
+		author = git.UserEmail(ctx, dir)
+		if author == "" {
+			return repo, fmt.Errorf(`user.email is not set; pass --author`)
+		}
+	}
+
+	commits, err := git.Activity(ctx, dir, git.ActivityOptions{Since: standupOpts.since, Until: standupOpts.until, Author: author})
+	if err != nil {
+		return repo, err
+	}
+
+	// Commits are newest first, so branches come out most recent first.
+	index := make(map[string]int)
+	for _, c := range commits {
+		i, ok := index[c.Branch]
+		if !ok {
+			i = len(repo.Branches)
+			index[c.Branch] = i
+			repo.Branches = append(repo.Branches, ai.StandupBranch{Name: c.Branch})
+		}
+		repo.Branches[i].Commits = append(repo.Branches[i].Commits, c.LogEntry)
+	}
+	return repo, nil
+}
+
+// standupGroup is a run of repositories that resolve to the same AI profile.
+type standupGroup struct {
+	profile string
+	repos   []ai.StandupRepo
+}
+
+// standupGroups selects the AI profile of every repository, as any other
+// command run inside it would, and groups the repositories by profile in
+// their original order.
+func standupGroups(ctx context.Context, repos []ai.StandupRepo) ([]standupGroup, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	var groups []standupGroup
+	index := make(map[string]int)
+	for _, repo := range repos {
+		name, _, _, err := selectProfile(ctx, cfg, repo.Info.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repo.Name(), err)
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, standupGroup{profile: name})
+		}
+		groups[i].repos = append(groups[i].repos, repo)
+	}
+	return groups, nil
+}
+
+// standupRepoNames lists the repositories for messages.
+func standupRepoNames(repos []ai.StandupRepo) string {
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
+// standupSummary asks the profile selected for dir to summarize req.
+func standupSummary(ctx context.Context, dir string, req ai.StandupRequest) (string, error) {
+	client, err := newAIClient(ctx, dir, standupOpts.maxTokens)
+	if err != nil {
+		return "", err
+	}
+	return client.Standup(ctx, req)
+}
+
+func printStandupText(period string, repos []ai.StandupRepo) {
+	fmt.Printf("Standup %s\n", period)
+	for _, repo := range repos {
+		fmt.Printf("\n%s\n", repo.Name())
+		for _, branch := range repo.Branches {
+			fmt.Printf("  %s\n", branch.Name)
+			for _, c := range branch.Commits {
+				fmt.Printf("    - %s %s (%s)\n", c.ShortHash(), c.Subject, relativeTime(c.Date))
+			}
+		}
+	}
+}
+
+func printStandupMarkdown(period string, repos []ai.StandupRepo) {
+	fmt.Printf("## Standup %s\n", period)
+	for _, repo := range repos {
+		fmt.Printf("\n### %s\n", repo.Name())
+		for _, branch := range repo.Branches {
+			fmt.Printf("\n**%s**\n\n", branch.Name)
+			for _, c := range branch.Commits {
+				fmt.Printf("- `%s` %s\n", c.ShortHash(), c.Subject)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/config"
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+func TestStandupGroups(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SMARTGIT_PROFILE", "")
+	if err := config.Save(config.Config{
+		DefaultProfile: "personal",
+		Profiles: map[string]config.Profile{
+			"work":     {Provider: "openai", Remotes: []string{"git@github.com:acme/*"}},
+			"personal": {},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	repo := func(name, remote string) ai.StandupRepo {
+		dir := filepath.Join(root, name)
+		for _, args := range [][]string{{"init", "--quiet", dir}, {"-C", dir, "remote", "add", "origin", remote}} {
+			if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+		return ai.StandupRepo{Info: git.RepoInfo{Path: dir}}
+	}
+	api := repo("api", "git@github.com:acme/api.git")
+	blog := repo("blog", "https://github.com/me/blog.git")
+	web := repo("web", "git@github.com:acme/web.git")
+
+	names := func(groups []standupGroup) map[string][]string {
+		out := make(map[string][]string)
+		for _, g := range groups {
+			for _, r := range g.repos {
+				out[g.profile] = append(out[g.profile], r.Name())
+			}
+		}
+		return out
+	}
+
+	groups, err := standupGroups(context.Background(), []ai.StandupRepo{api, blog, web})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].profile != "work" {
+		t.Fatalf("standupGroups = %+v, want work first, then personal", groups)
+	}
+	want := map[string][]string{"work": {"api", "web"}, "personal": {"blog"}}
+	if got := names(groups); !reflect.DeepEqual(got, want) {
+		t.Errorf("standupGroups = %v, want %v", got, want)
+	}
+
+	// An explicit profile applies to every repository.
+	saved := profileName
+	t.Cleanup(func() { profileName = saved })
+	profileName = "work"
+	groups, err = standupGroups(context.Background(), []ai.StandupRepo{api, blog, web})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(groups); !reflect.DeepEqual(got, map[string][]string{"work": {"api", "blog", "web"}}) {
+		t.Errorf("standupGroups with --profile = %v", got)
+	}
+}
@@ -1,27 +1,56 @@
 package commands
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/ai"
 	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
 	"github.com/vinhtran/git-smart/pkg/logger"
 )
 
+// stashMessagePrefix marks stashes created by sg in "git stash list".
+const stashMessagePrefix = "sg: "
+
 type switchOptions struct {
-	timeout time.Duration
+	create    string
+	autostash bool
+	noAI      bool
+	timeout   time.Duration
 }
 
 var (
 	switchCmd = &cobra.Command{
-		Use:     "switch <branch>",
+		Use:     "switch [branch]",
 		Aliases: []string{"sw"},
 		Short:   "Switch to a branch and pull from origin with rebase",
-		Args:    cobra.ExactArgs(1),
-		RunE:    runSwitch,
+		Long: `Switch to a branch and pull from origin with rebase.
+
+Uncommitted changes are stashed before the switch and re-applied on the
+target branch (with --autostash, or after confirming). The stash gets an
+AI-written description so it is easy to find in "git stash list".
+
+A branch that only exists on origin is fetched and checked out as a new
+local branch tracking it. Without a branch argument, pick one from a
+searchable list of local and origin branches, most recent first.
+
+With --create, describe the work instead of naming the branch; the name
+is derived the same way sg cm names branches (e.g. fix/login-timeout).`,
+		Example: `  sg sw main
+  sg sw
+  sg sw --autostash feature/login
+  sg sw -c "fix the login timeout"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runSwitch,
 	}
 	switchOpts switchOptions
 )
@@ -29,36 +58,391 @@ var (
 func init() {
 	rootCmd.AddCommand(switchCmd)
 
-	switchCmd.Flags().DurationVar(&switchOpts.timeout, "timeout", 45*time.Second, "Timeout for the branch switch and pull operation")
+	switchCmd.Flags().StringVarP(&switchOpts.create, "create", "c", "", "Create a branch for the described work and switch to it")
+	switchCmd.Flags().BoolVar(&switchOpts.autostash, "autostash", false, "Stash uncommitted changes, switch and pull, then re-apply them without asking")
+	switchCmd.Flags().BoolVar(&switchOpts.noAI, "no-ai", false, "Do not use AI for stash messages and branch names")
+	switchCmd.Flags().DurationVar(&switchOpts.timeout, "timeout", 60*time.Second, "Timeout for the branch switch and pull operation")
 }
 
 func runSwitch(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	if err := git.EnsureRepository(cmd.Context(), wd); err != nil {
+		return err
+	}
+
+	if switchOpts.create != "" {
+		if len(args) > 0 {
+			return errors.New("pass either a branch name or --create, not both")
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), switchOpts.timeout)
+		defer cancel()
+		return createSwitchBranch(ctx, wd, switchOpts.create)
+	}
+
+	// Pick before the timeout starts so browsing the list does not eat into it.
+	var targetBranch string
+	if len(args) == 1 {
+		targetBranch = args[0]
+	} else {
+		targetBranch, err = pickBranch(cmd.Context(), wd)
+		if err != nil {
+			return err
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(cmd.Context(), switchOpts.timeout)
 	defer cancel()
 
-	targetBranch := args[0]
+	log := logger.L().With("command", "switch", "path", wd, "target_branch", targetBranch)
 
-	wd, err := os.Getwd()
+	status, err := git.StatusPorcelain(ctx, wd)
 	if err != nil {
 		return err
 	}
 
-	log := logger.L().With("command", "switch", "path", wd, "target_branch", targetBranch)
+	// stash is the commit of the stash created here, "" when nothing was stashed.
+	stash := ""
+	if strings.TrimSpace(status) != "" {
+		if !switchOpts.autostash {
+			fmt.Print(i18n.T(i18n.MsgStashConfirm, targetBranch))
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if !i18n.IsYes(answer) {
+				return errors.New("uncommitted changes in the working tree; commit them or rerun with --autostash")
+			}
+		}
 
-	if err := git.EnsureRepository(ctx, wd); err != nil {
-		return err
+		message := stashMessagePrefix + switchStashMessage(ctx, wd)
+		log.InfoContext(ctx, "Stashing uncommitted changes", "message", message)
+		stash, err = git.StashPush(ctx, wd, message)
+		recordAction(ctx, wd, "sg sw", journal.Entry{Action: journal.ActionStash, Commit: stash, Target: message}, err)
+		if err != nil {
+			return err
+		}
+		if stash != "" {
+			fmt.Printf("Stashed your changes as %q.\n", message)
+		} else {
+			log.InfoContext(ctx, "Nothing was stashed")
+		}
 	}
+	stashed := stash != ""
 
-	log.InfoContext(ctx, "Checking out target branch")
-	if err := git.CheckoutBranch(ctx, wd, targetBranch); err != nil {
+	recordUndoPoint(ctx, wd, "sg sw "+targetBranch)
+	fresh := false
+	if git.BranchExists(ctx, wd, targetBranch) {
+		log.InfoContext(ctx, "Checking out target branch")
+		err = git.CheckoutBranch(ctx, wd, targetBranch)
+	} else {
+		var onRemote bool
+		onRemote, err = git.RemoteBranchExists(ctx, wd, "origin", targetBranch)
+		switch {
+		case err != nil:
+		case !onRemote:
+			err = fmt.Errorf("branch %q exists neither locally nor on origin", targetBranch)
+		default:
+			log.InfoContext(ctx, "Checking out remote branch as a new tracking branch")
+			err = git.CheckoutRemoteBranch(ctx, wd, "origin", targetBranch)
+			fresh = true
+		}
+	}
+	recordAction(ctx, wd, "sg sw", journal.Entry{Action: journal.ActionCheckout, Branch: targetBranch}, err)
+	if err != nil {
+		if stashed {
+			// Still on the original branch: put the changes back where they were.
+			if popErr := git.StashPop(ctx, wd, stash); popErr != nil {
+				log.WarnContext(ctx, "Failed to restore stashed changes", "error", popErr)
+				fmt.Printf("Your changes are still in the stash; restore them with 'git stash pop %s'.\n", stashSelector(ctx, wd, stash))
+			}
+		}
 		return err
 	}
 
-	log.InfoContext(ctx, "Pulling latest changes with rebase from origin")
-	if err := git.PullRebase(ctx, wd, "origin", targetBranch); err != nil {
-		return err
+	rebased := false
+	if !fresh {
+		log.InfoContext(ctx, "Pulling latest changes with rebase from origin")
+		recordUndoPoint(ctx, wd, "sg sw "+targetBranch)
+		err := git.PullRebase(ctx, wd, "origin", targetBranch)
+		recordAction(ctx, wd, "sg sw", journal.Entry{Action: journal.ActionRebase, Branch: targetBranch, Target: "origin/" + targetBranch}, err)
+		if err != nil {
+			if op, _ := git.InProgress(ctx, wd); op == git.OperationRebase {
+				if stashed {
+					selector := stashSelector(ctx, wd, stash)
+					fmt.Printf("Your changes stay in the stash (%s); run 'git stash pop %s' once the rebase is finished.\n", selector, selector)
+				}
+				return fmt.Errorf("pull --rebase stopped on conflicts; run 'sg resolve' to fix them or 'sg resolve --abort' to undo the rebase: %w", err)
+			}
+			if !stashed {
+				return err
+			}
+			// The branch is checked out; re-apply the changes before reporting the failed pull.
+			log.WarnContext(ctx, "Pull failed", "error", err)
+			fmt.Printf("Could not pull %s from origin: %v\n", targetBranch, err)
+		} else {
+			rebased = true
+		}
+	}
+
+	if stashed {
+		log.InfoContext(ctx, "Re-applying stashed changes")
+		err := git.StashPop(ctx, wd, stash)
+		recordAction(ctx, wd, "sg sw", journal.Entry{Action: journal.ActionStash, Branch: targetBranch, Commit: stash, Target: "pop"}, err)
+		if err != nil {
+			printStashRecovery(ctx, wd, targetBranch, stash)
+			return fmt.Errorf("re-applying stashed changes on %s conflicted: %w", targetBranch, err)
+		}
+		fmt.Println("Re-applied your stashed changes.")
+	}
+
+	switch {
+	case rebased:
+		fmt.Println(i18n.T(i18n.MsgSwitched, targetBranch, targetBranch))
+	case fresh:
+		fmt.Println(i18n.T(i18n.MsgSwitchedTracking, targetBranch, targetBranch))
+	default:
+		fmt.Println(i18n.T(i18n.MsgSwitchedNotRebased, targetBranch, targetBranch))
+	}
+	return nil
+}
+
+// switchStashMessage describes the uncommitted changes with AI, falling
+// back to a plain message when AI is disabled or fails.
+func switchStashMessage(ctx context.Context, dir string) string {
+	log := logger.L().With("command", "switch", "path", dir)
+
+	repoInfo, err := git.GetRepoInfo(ctx, dir)
+	if err != nil {
+		return "uncommitted changes"
+	}
+	fallback := "uncommitted changes on " + repoInfo.Branch
+	if switchOpts.noAI {
+		return fallback
+	}
+
+	diff, err := git.LoadDiff(ctx, dir, "HEAD")
+	if err != nil {
+		return fallback
+	}
+	diff, skipped, err := filterDiffForAI(ctx, dir, diff)
+	if err != nil {
+		return fallback
+	}
+	untracked, err := git.UntrackedFiles(ctx, dir)
+	if err != nil {
+		return fallback
+	}
+
+	client, err := newAIClient(ctx, dir, 128)
+	if err != nil {
+		log.WarnContext(ctx, "AI unavailable for the stash message", "error", err)
+		return fallback
+	}
+	message, err := client.StashMessage(ctx, ai.StashRequest{
+		Diff:         diff.String(),
+		SkippedFiles: skipped,
+		Untracked:    untracked,
+		RepoInfo:     repoInfo,
+		Language:     language.Tag,
+	})
+	if err != nil {
+		log.WarnContext(ctx, "Failed to generate stash message", "error", err)
+		return fallback
+	}
+	return message
+}
+
+// printStashRecovery explains how to finish or undo a conflicted stash pop.
+func printStashRecovery(ctx context.Context, dir, branch, stash string) {
+	fmt.Printf("\nSwitched to %s, but re-applying your stashed changes conflicted", branch)
+	if files, err := git.ConflictedFiles(ctx, dir); err == nil && len(files) > 0 {
+		fmt.Println(" in:")
+		for _, f := range files {
+			fmt.Printf("  %s\n", f)
+		}
+	} else {
+		fmt.Println(".")
+	}
+	selector := stashSelector(ctx, dir, stash)
+	fmt.Printf("Nothing is lost: the changes are still saved in %s. Either\n", selector)
+	fmt.Printf("  - fix the conflicts (for example with 'sg resolve'), then drop the stash with 'git stash drop %s', or\n", selector)
+	fmt.Println("  - undo the attempt and move the changes to a branch of their own:")
+	fmt.Printf("      git reset --merge && git stash branch %s-wip %s\n", branch, selector)
+}
+
+// stashSelector names stash for recovery hints, falling back to the latest
+// stash when it cannot be found.
+func stashSelector(ctx context.Context, dir, stash string) string {
+	if selector, err := git.StashSelector(ctx, dir, stash); err == nil {
+		return selector
+	}
+	return "stash@{0}"
+}
+
+// createSwitchBranch creates and checks out a branch named after the
+// described work, keeping any uncommitted changes.
+func createSwitchBranch(ctx context.Context, dir, description string) error {
+	log := logger.L().With("command", "switch", "path", dir)
+
+	header := strings.TrimSpace(description)
+	if !switchOpts.noAI {
+		if h, err := branchHeader(ctx, dir, description); err != nil {
+			log.WarnContext(ctx, "Falling back to a plain branch name", "error", err)
+		} else {
+			header = h
+		}
 	}
 
-	fmt.Printf("Switched to '%s' and rebased on origin/%s.\n", targetBranch, targetBranch)
+	base := deriveBranchNameFromCommit(header)
+	name := base
+	for i := 2; git.BranchExists(ctx, dir, name); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	log.InfoContext(ctx, "Creating branch", "branch", name, "header", header)
+	recordUndoPoint(ctx, dir, "sg sw -c")
+	err := git.CreateAndCheckoutBranch(ctx, dir, name)
+	recordAction(ctx, dir, "sg sw -c", journal.Entry{Action: journal.ActionBranchCreate, Branch: name}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created and switched to %s.\n", name)
 	return nil
 }
+
+func branchHeader(ctx context.Context, dir, description string) (string, error) {
+	repoInfo, err := git.GetRepoInfo(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	client, err := newAIClient(ctx, dir, 128)
+	if err != nil {
+		return "", err
+	}
+	return client.BranchHeader(ctx, ai.BranchRequest{Description: description, RepoInfo: repoInfo})
+}
+
+// pickBranch shows local branches and origin branches without a local
+// counterpart, most recent commit first, and returns the chosen name.
+func pickBranch(ctx context.Context, dir string) (string, error) {
+	all, err := git.ListBranches(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	local := make(map[string]bool)
+	for _, b := range all {
+		if !b.Remote {
+			local[b.Name] = true
+		}
+	}
+	var branches []git.Branch
+	width := 0
+	for _, b := range all {
+		if b.Remote && (!strings.HasPrefix(b.Name, "origin/") || local[strings.TrimPrefix(b.Name, "origin/")]) {
+			continue
+		}
+		branches = append(branches, b)
+		width = max(width, len(b.Name))
+	}
+	if len(branches) == 0 {
+		return "", errors.New("no branches to switch to")
+	}
+
+	items := make([]string, 0, len(branches))
+	for _, b := range branches {
+		marker := " "
+		if b.Current {
+			marker = "*"
+		}
+		items = append(items, fmt.Sprintf("%s %-*s  %-10s %s", marker, width, b.Name, branchTrack(b), relativeTime(b.Date)))
+	}
+
+	prompt := promptui.Select{
+		Label:             "Switch to branch (type to filter)",
+		Items:             items,
+		Size:              min(15, len(items)),
+		StartInSearchMode: true,
+		Searcher: func(input string, index int) bool {
+			return fuzzyMatch(input, branches[index].Name)
+		},
+		Templates: &promptui.SelectTemplates{
+			Label:    fmt.Sprintf("%s{{ . }}%s", colorCyan, colorReset),
+			Active:   fmt.Sprintf("%s▸ {{ . | cyan }}%s", colorCyan, colorReset),
+			Inactive: "  {{ . }}",
+			Selected: fmt.Sprintf("%s✓{{ . }}%s", colorGreen, colorReset),
+		},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(branches[index].Name, "origin/"), nil
+}
+
+// branchTrack summarizes a branch against its upstream, e.g. "↑2 ↓1".
+func branchTrack(b git.Branch) string {
+	switch {
+	case b.Remote:
+		return "remote"
+	case b.Gone:
+		return "gone"
+	case b.Upstream == "":
+		return "local"
+	case b.Ahead == 0 && b.Behind == 0:
+		return "="
+	}
+	var parts []string
+	if b.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", b.Ahead))
+	}
+	if b.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", b.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in s in order.
+func fuzzyMatch(pattern, s string) bool {
+	pattern = strings.ToLower(strings.ReplaceAll(pattern, " ", ""))
+	s = strings.ToLower(s)
+	for _, r := range pattern {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// relativeTime formats t like "3 days ago".
+func relativeTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return "1 " + name + " ago"
+		}
+		return fmt.Sprintf("%d %ss ago", n, name)
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return unit(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return unit(int(d.Hours()), "hour")
+	case d < 14*24*time.Hour:
+		return unit(int(d.Hours()/24), "day")
+	case d < 60*24*time.Hour:
+		return unit(int(d.Hours()/(24*7)), "week")
+	case d < 365*24*time.Hour:
+		return unit(int(d.Hours()/(24*30)), "month")
+	default:
+		return unit(int(d.Hours()/(24*365)), "year")
+	}
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+	"github.com/vinhtran/git-smart/internal/journal"
+	"github.com/vinhtran/git-smart/internal/undo"
+	"github.com/vinhtran/git-smart/pkg/logger"
+)
+
+type undoOptions struct {
+	count   int
+	timeout time.Duration
+}
+
+var (
+	undoCmd = &cobra.Command{
+		Use:   "undo",
+		Short: "Explain recent git operations and undo them safely",
+		Long: `Explain recent git operations and undo them safely.
+
+The HEAD reflog is shown as plain-language operations (commits, rebases,
+checkouts, resets, merges), newest first; operations that sg performed are
+tagged with the command. Choose how far back to go and sg restores that
+point with the least destructive commands:
+
+  - commits are undone with "git reset --soft", so their changes come
+    back staged,
+  - rebases, resets and merges with "git reset --keep" to the commit
+    before them (ORIG_HEAD for the latest rebase), which refuses to
+    overwrite uncommitted changes,
+  - branch switches by checking out the previous branch.
+
+Nothing runs until you confirm, and the undo can itself be undone.`,
+		Example: `  sg undo
+  sg undo -n 20`,
+		Args: cobra.NoArgs,
+		RunE: runUndo,
+	}
+	undoOpts undoOptions
+)
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().IntVarP(&undoOpts.count, "number", "n", 10, "Number of recent operations to show")
+	undoCmd.Flags().DurationVar(&undoOpts.timeout, "timeout", 5*time.Minute, "Timeout for the undo")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), undoOpts.timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	log := logger.L().With("command", "undo", "path", wd)
+
+	if err := git.EnsureRepository(ctx, wd); err != nil {
+		return err
+	}
+	if undoOpts.count <= 0 {
+		return fmt.Errorf("--number must be positive, got %d", undoOpts.count)
+	}
+
+	if op, err := git.InProgress(ctx, wd); err != nil {
+		return err
+	} else if op != git.OperationNone {
+		return fmt.Errorf("a %s is in progress; finish it or undo it with 'sg resolve --abort' first", op)
+	}
+
+	// Rebases take one reflog entry per commit, so read generously.
+	entries, err := git.Reflog(ctx, wd, undoOpts.count*10+1)
+	if err != nil {
+		return err
+	}
+	points, err := undo.Points(ctx, wd)
+	if err != nil {
+		log.WarnContext(ctx, "Failed to read undo points", "error", err)
+	}
+	ops := undo.Operations(entries, points)
+	if len(ops) > undoOpts.count {
+		ops = ops[:undoOpts.count]
+	}
+	if len(ops) == 0 {
+		fmt.Println("Nothing to undo: the reflog has no earlier operations.")
+		return nil
+	}
+
+	fmt.Println("Recent operations (newest first):")
+	for i, op := range ops {
+		by := ""
+		if op.By != "" {
+			by = "  [" + op.By + "]"
+		}
+		fmt.Printf("%3d) %-15s %s%s\n", i+1, relativeTime(op.Time), op.Description, by)
+	}
+
+	fmt.Print(i18n.T(i18n.MsgUndoSelect))
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		fmt.Println(i18n.T(i18n.MsgNothingChanged))
+		return nil
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(ops) {
+		return fmt.Errorf("invalid choice %q (expected 1-%d)", answer, len(ops))
+	}
+
+	branch, err := git.CurrentBranch(ctx, wd)
+	if err != nil {
+		return err
+	}
+	steps := undo.Plan(ops, n, branch)
+	if len(steps) == 0 {
+		fmt.Println("Nothing to change: those operations did not move any branch.")
+		return nil
+	}
+
+	fmt.Println("This will run:")
+	for _, s := range steps {
+		fmt.Printf("  git %s\n      %s\n", strings.Join(s.Args, " "), s.Reason)
+	}
+	fmt.Print(i18n.T(i18n.MsgProceed))
+	answer, _ = reader.ReadString('\n')
+	if !i18n.IsYes(answer) {
+		fmt.Println(i18n.T(i18n.MsgNothingChanged))
+		return nil
+	}
+
+	recordUndoPoint(ctx, wd, "sg undo")
+	for _, s := range steps {
+		log.InfoContext(ctx, "Running undo step", "args", s.Args)
+		_, err := git.Run(ctx, wd, s.Args...)
+		action := journal.ActionReset
+		if s.Args[0] == "checkout" {
+			action = journal.ActionCheckout
+		}
+		recordAction(ctx, wd, "sg undo", journal.Entry{Action: action, Branch: branch, Target: s.Args[len(s.Args)-1]}, err)
+		if err != nil {
+			if s.Args[0] == "reset" {
+				return fmt.Errorf("git %s failed; commit or stash your local changes and try again: %w", strings.Join(s.Args, " "), err)
+			}
+			return err
+		}
+	}
+	fmt.Println("Done. Run 'sg undo' again to see the new state, or to undo this undo.")
+	return nil
+}
+
+// recordUndoPoint remembers HEAD before a command changes it, so sg undo
+// can tell what sg did. Failures are logged but never stop the command.
+func recordUndoPoint(ctx context.Context, dir, command string) {
+	if err := undo.Record(ctx, dir, command); err != nil {
+		logger.L().WarnContext(ctx, "Failed to record undo point", "path", dir, "error", err)
+	}
+}
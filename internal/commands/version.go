@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vinhtran/git-smart/internal/i18n"
 	"github.com/vinhtran/git-smart/internal/version"
 	"github.com/vinhtran/git-smart/pkg/logger"
 )
@@ -57,14 +58,13 @@ func runVersion(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("A new version is available: %s (current %s)\n", latest, version.Current)
-	fmt.Print("Do you want to update now? (y/N): ")
+	fmt.Println(i18n.T(i18n.MsgUpdateAvailable, latest, version.Current))
+	fmt.Print(i18n.T(i18n.MsgUpdateConfirm))
 
 	reader := bufio.NewReader(os.Stdin)
 	answer, _ := reader.ReadString('\n')
-	answer = strings.ToLower(strings.TrimSpace(answer))
-	if answer != "y" && answer != "yes" {
-		fmt.Println("Update skipped.")
+	if !i18n.IsYes(answer) {
+		fmt.Println(i18n.T(i18n.MsgUpdateSkipped))
 		return nil
 	}
 
@@ -242,5 +242,5 @@ func checkForUpdateOnStartup(ctx context.Context) {
 		return
 	}
 
-	fmt.Fprintf(os.Stderr, "Warning: a new version of sg is available: %s (current %s). Run 'sg version' to update.\n", latest, version.Current)
+	fmt.Fprintln(os.Stderr, i18n.T(i18n.MsgUpdateWarning, latest, version.Current))
 }
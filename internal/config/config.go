@@ -6,17 +6,156 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 const (
 	appFolder = "smartgit"
 	fileName  = "config.json"
+
+	secretsFileName = "secrets.enc"
 )
 
 // Config contains persisted user preferences.
 type Config struct {
-	GeminiAPIKey string `json:"gemini_api_key"`
+	// GeminiAPIKey is the legacy plaintext key. It stays empty once the key
+	// has been moved into a secret store with `sg secrets migrate`.
+	GeminiAPIKey string `json:"gemini_api_key,omitempty"`
 	GeminiModel  string `json:"gemini_model,omitempty"`
+
+	// SecretStore selects where API keys are kept: "" (plaintext in this
+	// file), "keyring", "file" or "command".
+	SecretStore string `json:"secret_store,omitempty"`
+	// SecretCommand is the credential helper used by the "command" store,
+	// e.g. "pass show smartgit/{name}".
+	SecretCommand string `json:"secret_command,omitempty"`
+
+	// Ignore lists extra gitignore-style patterns for files whose diffs are
+	// replaced by one-line summaries in AI prompts (see .smartgitignore).
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Language is the BCP-47 tag used for CLI messages and AI output,
+	// e.g. "ja" or "de-AT". When empty, LC_ALL, LC_MESSAGES or LANG decide.
+	Language string `json:"language,omitempty"`
+
+	// Repos lists the repositories that commands working across projects,
+	// such as sg standup, look at. A leading "~/" is the home directory.
+	Repos []string `json:"repos,omitempty"`
+
+	// Forges configures pull request creation per remote host, e.g.
+	// "github.com" or "git.example.com".
+	Forges map[string]Forge `json:"forges,omitempty"`
+
+	// Profiles holds named AI setups, e.g. "work" and "personal".
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// DefaultProfile is used when no profile is selected explicitly or by remote.
+	DefaultProfile string `json:"default_profile,omitempty"`
+}
+
+// Profile describes one AI provider setup that can be selected per command,
+// per shell (SMARTGIT_PROFILE) or per repository remote.
+type Profile struct {
+	// Provider is "gemini" (default) or "openai" for OpenAI-compatible gateways.
+	Provider string `json:"provider,omitempty"`
+	BaseURL  string `json:"base_url,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// APIKey is a plaintext key; prefer APIKeyEnv or a secret store.
+	APIKey string `json:"api_key,omitempty"`
+	// APIKeyEnv names an environment variable holding the key.
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+	// Redaction is "strict" to mask secrets and emails before sending prompts.
+	Redaction string `json:"redaction,omitempty"`
+	// Remotes are glob patterns matched against the origin URL; "*" matches
+	// any run of characters, e.g. "*github.com:acme/*".
+	Remotes []string `json:"remotes,omitempty"`
+}
+
+// Forge describes the hosting service behind a remote host. Hosts such as
+// github.com, gitlab.com and codeberg.org are detected without configuration.
+type Forge struct {
+	// Type is "github", "gitlab" or "gitea".
+	Type string `json:"type,omitempty"`
+	// APIURL overrides the REST API root, e.g. "https://git.example.com/api/v1".
+	APIURL string `json:"api_url,omitempty"`
+	// Token is a plaintext token; prefer TokenEnv.
+	Token string `json:"token,omitempty"`
+	// TokenEnv names an environment variable holding the token.
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
+// ProfileSource explains why a profile was selected.
+type ProfileSource string
+
+const (
+	ProfileSourceNone    ProfileSource = ""
+	ProfileSourceFlag    ProfileSource = "flag"
+	ProfileSourceEnv     ProfileSource = "env"
+	ProfileSourceRemote  ProfileSource = "remote"
+	ProfileSourceDefault ProfileSource = "default"
+)
+
+// ErrProfileNotFound indicates a selected profile is missing from the config.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// SelectProfile picks the active profile, in priority order: explicit name
+// (--profile), SMARTGIT_PROFILE, the first profile whose remote pattern
+// matches remote, then DefaultProfile. An empty name means no profile is
+// active and the legacy top-level settings apply.
+func (c Config) SelectProfile(explicit, remote string) (string, Profile, ProfileSource, error) {
+	lookup := func(name string, source ProfileSource) (string, Profile, ProfileSource, error) {
+		profile, ok := c.Profiles[name]
+		if !ok {
+			return "", Profile{}, ProfileSourceNone, fmt.Errorf("%w: %q (selected via %s)", ErrProfileNotFound, name, source)
+		}
+		return name, profile, source, nil
+	}
+
+	if name := strings.TrimSpace(explicit); name != "" {
+		return lookup(name, ProfileSourceFlag)
+	}
+	if name := strings.TrimSpace(os.Getenv("SMARTGIT_PROFILE")); name != "" {
+		return lookup(name, ProfileSourceEnv)
+	}
+
+	if remote = strings.TrimSpace(remote); remote != "" {
+		names := make([]string, 0, len(c.Profiles))
+		for name := range c.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, pattern := range c.Profiles[name].Remotes {
+				if matchRemote(pattern, remote) {
+					return name, c.Profiles[name], ProfileSourceRemote, nil
+				}
+			}
+		}
+	}
+
+	if name := strings.TrimSpace(c.DefaultProfile); name != "" {
+		return lookup(name, ProfileSourceDefault)
+	}
+	return "", Profile{}, ProfileSourceNone, nil
+}
+
+// matchRemote reports whether remote matches pattern, where "*" matches any
+// run of characters (including "/" and ":") and the match is case-insensitive.
+func matchRemote(pattern, remote string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	re, err := regexp.Compile("(?i)^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(remote)
 }
 
 // Load returns the stored configuration, or an empty config if file not found.
@@ -60,10 +199,42 @@ func Save(cfg Config) error {
 	return os.WriteFile(path, data, 0o600)
 }
 
-func path() (string, error) {
+// Dir returns the SmartGit configuration directory.
+func Dir() (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, appFolder, fileName), nil
+	return filepath.Join(dir, appFolder), nil
+}
+
+// StateDir returns the directory for data sg accumulates over time, such
+// as the operation journal: $XDG_STATE_HOME/smartgit, or
+// ~/.local/state/smartgit when XDG_STATE_HOME is unset.
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appFolder), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", appFolder), nil
+}
+
+// SecretsFilePath returns the location of the encrypted secrets file.
+func SecretsFilePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, secretsFileName), nil
+}
+
+func path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
 }
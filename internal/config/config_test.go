@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectProfile(t *testing.T) {
+	cfg := Config{
+		DefaultProfile: "personal",
+		Profiles: map[string]Profile{
+			"personal": {},
+			"work":     {Remotes: []string{"git@github.com:acme/*", "https://github.com/acme/*"}},
+			"oss":      {Remotes: []string{"*github.com*/oss-*"}},
+			"gitlab":   {Remotes: []string{"*gitlab.example.com*"}},
+		},
+	}
+	tests := []struct {
+		name       string
+		explicit   string
+		env        string
+		remote     string
+		want       string
+		wantSource ProfileSource
+	}{
+		{"flag wins", "gitlab", "oss", "git@github.com:acme/api.git", "gitlab", ProfileSourceFlag},
+		{"env beats remote", "", "oss", "git@github.com:acme/api.git", "oss", ProfileSourceEnv},
+		{"ssh remote", "", "", "git@github.com:acme/api.git", "work", ProfileSourceRemote},
+		{"https remote", "", "", "https://github.com/acme/api.git", "work", ProfileSourceRemote},
+		{"case insensitive", "", "", "git@GitHub.com:ACME/api.git", "work", ProfileSourceRemote},
+		{"star spans slashes", "", "", "https://github.com/acme/oss-tools.git", "oss", ProfileSourceRemote},
+		{"star spans host", "", "", "ssh://git@gitlab.example.com:2222/team/app.git", "gitlab", ProfileSourceRemote},
+		{"pattern is anchored", "", "", "https://mirror.example.com/github.com/acme/api.git", "personal", ProfileSourceDefault},
+		{"no remote falls back to default", "", "", "", "personal", ProfileSourceDefault},
+		{"whitespace flag is ignored", "  ", "", "git@github.com:acme/api.git", "work", ProfileSourceRemote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SMARTGIT_PROFILE", tt.env)
+			name, _, source, err := cfg.SelectProfile(tt.explicit, tt.remote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tt.want || source != tt.wantSource {
+				t.Errorf("SelectProfile(%q, %q) = %q via %q, want %q via %q", tt.explicit, tt.remote, name, source, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestSelectProfileFirstMatchIsSorted(t *testing.T) {
+	t.Setenv("SMARTGIT_PROFILE", "")
+	cfg := Config{Profiles: map[string]Profile{
+		"b": {Remotes: []string{"*example.com*"}},
+		"a": {Remotes: []string{"*example.com*"}},
+	}}
+	for i := 0; i < 10; i++ {
+		if name, _, _, _ := cfg.SelectProfile("", "git@example.com:x/y.git"); name != "a" {
+			t.Fatalf("SelectProfile picked %q, want the alphabetically first match %q", name, "a")
+		}
+	}
+}
+
+func TestSelectProfileErrors(t *testing.T) {
+	cfg := Config{DefaultProfile: "missing", Profiles: map[string]Profile{"work": {}}}
+	tests := []struct {
+		name     string
+		explicit string
+		env      string
+	}{
+		{"unknown flag", "nope", ""},
+		{"unknown env", "", "nope"},
+		{"unknown default", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SMARTGIT_PROFILE", tt.env)
+			if _, _, _, err := cfg.SelectProfile(tt.explicit, ""); !errors.Is(err, ErrProfileNotFound) {
+				t.Errorf("err = %v, want ErrProfileNotFound", err)
+			}
+		})
+	}
+}
+
+func TestSelectProfileNone(t *testing.T) {
+	t.Setenv("SMARTGIT_PROFILE", "")
+	name, _, source, err := Config{}.SelectProfile("", "git@example.com:x/y.git")
+	if err != nil || name != "" || source != ProfileSourceNone {
+		t.Errorf("SelectProfile on empty config = %q, %q, %v", name, source, err)
+	}
+}
+
+func TestMatchRemote(t *testing.T) {
+	tests := []struct {
+		pattern, remote string
+		want            bool
+	}{
+		{"git@github.com:acme/*", "git@github.com:acme/api.git", true},
+		{"git@github.com:acme/*", "git@github.com:other/api.git", false},
+		{"*acme*", "https://github.com/acme/api", true},
+		{"github.com/acme/api", "https://github.com/acme/api", false},
+		{"https://github.com/acme/api", "https://github.com/acme/api", true},
+		{"a.b", "axb", false},
+		{"*", "anything", true},
+		{"", "anything", false},
+		{"  ", "anything", false},
+	}
+	for _, tt := range tests {
+		if got := matchRemote(tt.pattern, tt.remote); got != tt.want {
+			t.Errorf("matchRemote(%q, %q) = %v, want %v", tt.pattern, tt.remote, got, tt.want)
+		}
+	}
+}
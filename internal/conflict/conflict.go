@@ -0,0 +1,216 @@
+package conflict
+
+import (
+	"errors"
+	"strings"
+)
+
+// Marker prefixes written by git. Markers are exactly seven characters
+// followed by a space or the end of the line.
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSplit  = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// ErrMalformed is returned for unterminated or misordered conflict markers.
+var ErrMalformed = errors.New("malformed conflict markers")
+
+// Hunk is one conflicted region. Lines keep their line endings.
+type Hunk struct {
+	OursLabel   string
+	BaseLabel   string
+	TheirsLabel string
+	Ours        []string
+	// Base is nil when the file was not written with diff3 markers.
+	Base   []string
+	Theirs []string
+	// HasBase distinguishes an empty base from a missing one.
+	HasBase bool
+	// StartLine is the 1-based line of the <<<<<<< marker.
+	StartLine int
+
+	// eol is the line ending of the markers ("" means "\n"), and
+	// unterminated is set when the >>>>>>> marker ends the file without one,
+	// so Markers reproduces the file byte for byte.
+	eol          string
+	unterminated bool
+}
+
+// Segment is either plain text or a conflict hunk.
+type Segment struct {
+	Text []string
+	Hunk *Hunk
+}
+
+// File is a parsed file with conflict markers.
+type File struct {
+	Segments []Segment
+}
+
+// Hunks returns the conflict hunks in order.
+func (f File) Hunks() []*Hunk {
+	var hunks []*Hunk
+	for _, s := range f.Segments {
+		if s.Hunk != nil {
+			hunks = append(hunks, s.Hunk)
+		}
+	}
+	return hunks
+}
+
+// HasDiff3 reports whether every hunk carries a base section.
+func (f File) HasDiff3() bool {
+	for _, h := range f.Hunks() {
+		if !h.HasBase {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse splits content into text and conflict hunks.
+func Parse(content string) (File, error) {
+	var file File
+	lines := strings.SplitAfter(content, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+
+	var text []string
+	var hunk *Hunk
+	// section: 0 outside, 1 ours, 2 base, 3 theirs.
+	section := 0
+	for i, line := range lines {
+		switch {
+		case isMarker(line, markerOurs) && section == 0:
+			if len(text) > 0 {
+				file.Segments = append(file.Segments, Segment{Text: text})
+				text = nil
+			}
+			hunk = &Hunk{OursLabel: markerLabel(line), StartLine: i + 1, eol: lineEnding(line)}
+			section = 1
+		case isMarker(line, markerBase) && section == 1:
+			hunk.BaseLabel = markerLabel(line)
+			hunk.HasBase = true
+			section = 2
+		case isMarker(line, markerSplit) && (section == 1 || section == 2):
+			section = 3
+		case isMarker(line, markerTheirs) && section == 3:
+			hunk.TheirsLabel = markerLabel(line)
+			hunk.unterminated = lineEnding(line) == ""
+			file.Segments = append(file.Segments, Segment{Hunk: hunk})
+			hunk = nil
+			section = 0
+		case section == 1:
+			hunk.Ours = append(hunk.Ours, line)
+		case section == 2:
+			hunk.Base = append(hunk.Base, line)
+		case section == 3:
+			hunk.Theirs = append(hunk.Theirs, line)
+		default:
+			if isMarker(line, markerBase) || isMarker(line, markerSplit) || isMarker(line, markerTheirs) {
+				return File{}, ErrMalformed
+			}
+			text = append(text, line)
+		}
+	}
+	if section != 0 {
+		return File{}, ErrMalformed
+	}
+	if len(text) > 0 {
+		file.Segments = append(file.Segments, Segment{Text: text})
+	}
+	return file, nil
+}
+
+// Render writes the file back, replacing each hunk that has an entry in
+// resolutions with that text and keeping markers for the others.
+func (f File) Render(resolutions map[*Hunk]string) string {
+	var b strings.Builder
+	for _, s := range f.Segments {
+		if s.Hunk == nil {
+			b.WriteString(strings.Join(s.Text, ""))
+			continue
+		}
+		if res, ok := resolutions[s.Hunk]; ok {
+			b.WriteString(res)
+			continue
+		}
+		b.WriteString(s.Hunk.Markers())
+	}
+	return b.String()
+}
+
+// Markers renders the hunk with its original conflict markers.
+func (h *Hunk) Markers() string {
+	eol := h.eol
+	if eol == "" {
+		eol = "\n"
+	}
+	var b strings.Builder
+	b.WriteString(markerLine(markerOurs, h.OursLabel) + eol)
+	b.WriteString(strings.Join(h.Ours, ""))
+	if h.HasBase {
+		b.WriteString(markerLine(markerBase, h.BaseLabel) + eol)
+		b.WriteString(strings.Join(h.Base, ""))
+	}
+	b.WriteString(markerSplit + eol)
+	b.WriteString(strings.Join(h.Theirs, ""))
+	b.WriteString(markerLine(markerTheirs, h.TheirsLabel))
+	if !h.unterminated {
+		b.WriteString(eol)
+	}
+	return b.String()
+}
+
+// Context returns up to n lines of plain text before and after hunk.
+func (f File) Context(hunk *Hunk, n int) (before, after string) {
+	for i, s := range f.Segments {
+		if s.Hunk != hunk {
+			continue
+		}
+		if i > 0 && f.Segments[i-1].Hunk == nil {
+			text := f.Segments[i-1].Text
+			before = strings.Join(text[max(0, len(text)-n):], "")
+		}
+		if i+1 < len(f.Segments) && f.Segments[i+1].Hunk == nil {
+			text := f.Segments[i+1].Text
+			after = strings.Join(text[:min(n, len(text))], "")
+		}
+	}
+	return before, after
+}
+
+func isMarker(line, marker string) bool {
+	if !strings.HasPrefix(line, marker) {
+		return false
+	}
+	rest := line[len(marker):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\n' || rest[0] == '\r'
+}
+
+func markerLabel(line string) string {
+	return strings.TrimSpace(line[len(markerOurs):])
+}
+
+// markerLine returns the marker with its label, without a line ending.
+func markerLine(marker, label string) string {
+	if label == "" {
+		return marker
+	}
+	return marker + " " + label
+}
+
+// lineEnding returns the "\r\n" or "\n" ending line, or "" for the last
+// line of a file without a trailing newline.
+func lineEnding(line string) string {
+	switch {
+	case strings.HasSuffix(line, "\r\n"):
+		return "\r\n"
+	case strings.HasSuffix(line, "\n"):
+		return "\n"
+	}
+	return ""
+}
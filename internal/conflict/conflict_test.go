@@ -0,0 +1,170 @@
+package conflict
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		text    [][]string
+		hunks   []Hunk
+	}{
+		{
+			name:    "no conflicts",
+			content: "a\nb\n",
+			text:    [][]string{{"a\n", "b\n"}},
+		},
+		{
+			name:    "two-way",
+			content: "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nz\n",
+			text:    [][]string{{"a\n"}, nil, {"z\n"}},
+			hunks: []Hunk{{
+				OursLabel: "HEAD", TheirsLabel: "feature", StartLine: 2,
+				Ours: []string{"ours\n"}, Theirs: []string{"theirs\n"},
+			}},
+		},
+		{
+			name:    "diff3 with empty base",
+			content: "<<<<<<< HEAD\nours\n||||||| merged common ancestors\n=======\n>>>>>>> 1a2b3c4 (Add x)\n",
+			text:    [][]string{nil},
+			hunks: []Hunk{{
+				OursLabel: "HEAD", BaseLabel: "merged common ancestors", TheirsLabel: "1a2b3c4 (Add x)", StartLine: 1,
+				Ours: []string{"ours\n"}, HasBase: true,
+			}},
+		},
+		{
+			name:    "two hunks",
+			content: "<<<<<<<\n1\n=======\n2\n>>>>>>>\nmid\n<<<<<<< a\n|||||||\nbase\n=======\n>>>>>>> b\n",
+			text:    [][]string{nil, {"mid\n"}, nil},
+			hunks: []Hunk{
+				{StartLine: 1, Ours: []string{"1\n"}, Theirs: []string{"2\n"}},
+				{OursLabel: "a", TheirsLabel: "b", StartLine: 7, HasBase: true, Base: []string{"base\n"}},
+			},
+		},
+		{
+			name:    "crlf",
+			content: "a\r\n<<<<<<< HEAD\r\nours\r\n=======\r\ntheirs\r\n>>>>>>> feature\r\n",
+			text:    [][]string{{"a\r\n"}, nil},
+			hunks: []Hunk{{
+				OursLabel: "HEAD", TheirsLabel: "feature", StartLine: 2,
+				Ours: []string{"ours\r\n"}, Theirs: []string{"theirs\r\n"},
+				eol: "\r\n",
+			}},
+		},
+		{
+			name:    "no trailing newline",
+			content: "<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature",
+			text:    [][]string{nil},
+			hunks: []Hunk{{
+				OursLabel: "HEAD", TheirsLabel: "feature", StartLine: 1,
+				Ours: []string{"ours\n"}, Theirs: []string{"theirs\n"},
+				eol: "\n", unterminated: true,
+			}},
+		},
+		{
+			name:    "marker lookalikes are text",
+			content: "<<<<<<<< eight\n========\n>>>>>>>x\n",
+			text:    [][]string{{"<<<<<<<< eight\n", "========\n", ">>>>>>>x\n"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.content)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(f.Segments) != len(tt.text) {
+				t.Fatalf("Parse returned %d segments, want %d: %+v", len(f.Segments), len(tt.text), f.Segments)
+			}
+			hunks := 0
+			for i, s := range f.Segments {
+				if s.Hunk == nil {
+					if !reflect.DeepEqual(s.Text, tt.text[i]) {
+						t.Errorf("segment %d text = %q, want %q", i, s.Text, tt.text[i])
+					}
+					continue
+				}
+				want := tt.hunks[hunks]
+				if want.eol == "" {
+					want.eol = "\n"
+				}
+				if !reflect.DeepEqual(*s.Hunk, want) {
+					t.Errorf("segment %d hunk = %+v, want %+v", i, *s.Hunk, want)
+				}
+				hunks++
+			}
+			if hunks != len(tt.hunks) || len(f.Hunks()) != len(tt.hunks) {
+				t.Errorf("Parse found %d hunks, want %d", hunks, len(tt.hunks))
+			}
+			// Unresolved hunks keep their markers byte for byte.
+			if got := f.Render(nil); got != tt.content {
+				t.Errorf("Render(nil) = %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, content := range []string{
+		"<<<<<<< HEAD\nours\n",
+		"<<<<<<< HEAD\nours\n=======\ntheirs\n",
+		"a\n=======\nb\n",
+		"a\n>>>>>>> feature\n",
+		"a\n||||||| base\n",
+	} {
+		if _, err := Parse(content); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%q) error = %v, want ErrMalformed", content, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	content := "top\n<<<<<<< HEAD\nA\n=======\nB\n>>>>>>> x\nmid\n<<<<<<< HEAD\nC\n=======\nD\n>>>>>>> x\n"
+	f, err := Parse(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunks := f.Hunks()
+	tests := []struct {
+		name        string
+		resolutions map[*Hunk]string
+		want        string
+	}{
+		{"none", nil, content},
+		{"first", map[*Hunk]string{hunks[0]: "AB\n"}, "top\nAB\nmid\n<<<<<<< HEAD\nC\n=======\nD\n>>>>>>> x\n"},
+		{"both", map[*Hunk]string{hunks[0]: "AB\n", hunks[1]: ""}, "top\nAB\nmid\n"},
+	}
+	for _, tt := range tests {
+		if got := f.Render(tt.resolutions); got != tt.want {
+			t.Errorf("%s: Render() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if f.HasDiff3() {
+		t.Error("HasDiff3() = true for a two-way file")
+	}
+}
+
+func TestContext(t *testing.T) {
+	f, err := Parse("1\n2\n3\n<<<<<<<\nA\n=======\nB\n>>>>>>>\n4\n5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		n             int
+		before, after string
+	}{
+		{0, "", ""},
+		{2, "2\n3\n", "4\n5\n"},
+		{10, "1\n2\n3\n", "4\n5\n"},
+	}
+	for _, tt := range tests {
+		before, after := f.Context(f.Hunks()[0], tt.n)
+		if before != tt.before || after != tt.after {
+			t.Errorf("Context(%d) = %q, %q, want %q, %q", tt.n, before, after, tt.before, tt.after)
+		}
+	}
+}
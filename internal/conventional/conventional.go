@@ -0,0 +1,78 @@
+package conventional
+
+import (
+	"strings"
+)
+
+// Header is a parsed Conventional Commits header: "<type>(<scope>)!: <description>".
+type Header struct {
+	// Type is lowercase, e.g. "feat" or "fix".
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// ParseHeader splits a commit header into its parts. It reports false when
+// the header has no colon and therefore no type.
+func ParseHeader(header string) (Header, bool) {
+	header = strings.TrimSpace(header)
+	colonIdx := strings.Index(header, ":")
+	if colonIdx == -1 {
+		return Header{Description: header}, false
+	}
+
+	prefix := strings.TrimSpace(header[:colonIdx])
+	h := Header{Description: strings.TrimSpace(header[colonIdx+1:])}
+
+	// Optional breaking change indicator "!" before the colon.
+	if strings.HasSuffix(prefix, "!") {
+		h.Breaking = true
+		prefix = strings.TrimSpace(strings.TrimSuffix(prefix, "!"))
+	}
+
+	typePart := prefix
+	if parenIdx := strings.Index(prefix, "("); parenIdx != -1 {
+		typePart = prefix[:parenIdx]
+		h.Scope = strings.TrimSpace(strings.TrimSuffix(prefix[parenIdx+1:], ")"))
+	}
+	h.Type = strings.ToLower(strings.TrimSpace(typePart))
+	return h, true
+}
+
+// Valid reports whether the type is a single lowercase word, as opposed to
+// an arbitrary "text: more text" subject.
+func (h Header) Valid() bool {
+	if h.Type == "" {
+		return false
+	}
+	for _, r := range h.Type {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// BreakingNote returns the text of a "BREAKING CHANGE:" (or "BREAKING-CHANGE:")
+// footer in body, reporting whether one was found.
+func BreakingNote(body string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		for _, token := range []string{"BREAKING CHANGE:", "BREAKING-CHANGE:"} {
+			if !strings.HasPrefix(line, token) {
+				continue
+			}
+			note := []string{strings.TrimSpace(strings.TrimPrefix(line, token))}
+			// The note continues until the next blank line.
+			for _, next := range lines[i+1:] {
+				if strings.TrimSpace(next) == "" {
+					break
+				}
+				note = append(note, strings.TrimSpace(next))
+			}
+			return strings.TrimSpace(strings.Join(note, " ")), true
+		}
+	}
+	return "", false
+}
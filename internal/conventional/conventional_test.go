@@ -0,0 +1,52 @@
+package conventional
+
+import "testing"
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  Header
+		ok    bool
+		valid bool
+	}{
+		{"feat: add login", Header{Type: "feat", Description: "add login"}, true, true},
+		{"fix(api): handle nil", Header{Type: "fix", Scope: "api", Description: "handle nil"}, true, true},
+		{"feat(api)!: drop v1", Header{Type: "feat", Scope: "api", Breaking: true, Description: "drop v1"}, true, true},
+		{"refactor!: rename package", Header{Type: "refactor", Breaking: true, Description: "rename package"}, true, true},
+		{"  Feat ( ui ) :  tidy  ", Header{Type: "feat", Scope: "ui", Description: "tidy"}, true, true},
+		{"chore(deps): bump x: y", Header{Type: "chore", Scope: "deps", Description: "bump x: y"}, true, true},
+		{"Update README", Header{Description: "Update README"}, false, false},
+		{": no type", Header{Description: "no type"}, true, false},
+		{"Release notes: v1", Header{Type: "release notes", Description: "v1"}, true, false},
+		{"feat2: digits", Header{Type: "feat2", Description: "digits"}, true, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseHeader(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseHeader(%q) = %+v, %v, want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+		if got.Valid() != tt.valid {
+			t.Errorf("ParseHeader(%q).Valid() = %v, want %v", tt.in, got.Valid(), tt.valid)
+		}
+	}
+}
+
+func TestBreakingNote(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+		ok   bool
+	}{
+		{"", "", false},
+		{"Some body.\n\nRefs: #12", "", false},
+		{"Body.\n\nBREAKING CHANGE: config moved", "config moved", true},
+		{"BREAKING-CHANGE: a\n  wrapped line\n\nlater paragraph", "a wrapped line", true},
+		{"Mentions BREAKING CHANGE: inline only", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := BreakingNote(tt.body)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("BreakingNote(%q) = %q, %v, want %q, %v", tt.body, got, ok, tt.want, tt.ok)
+		}
+	}
+}
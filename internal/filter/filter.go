@@ -0,0 +1,256 @@
+package filter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// IgnoreFileName is the per-repository file listing paths to keep out of AI prompts.
+const IgnoreFileName = ".smartgitignore"
+
+// DefaultPatterns exclude files that rarely benefit from AI review but
+// dominate diffs: lockfiles, generated code, vendored code and minified assets.
+var DefaultPatterns = []string{
+	"go.sum",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"poetry.lock",
+	"Pipfile.lock",
+	"Gemfile.lock",
+	"composer.lock",
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+	"*.min.js",
+	"*.min.css",
+	"*.map",
+	"vendor/",
+	"node_modules/",
+	"third_party/",
+}
+
+// rule is a single gitignore-style pattern.
+type rule struct {
+	pattern string
+	source  string
+	negate  bool
+	re      *regexp.Regexp
+}
+
+// Matcher decides which paths are excluded from AI context. Patterns use
+// gitignore syntax: later rules win, "!" re-includes a path, a leading "/"
+// anchors to the repository root, a trailing "/" matches a directory and
+// "**" matches any number of directories.
+type Matcher struct {
+	rules []rule
+}
+
+// Skipped is a file left out of the prompt, reported as a one-line summary.
+type Skipped struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+	Reason  string
+}
+
+// Summary renders the skipped file, e.g. "go.sum: 120 lines changed (ignored by go.sum)".
+func (s Skipped) Summary() string {
+	if s.Binary {
+		return fmt.Sprintf("%s: binary file changed (%s)", s.Path, s.Reason)
+	}
+	return fmt.Sprintf("%s: %d lines changed (+%d -%d, %s)", s.Path, s.Added+s.Deleted, s.Added, s.Deleted, s.Reason)
+}
+
+// Load builds a matcher from the defaults, the repository's .smartgitignore
+// and extra patterns (for example from config), in that order.
+func Load(repoRoot string, extra []string) (*Matcher, error) {
+	m := &Matcher{}
+	m.add(DefaultPatterns, "default")
+
+	patterns, err := readIgnoreFile(filepath.Join(repoRoot, IgnoreFileName))
+	if err != nil {
+		return nil, err
+	}
+	m.add(patterns, IgnoreFileName)
+	m.add(extra, "config")
+	return m, nil
+}
+
+func readIgnoreFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+func (m *Matcher) add(patterns []string, source string) {
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		r := rule{pattern: p, source: source}
+		if strings.HasPrefix(p, "!") {
+			r.negate = true
+			p = p[1:]
+		}
+		re, err := compilePattern(p)
+		if err != nil {
+			continue
+		}
+		r.re = re
+		m.rules = append(m.rules, r)
+	}
+}
+
+// compilePattern converts a gitignore-style glob into an anchored regexp.
+func compilePattern(p string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if dirOnly {
+		// A directory pattern matches everything below it.
+		b.WriteString("/.*$")
+	} else {
+		// A file pattern also matches a directory of that name and its contents.
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Match reports whether path is excluded, with the pattern responsible.
+func (m *Matcher) Match(path string) (bool, string) {
+	path = filepath.ToSlash(strings.TrimPrefix(path, "./"))
+	excluded, reason := false, ""
+	for _, r := range m.rules {
+		if !r.re.MatchString(path) {
+			continue
+		}
+		excluded = !r.negate
+		if excluded {
+			reason = fmt.Sprintf("ignored by %s pattern %s", r.source, r.pattern)
+		} else {
+			reason = ""
+		}
+	}
+	return excluded, reason
+}
+
+// Apply splits diff into files that go into the prompt and files replaced by
+// summaries. Besides the matcher, files marked linguist-generated,
+// linguist-vendored or -diff in .gitattributes are summarized, as are binary
+// files.
+func Apply(ctx context.Context, repoRoot string, diff *git.Diff, m *Matcher) (*git.Diff, []Skipped) {
+	paths := make([]string, 0, len(diff.Files))
+	for _, f := range diff.Files {
+		paths = append(paths, f.Path())
+	}
+	// Attributes are best effort: a failure only means fewer files are summarized.
+	attrs, err := git.CheckAttributes(ctx, repoRoot, []string{"linguist-generated", "linguist-vendored", "diff"}, paths)
+	if err != nil {
+		attrs = nil
+	}
+
+	var skipped []Skipped
+	kept := diff.Filter(func(f git.FileDiff) bool {
+		reason := skipReason(f, attrs[f.Path()], m)
+		if reason == "" {
+			return true
+		}
+		added, deleted := f.Stats()
+		skipped = append(skipped, Skipped{
+			Path:    f.Path(),
+			Added:   added,
+			Deleted: deleted,
+			Binary:  f.IsBinary,
+			Reason:  reason,
+		})
+		return false
+	})
+	return kept, skipped
+}
+
+func skipReason(f git.FileDiff, attrs map[string]string, m *Matcher) string {
+	if m != nil {
+		if excluded, reason := m.Match(f.Path()); excluded {
+			return reason
+		}
+	}
+	for _, attr := range []string{"linguist-generated", "linguist-vendored"} {
+		switch attrs[attr] {
+		case "set", "true":
+			return attr
+		}
+	}
+	if attrs["diff"] == "unset" {
+		return "-diff attribute"
+	}
+	if f.IsBinary {
+		return "binary"
+	}
+	return ""
+}
+
+// Summaries renders skipped files as prompt lines.
+func Summaries(skipped []Skipped) []string {
+	lines := make([]string, 0, len(skipped))
+	for _, s := range skipped {
+		lines = append(lines, s.Summary())
+	}
+	return lines
+}
@@ -0,0 +1,202 @@
+package filter
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{"basename anywhere", []string{"go.sum"}, "tools/go.sum", true},
+		{"basename at root", []string{"go.sum"}, "go.sum", true},
+		{"basename is exact", []string{"go.sum"}, "go.sums", false},
+		{"star stays in a segment", []string{"*.pb.go"}, "api/v1/user.pb.go", true},
+		{"star does not cross slashes", []string{"api/*.go"}, "api/v1/user.go", false},
+		{"question mark", []string{"file?.txt"}, "docs/file1.txt", true},
+		{"question mark needs a character", []string{"file?.txt"}, "file.txt", false},
+		{"regexp characters are literal", []string{"a+b.txt"}, "a+b.txt", true},
+		{"dot is literal", []string{"a.txt"}, "abtxt", false},
+		{"leading slash anchors", []string{"/build"}, "build/out.js", true},
+		{"anchored pattern misses nested path", []string{"/build"}, "web/build/out.js", false},
+		{"inner slash anchors", []string{"docs/gen"}, "sub/docs/gen/a.md", false},
+		{"dir-only matches contents", []string{"vendor/"}, "vendor/github.com/x/y.go", true},
+		{"dir-only matches nested dirs", []string{"vendor/"}, "app/vendor/lib.js", true},
+		{"dir-only does not match a file", []string{"vendor/"}, "vendor", false},
+		{"file pattern matches a directory", []string{"secrets"}, "config/secrets/prod.env", true},
+		{"leading double star", []string{"**/fixtures/*.json"}, "a/b/fixtures/x.json", true},
+		{"leading double star at root", []string{"**/fixtures/*.json"}, "fixtures/x.json", true},
+		{"middle double star", []string{"a/**/b.txt"}, "a/x/y/b.txt", true},
+		{"middle double star matches zero dirs", []string{"a/**/b.txt"}, "a/b.txt", true},
+		{"trailing double star", []string{"gen/**"}, "gen/x/y.go", true},
+		{"negation re-includes", []string{"*.lock", "!Cargo.lock"}, "Cargo.lock", false},
+		{"negation only affects its match", []string{"*.lock", "!Cargo.lock"}, "yarn.lock", true},
+		{"later rule wins", []string{"!keep.txt", "keep.txt"}, "keep.txt", true},
+		{"dot slash prefix", []string{"/go.sum"}, "./go.sum", true},
+		{"blank pattern is ignored", []string{"  "}, "main.go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Matcher{}
+			m.add(tt.patterns, "test")
+			got, reason := m.Match(tt.path)
+			if got != tt.want {
+				t.Errorf("Match(%q) with %q = %v, want %v", tt.path, tt.patterns, got, tt.want)
+			}
+			if got && !strings.Contains(reason, "test pattern") {
+				t.Errorf("reason = %q, want it to name the pattern", reason)
+			}
+			if !got && reason != "" {
+				t.Errorf("reason = %q for a kept path", reason)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	ignore := "# local rules\n\n!go.sum\nfixtures/\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte(ignore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(root, []string{"*.snap", "!fixtures/keep.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path   string
+		want   bool
+		reason string
+	}{
+		{"go.sum", false, ""},
+		{"web/package-lock.json", true, "ignored by default pattern package-lock.json"},
+		{"testdata/fixtures/big.json", true, "ignored by .smartgitignore pattern fixtures/"},
+		{"fixtures/keep.json", false, ""},
+		{"ui/__snapshots__/a.snap", true, "ignored by config pattern *.snap"},
+		{"main.go", false, ""},
+	}
+	for _, tt := range tests {
+		got, reason := m.Match(tt.path)
+		if got != tt.want || reason != tt.reason {
+			t.Errorf("Match(%q) = %v, %q; want %v, %q", tt.path, got, reason, tt.want, tt.reason)
+		}
+	}
+
+	if _, err := Load(t.TempDir(), nil); err != nil {
+		t.Errorf("Load without %s: %v", IgnoreFileName, err)
+	}
+}
+
+const applyDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
++// visible change
+ func main() {}
+diff --git a/go.sum b/go.sum
+index 1111111..2222222 100644
+--- a/go.sum
++++ b/go.sum
+@@ -1,2 +1,2 @@
+-example.com/a v1.0.0 h1:old=
++example.com/a v1.1.0 h1:new=
+ example.com/b v1.0.0 h1:keep=
+diff --git a/api/user_gen.go b/api/user_gen.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/api/user_gen.go
+@@ -0,0 +1 @@
++package api // generated secret
+diff --git a/third/lib.js b/third/lib.js
+new file mode 100644
+index 0000000..4444444
+--- /dev/null
++++ b/third/lib.js
+@@ -0,0 +1 @@
++vendoredCode()
+diff --git a/data/dump.sql b/data/dump.sql
+new file mode 100644
+index 0000000..5555555
+--- /dev/null
++++ b/data/dump.sql
+@@ -0,0 +1 @@
++INSERT INTO users VALUES ('hidden');
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..6666666
+Binary files /dev/null and b/logo.png differ
+`
+
+func TestApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	if out, err := exec.Command("git", "init", "--quiet", root).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	attrs := "*_gen.go linguist-generated\nthird/** linguist-vendored=true\n*.sql -diff\n"
+	if err := os.WriteFile(filepath.Join(root, ".gitattributes"), []byte(attrs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := git.ParseDiff(applyDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, skipped := Apply(context.Background(), root, diff, m)
+
+	if len(kept.Files) != 1 || kept.Files[0].Path() != "main.go" {
+		t.Fatalf("kept %d files, want only main.go:\n%s", len(kept.Files), kept.String())
+	}
+	prompt := kept.String()
+	if !strings.Contains(prompt, "+// visible change") {
+		t.Errorf("kept diff lost main.go:\n%s", prompt)
+	}
+	for _, hidden := range []string{"h1:new=", "generated secret", "vendoredCode", "hidden", "logo.png"} {
+		if strings.Contains(prompt, hidden) {
+			t.Errorf("kept diff contains %q from a skipped file:\n%s", hidden, prompt)
+		}
+	}
+
+	want := []string{
+		"go.sum: 2 lines changed (+1 -1, ignored by default pattern go.sum)",
+		"api/user_gen.go: 1 lines changed (+1 -0, linguist-generated)",
+		"third/lib.js: 1 lines changed (+1 -0, linguist-vendored)",
+		"data/dump.sql: 1 lines changed (+1 -0, -diff attribute)",
+		"logo.png: binary file changed (binary)",
+	}
+	if got := Summaries(skipped); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("summaries =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestApplyWithoutMatcherOrRepository(t *testing.T) {
+	diff, err := git.ParseDiff(applyDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Outside a repository attributes are unavailable, but binary files are
+	// still summarized.
+	kept, skipped := Apply(context.Background(), t.TempDir(), diff, nil)
+	if len(kept.Files) != 5 || len(skipped) != 1 || skipped[0].Path != "logo.png" {
+		t.Errorf("kept %d files and skipped %+v, want all text files kept", len(kept.Files), skipped)
+	}
+}
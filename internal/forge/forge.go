@@ -0,0 +1,304 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind names a supported forge API.
+type Kind string
+
+const (
+	KindGitHub Kind = "github"
+	KindGitLab Kind = "gitlab"
+	KindGitea  Kind = "gitea"
+)
+
+var (
+	// ErrUnknownForge is returned when the forge cannot be detected from a remote.
+	ErrUnknownForge = errors.New("unknown forge")
+	// ErrUnauthorized is matched by API errors caused by a missing, invalid or
+	// underprivileged token.
+	ErrUnauthorized = errors.New("forge rejected the token")
+	// ErrPullRequestExists is matched by API errors reporting that an open
+	// pull request for the same branches already exists.
+	ErrPullRequestExists = errors.New("pull request already exists")
+)
+
+// APIError is a non-2xx response from a forge API. Use errors.Is with
+// ErrUnauthorized or ErrPullRequestExists to tell the common cases apart.
+type APIError struct {
+	Forge   string
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s API error: status=%d", e.Forge, e.Status)
+	}
+	return fmt.Sprintf("%s API error: status=%d: %s", e.Forge, e.Status, e.Message)
+}
+
+// Unwrap maps the status to a sentinel error. GitHub reports an existing
+// pull request as a 422 validation error, GitLab and Gitea as a 409.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.Status == http.StatusConflict,
+		e.Status == http.StatusUnprocessableEntity && strings.Contains(strings.ToLower(e.Message), "already exist"):
+		return ErrPullRequestExists
+	}
+	return nil
+}
+
+// PullRequest is what is needed to open a pull or merge request.
+type PullRequest struct {
+	// Head is the source branch, Base the target branch.
+	Head  string
+	Base  string
+	Title string
+	Body  string
+	Draft bool
+}
+
+// Created describes an opened pull or merge request.
+type Created struct {
+	Number int
+	URL    string
+}
+
+// Forge opens pull requests on a hosting service.
+type Forge interface {
+	Name() string
+	CreatePullRequest(ctx context.Context, pr PullRequest) (Created, error)
+}
+
+// Remote is a parsed git remote URL.
+type Remote struct {
+	Host string
+	// Path is the repository path without ".git", e.g. "acme/api" or
+	// "group/subgroup/api" on GitLab.
+	Path string
+}
+
+// Owner returns the namespace part of the path, e.g. "acme".
+func (r Remote) Owner() string {
+	i := strings.LastIndex(r.Path, "/")
+	if i < 0 {
+		return ""
+	}
+	return r.Path[:i]
+}
+
+// Repo returns the last path element, e.g. "api".
+func (r Remote) Repo() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
+
+// ParseRemote understands https, ssh and scp-like (git@host:owner/repo.git)
+// remote URLs.
+func ParseRemote(remote string) (Remote, error) {
+	remote = strings.TrimSpace(remote)
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", remote, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, ":"); at > 0 {
+		host, path = remote[:at], remote[at+1:]
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("cannot parse owner and repository from remote %q", remote)
+	}
+	return Remote{Host: strings.ToLower(host), Path: path}, nil
+}
+
+// DetectKind guesses the forge from a remote host name.
+func DetectKind(host string) (Kind, bool) {
+	switch {
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return KindGitHub, true
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return KindGitLab, true
+	case host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return KindGitea, true
+	}
+	return "", false
+}
+
+// ParseKind validates a forge name from config.
+func ParseKind(s string) (Kind, bool) {
+	switch k := Kind(strings.ToLower(strings.TrimSpace(s))); k {
+	case KindGitHub, KindGitLab, KindGitea:
+		return k, true
+	}
+	return "", false
+}
+
+// DefaultAPIURL returns the REST API root for kind on host.
+func DefaultAPIURL(kind Kind, host string) string {
+	switch kind {
+	case KindGitHub:
+		if host == "github.com" {
+			return "https://api.github.com"
+		}
+		return "https://" + host + "/api/v3"
+	case KindGitLab:
+		return "https://" + host + "/api/v4"
+	default:
+		return "https://" + host + "/api/v1"
+	}
+}
+
+// TokenEnvVars lists the environment variables conventionally holding a
+// token for kind on host, in order of preference. GitHub's variables belong
+// to github.com; Enterprise hosts use the GH_ENTERPRISE_TOKEN family, as the
+// gh CLI does, so a github.com token is never sent to another server.
+func TokenEnvVars(kind Kind, host string) []string {
+	switch kind {
+	case KindGitHub:
+		if host == "github.com" {
+			return []string{"GITHUB_TOKEN", "GH_TOKEN"}
+		}
+		return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	case KindGitLab:
+		return []string{"GITLAB_TOKEN"}
+	default:
+		return []string{"GITEA_TOKEN"}
+	}
+}
+
+// Options configures New.
+type Options struct {
+	Kind Kind
+	// APIURL overrides the API root, e.g. for self-hosted instances or a
+	// local stand-in server.
+	APIURL string
+	Token  string
+	Remote Remote
+}
+
+// New returns the forge client for opts.Kind.
+func New(opts Options) (Forge, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("no %s token configured", opts.Kind)
+	}
+	apiURL := strings.TrimRight(opts.APIURL, "/")
+	if apiURL == "" {
+		apiURL = DefaultAPIURL(opts.Kind, opts.Remote.Host)
+	}
+	base := client{
+		apiURL:     apiURL,
+		token:      opts.Token,
+		remote:     opts.Remote,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	switch opts.Kind {
+	case KindGitHub:
+		return &github{base}, nil
+	case KindGitLab:
+		return &gitlab{base}, nil
+	case KindGitea:
+		return &gitea{base}, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownForge, opts.Kind)
+}
+
+// client holds what every REST implementation shares.
+type client struct {
+	apiURL     string
+	token      string
+	remote     Remote
+	httpClient *http.Client
+}
+
+// postJSON sends payload to path and decodes a 2xx response into out.
+func (c client) postJSON(ctx context.Context, name, path string, headers map[string]string, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		raw, _ := io.ReadAll(io.LimitReader(httpResp.Body, 64<<10))
+		return &APIError{Forge: name, Status: httpResp.StatusCode, Message: apiMessage(raw)}
+	}
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
+
+// apiMessage extracts the human-readable messages from an error body. The
+// forges disagree on the shape: GitHub sends "message" plus an "errors" list
+// of objects, GitLab sends "message" as a string, a list or a map of fields,
+// and Gitea a plain "message". Bodies that are not JSON are returned trimmed.
+func apiMessage(raw []byte) string {
+	var body map[string]any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return strings.TrimSpace(string(raw))
+	}
+	var messages []string
+	var collect func(prefix string, v any)
+	collect = func(prefix string, v any) {
+		switch v := v.(type) {
+		case string:
+			if v = strings.TrimSpace(v); v != "" {
+				messages = append(messages, prefix+v)
+			}
+		case []any:
+			for _, item := range v {
+				collect(prefix, item)
+			}
+		case map[string]any:
+			// GitHub error objects carry a "message"; GitLab validation
+			// errors map field names to lists of messages.
+			if msg, ok := v["message"]; ok {
+				collect(prefix, msg)
+				return
+			}
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				collect(prefix+k+" ", v[k])
+			}
+		}
+	}
+	for _, key := range []string{"message", "error", "errors"} {
+		collect("", body[key])
+	}
+	return strings.Join(messages, "; ")
+}
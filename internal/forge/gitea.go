@@ -0,0 +1,40 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+)
+
+type gitea struct {
+	client
+}
+
+func (g *gitea) Name() string {
+	return "Gitea"
+}
+
+// CreatePullRequest calls POST /repos/{owner}/{repo}/pulls. Gitea has no
+// draft flag; a "WIP:" title prefix marks work in progress instead.
+func (g *gitea) CreatePullRequest(ctx context.Context, pr PullRequest) (Created, error) {
+	title := pr.Title
+	if pr.Draft {
+		title = "WIP: " + title
+	}
+	payload := map[string]any{
+		"title": title,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"body":  pr.Body,
+	}
+	headers := map[string]string{"Authorization": "token " + g.token}
+
+	var resp struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/pulls", g.remote.Owner(), g.remote.Repo())
+	if err := g.postJSON(ctx, "gitea", path, headers, payload, &resp); err != nil {
+		return Created{}, err
+	}
+	return Created{Number: resp.Number, URL: resp.HTMLURL}, nil
+}
@@ -0,0 +1,40 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+)
+
+type github struct {
+	client
+}
+
+func (g *github) Name() string {
+	return "GitHub"
+}
+
+// CreatePullRequest calls POST /repos/{owner}/{repo}/pulls.
+func (g *github) CreatePullRequest(ctx context.Context, pr PullRequest) (Created, error) {
+	payload := map[string]any{
+		"title": pr.Title,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"body":  pr.Body,
+		"draft": pr.Draft,
+	}
+	headers := map[string]string{
+		"Authorization":        "Bearer " + g.token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+
+	var resp struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/pulls", g.remote.Owner(), g.remote.Repo())
+	if err := g.postJSON(ctx, "github", path, headers, payload, &resp); err != nil {
+		return Created{}, err
+	}
+	return Created{Number: resp.Number, URL: resp.HTMLURL}, nil
+}
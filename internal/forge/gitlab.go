@@ -0,0 +1,41 @@
+package forge
+
+import (
+	"context"
+	"net/url"
+)
+
+type gitlab struct {
+	client
+}
+
+func (g *gitlab) Name() string {
+	return "GitLab"
+}
+
+// CreatePullRequest calls POST /projects/{id}/merge_requests, using the
+// URL-encoded project path as id.
+func (g *gitlab) CreatePullRequest(ctx context.Context, pr PullRequest) (Created, error) {
+	title := pr.Title
+	if pr.Draft {
+		title = "Draft: " + title
+	}
+	payload := map[string]any{
+		"source_branch":        pr.Head,
+		"target_branch":        pr.Base,
+		"title":                title,
+		"description":          pr.Body,
+		"remove_source_branch": true,
+	}
+	headers := map[string]string{"PRIVATE-TOKEN": g.token}
+
+	var resp struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	path := "/projects/" + url.PathEscape(g.remote.Path) + "/merge_requests"
+	if err := g.postJSON(ctx, "gitlab", path, headers, payload, &resp); err != nil {
+		return Created{}, err
+	}
+	return Created{Number: resp.IID, URL: resp.WebURL}, nil
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Verdict is the answer for the commit under test in a bisect.
+type Verdict string
+
+const (
+	VerdictGood Verdict = "good"
+	VerdictBad  Verdict = "bad"
+	VerdictSkip Verdict = "skip"
+)
+
+// BisectState is where a bisect stands after starting it or marking a commit.
+type BisectState struct {
+	// Current is the commit checked out for testing; empty once Done.
+	Current string
+	// Remaining and Steps are git's estimate of what is left after Current.
+	Remaining int
+	Steps     int
+	// Done is set when git found the first bad commit (Culprit) or ran out
+	// of testable commits, leaving only skipped Candidates.
+	Done       bool
+	Culprit    string
+	Candidates []string
+}
+
+var (
+	bisectProgress = regexp.MustCompile(`Bisecting: (\d+) revisions? left to test after this \(roughly (\d+) steps?\)`)
+	bisectCurrent  = regexp.MustCompile(`(?m)^\[([0-9a-f]{7,64})\]`)
+	bisectCulprit  = regexp.MustCompile(`(?m)^([0-9a-f]{7,64}) is the first bad commit`)
+	bisectHash     = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+)
+
+// BisectInProgress reports whether a bisect was started and not reset.
+func BisectInProgress(ctx context.Context, dir string) (bool, error) {
+	path, err := GitPath(ctx, dir, "BISECT_START")
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	return err == nil, nil
+}
+
+// BisectStart starts a bisect between a known bad and a known good revision
+// and checks out the first commit to test.
+func BisectStart(ctx context.Context, dir, bad, good string) (BisectState, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return BisectState{}, err
+	}
+	return bisectStep(ctx, dir, "start", bad, good)
+}
+
+// BisectMark records the verdict for the commit under test and checks out
+// the next one, if any.
+func BisectMark(ctx context.Context, dir string, verdict Verdict) (BisectState, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return BisectState{}, err
+	}
+	return bisectStep(ctx, dir, string(verdict))
+}
+
+// BisectReset ends the bisect and returns to the branch it started from.
+func BisectReset(ctx context.Context, dir string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "bisect", "reset")
+	return err
+}
+
+// BisectSuspects returns the commits that may still be the first bad one:
+// reachable from the bad commit but from none of the good ones, newest first.
+func BisectSuspects(ctx context.Context, dir string) ([]LogEntry, error) {
+	return CommitLog(ctx, dir, "refs/bisect/bad", "--not", "--glob=refs/bisect/good-*")
+}
+
+func bisectStep(ctx context.Context, dir string, args ...string) (BisectState, error) {
+	// parseBisect reads git's messages, which are translated.
+	out, err := runEnv(ctx, dir, []string{"LC_ALL=C"}, append([]string{"bisect"}, args...)...)
+	state, ok := parseBisect(out)
+	if ok {
+		// git exits non-zero when only skipped commits are left, but the
+		// outcome is still a result.
+		return state, nil
+	}
+	return state, err
+}
+
+// parseBisect reads the output of "git bisect start|good|bad|skip".
+func parseBisect(out string) (BisectState, bool) {
+	var state BisectState
+	if m := bisectCulprit.FindStringSubmatch(out); m != nil {
+		state.Done = true
+		state.Culprit = m[1]
+		return state, true
+	}
+	if strings.Contains(out, "first bad commit could be any of") {
+		state.Done = true
+		for _, line := range strings.Split(out, "\n") {
+			if line = strings.TrimSpace(line); bisectHash.MatchString(line) {
+				state.Candidates = append(state.Candidates, line)
+			}
+		}
+		return state, true
+	}
+	m := bisectCurrent.FindStringSubmatch(out)
+	if m == nil {
+		return state, false
+	}
+	state.Current = m[1]
+	if p := bisectProgress.FindStringSubmatch(out); p != nil {
+		state.Remaining, _ = strconv.Atoi(p[1])
+		state.Steps, _ = strconv.Atoi(p[2])
+	}
+	return state, true
+}
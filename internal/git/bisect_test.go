@@ -0,0 +1,130 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseBisect(t *testing.T) {
+	const (
+		h1 = "1111111111111111111111111111111111111111"
+		h2 = "2222222222222222222222222222222222222222"
+	)
+	tests := []struct {
+		name string
+		out  string
+		want BisectState
+		ok   bool
+	}{
+		{
+			name: "progress",
+			out:  "Bisecting: 6 revisions left to test after this (roughly 3 steps)\n[" + h1 + "] feat: add cache\n",
+			want: BisectState{Current: h1, Remaining: 6, Steps: 3},
+			ok:   true,
+		},
+		{
+			name: "singular",
+			out:  "Bisecting: 1 revision left to test after this (roughly 1 step)\n[" + h1 + "] fix: typo\n",
+			want: BisectState{Current: h1, Remaining: 1, Steps: 1},
+			ok:   true,
+		},
+		{
+			name: "status line before progress",
+			out:  "status: waiting for both good and bad commits\nstatus: waiting for good commit(s), bad commit known\nBisecting: 0 revisions left to test after this (roughly 0 steps)\n[" + h2 + "] refactor\n",
+			want: BisectState{Current: h2},
+			ok:   true,
+		},
+		{
+			name: "culprit",
+			out:  h1 + " is the first bad commit\ncommit " + h1 + "\nAuthor: A <a@example.com>\n\n    feat: add cache\n",
+			want: BisectState{Done: true, Culprit: h1},
+			ok:   true,
+		},
+		{
+			name: "only skipped left",
+			out:  "There are only 'skip'ped commits left to test.\nThe first bad commit could be any of:\n" + h1 + "\n" + h2 + "\nWe cannot bisect more!\n",
+			want: BisectState{Done: true, Candidates: []string{h1, h2}},
+			ok:   true,
+		},
+		{
+			name: "waiting",
+			out:  "status: waiting for good commit(s), bad commit known\n",
+			ok:   false,
+		},
+		{
+			name: "error",
+			out:  "fatal: invalid reference: nope\n",
+			ok:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseBisect(tt.out)
+			if ok != tt.ok {
+				t.Fatalf("parseBisect ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBisect = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBisect(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	// parseBisect must not depend on the user's locale.
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "repo")
+	gitCmd(t, filepath.Dir(dir), "init", "--quiet", dir)
+	hashes := make([]string, 8)
+	for i := range hashes {
+		gitCmd(t, dir, "commit", "--quiet", "--allow-empty", "-m", "commit "+strconv.Itoa(i))
+		h, err := RevParse(ctx, dir, "HEAD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes[i] = h
+	}
+	// Commit 5 introduced the bug.
+	isBad := func(h string) bool {
+		for i, c := range hashes {
+			if c == h {
+				return i >= 5
+			}
+		}
+		t.Fatalf("bisect checked out unknown commit %s", h)
+		return false
+	}
+
+	state, err := BisectStart(ctx, dir, hashes[7], hashes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = BisectReset(ctx, dir) })
+	if inProgress, err := BisectInProgress(ctx, dir); err != nil || !inProgress {
+		t.Fatalf("BisectInProgress = %v, %v", inProgress, err)
+	}
+	for i := 0; !state.Done; i++ {
+		if i > 8 || state.Current == "" {
+			t.Fatalf("bisect did not converge: %+v", state)
+		}
+		verdict := VerdictGood
+		if isBad(state.Current) {
+			verdict = VerdictBad
+		}
+		if state, err = BisectMark(ctx, dir, verdict); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if state.Culprit != hashes[5] {
+		t.Errorf("Culprit = %s, want %s", state.Culprit, hashes[5])
+	}
+}
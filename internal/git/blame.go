@@ -0,0 +1,110 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameLine describes the commit that last changed a line.
+type BlameLine struct {
+	Commit LogEntry
+	// Path and Line locate the line in Commit, which may differ from the
+	// current ones after renames and edits above it.
+	Path string
+	Line int
+	Text string
+	// Uncommitted is set for lines that only exist in the working tree.
+	Uncommitted bool
+}
+
+// Blame returns the commit that last changed line n (1-based) of path in
+// the working tree.
+func Blame(ctx context.Context, dir, path string, n int) (BlameLine, error) {
+	var b BlameLine
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return b, err
+	}
+	out, err := Run(ctx, dir, "blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", n, n), "--", path)
+	if err != nil {
+		return b, err
+	}
+
+	lines := strings.Split(out, "\n")
+	if len(lines) == 0 {
+		return b, fmt.Errorf("git blame returned nothing for %s:%d", path, n)
+	}
+	header := strings.Fields(lines[0])
+	if len(header) < 2 {
+		return b, fmt.Errorf("unexpected git blame output: %q", lines[0])
+	}
+	b.Commit.Hash = header[0]
+	b.Line, _ = strconv.Atoi(header[1])
+	b.Path = path
+	b.Uncommitted = strings.Trim(b.Commit.Hash, "0") == ""
+
+	for _, line := range lines[1:] {
+		if text, ok := strings.CutPrefix(line, "\t"); ok {
+			b.Text = text
+			break
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			b.Commit.Author = value
+		case "author-mail":
+			b.Commit.Email = strings.Trim(value, "<>")
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				b.Commit.Date = time.Unix(sec, 0)
+			}
+		case "summary":
+			b.Commit.Subject = value
+		case "filename":
+			b.Path = value
+		}
+	}
+	return b, nil
+}
+
+// FileChange is one commit in the history of a file with the patch it
+// applied to that file.
+type FileChange struct {
+	Commit LogEntry
+	Patch  string
+}
+
+// FileHistory returns the last n commits that changed path, following
+// renames, newest first.
+func FileHistory(ctx context.Context, dir, path string, n int) ([]FileChange, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	// The record separator comes first so that each record ends with the
+	// patch that follows the formatted header.
+	format := "--pretty=format:" + recordSep + "%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b%x1f"
+	out, err := Run(ctx, dir, "log", "--follow", "-p", "-n", strconv.Itoa(n), format, "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FileChange
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.SplitN(record, fieldSep, 7)
+		if len(fields) < 7 {
+			continue
+		}
+		entry := LogEntry{
+			Hash:    strings.TrimSpace(fields[0]),
+			Author:  fields[1],
+			Email:   fields[2],
+			Subject: fields[4],
+			Body:    strings.TrimSpace(fields[5]),
+		}
+		entry.Date, _ = time.Parse(time.RFC3339, fields[3])
+		changes = append(changes, FileChange{Commit: entry, Patch: strings.TrimSpace(fields[6])})
+	}
+	return changes, nil
+}
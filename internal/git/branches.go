@@ -0,0 +1,221 @@
+package git
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Branch describes a local branch or a remote-tracking branch.
+type Branch struct {
+	// Name is the short name, e.g. "main" or "origin/main".
+	Name string
+	// Remote is set for remote-tracking branches (refs/remotes/...).
+	Remote   bool
+	Current  bool
+	Upstream string
+	// Ahead and Behind count commits relative to Upstream.
+	Ahead  int
+	Behind int
+	// Gone is set when the upstream was deleted on the remote.
+	Gone        bool
+	Date        time.Time
+	Author      string
+	AuthorEmail string
+	Hash        string
+	Subject     string
+}
+
+// branchFormat matches the field order parsed by ListBranches.
+const branchFormat = "--format=%(HEAD)%1f%(refname)%1f%(refname:short)%1f%(upstream:short)%1f%(upstream:track,nobracket)%1f%(committerdate:iso-strict)%1f%(authorname)%1f%(authoremail)%1f%(objectname)%1f%(subject)"
+
+// ListBranches returns local and remote-tracking branches, most recent
+// commit first. Symbolic refs such as origin/HEAD are left out.
+func ListBranches(ctx context.Context, dir string) ([]Branch, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	// %(upstream:track) is translated, so parseTrack needs the C locale.
+	out, err := runEnv(ctx, dir, []string{"LC_ALL=C"}, "for-each-ref", "--sort=-committerdate", branchFormat, "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []Branch
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, fieldSep)
+		if len(fields) < 10 {
+			continue
+		}
+		ref := fields[1]
+		if strings.HasPrefix(ref, "refs/remotes/") && strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		b := Branch{
+			Name:        fields[2],
+			Remote:      strings.HasPrefix(ref, "refs/remotes/"),
+			Current:     fields[0] == "*",
+			Upstream:    fields[3],
+			Author:      fields[6],
+			AuthorEmail: strings.Trim(fields[7], "<>"),
+			Hash:        fields[8],
+			Subject:     fields[9],
+		}
+		b.Date, _ = time.Parse(time.RFC3339, fields[5])
+		parseTrack(&b, fields[4])
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
+
+// parseTrack reads %(upstream:track,nobracket), e.g. "ahead 1, behind 2" or "gone".
+func parseTrack(b *Branch, track string) {
+	for _, part := range strings.Split(track, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "gone":
+			b.Gone = true
+		case strings.HasPrefix(part, "ahead "):
+			b.Ahead, _ = strconv.Atoi(strings.TrimPrefix(part, "ahead "))
+		case strings.HasPrefix(part, "behind "):
+			b.Behind, _ = strconv.Atoi(strings.TrimPrefix(part, "behind "))
+		}
+	}
+}
+
+// FetchPrune fetches remote and removes remote-tracking branches that no
+// longer exist there.
+func FetchPrune(ctx context.Context, dir, remote string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "fetch", "--prune", remote)
+	return err
+}
+
+// MergedInto returns the short names of local and remote-tracking branches
+// whose tip is reachable from ref.
+func MergedInto(ctx context.Context, dir, ref string) (map[string]bool, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, "for-each-ref", "--merged="+ref, "--format=%(refname:short)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			merged[line] = true
+		}
+	}
+	return merged, nil
+}
+
+// DeleteBranch deletes a local branch. Without force, git refuses to
+// delete a branch that is not merged.
+func DeleteBranch(ctx context.Context, dir, name string, force bool) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := Run(ctx, dir, "branch", flag, name)
+	return err
+}
+
+// DeleteRemoteBranch deletes name on remote.
+func DeleteRemoteBranch(ctx context.Context, dir, remote, name string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "push", remote, "--delete", name)
+	return err
+}
+
+// UserEmail returns the configured user.email, or "" if unset.
+func UserEmail(ctx context.Context, dir string) string {
+	out, err := Run(ctx, dir, "config", "--get", "user.email")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// BranchCommit is a commit together with the branch it was found on.
+type BranchCommit struct {
+	LogEntry
+	// Branch is the branch name without "refs/heads/" or the remote prefix,
+	// so local and pushed work land in the same group.
+	Branch string
+}
+
+// ActivityOptions selects commits for Activity. Since and Until accept
+// anything git log does, e.g. "yesterday" or "2024-05-01".
+type ActivityOptions struct {
+	Since  string
+	Until  string
+	Author string
+}
+
+// Activity returns the non-merge commits on local and remote-tracking
+// branches that match opts, newest first. Each commit appears once, on the
+// first branch git reached it from.
+func Activity(ctx context.Context, dir string, opts ActivityOptions) ([]BranchCommit, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	args := []string{"log", "--branches", "--remotes", "--source", "--no-merges",
+		"--pretty=format:%S" + fieldSep + "%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b%x1e"}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+opts.Until)
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	out, err := Run(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	remotes := make(map[string]bool)
+	if list, err := Run(ctx, dir, "remote"); err == nil {
+		for _, r := range strings.Fields(list) {
+			remotes[r] = true
+		}
+	}
+
+	var commits []BranchCommit
+	for _, record := range strings.Split(out, recordSep) {
+		record = strings.TrimLeft(record, "\r\n")
+		source, rest, ok := strings.Cut(record, fieldSep)
+		if !ok {
+			continue
+		}
+		entries := parseCommitLog(rest)
+		if len(entries) == 0 {
+			continue
+		}
+		commits = append(commits, BranchCommit{LogEntry: entries[0], Branch: sourceBranch(source, remotes)})
+	}
+	return commits, nil
+}
+
+// sourceBranch turns a %S ref such as "origin/feat" or "refs/heads/feat"
+// into "feat".
+func sourceBranch(ref string, remotes map[string]bool) string {
+	if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		return name
+	}
+	ref = strings.TrimPrefix(ref, "refs/remotes/")
+	if remote, branch, ok := strings.Cut(ref, "/"); ok && remotes[remote] {
+		return branch
+	}
+	return ref
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTrack(t *testing.T) {
+	tests := []struct {
+		track string
+		want  Branch
+	}{
+		{"", Branch{}},
+		{"gone", Branch{Gone: true}},
+		{"ahead 3", Branch{Ahead: 3}},
+		{"behind 12", Branch{Behind: 12}},
+		{"ahead 1, behind 2", Branch{Ahead: 1, Behind: 2}},
+		{" ahead 4 ,behind 5 ", Branch{Ahead: 4, Behind: 5}},
+		{"ahead x", Branch{}},
+	}
+	for _, tt := range tests {
+		var got Branch
+		parseTrack(&got, tt.track)
+		if got != tt.want {
+			t.Errorf("parseTrack(%q) = %+v, want %+v", tt.track, got, tt.want)
+		}
+	}
+}
+
+func TestListBranchesTracking(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	// The tracking counts must not depend on the user's locale.
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	ctx := context.Background()
+	root := t.TempDir()
+	upstream := filepath.Join(root, "upstream")
+	clone := filepath.Join(root, "clone")
+
+	gitCmd(t, root, "init", "--quiet", "--initial-branch=main", upstream)
+	gitCmd(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	gitCmd(t, upstream, "branch", "doomed")
+	gitCmd(t, root, "clone", "--quiet", upstream, clone)
+	gitCmd(t, clone, "branch", "--quiet", "--track", "doomed", "origin/doomed")
+	gitCmd(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "upstream work")
+	gitCmd(t, upstream, "branch", "-D", "doomed")
+	gitCmd(t, clone, "commit", "--quiet", "--allow-empty", "-m", "local work")
+	gitCmd(t, clone, "fetch", "--quiet", "--prune")
+
+	branches, err := ListBranches(ctx, clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]Branch)
+	for _, b := range branches {
+		byName[b.Name] = b
+	}
+	if main := byName["main"]; !main.Current || main.Upstream != "origin/main" || main.Ahead != 1 || main.Behind != 1 {
+		t.Errorf("main = %+v, want current, ahead 1 and behind 1 of origin/main", main)
+	}
+	if doomed := byName["doomed"]; !doomed.Gone {
+		t.Errorf("doomed = %+v, want its upstream gone", doomed)
+	}
+	if remote := byName["origin/main"]; !remote.Remote {
+		t.Errorf("origin/main = %+v, want a remote-tracking branch", remote)
+	}
+	if _, ok := byName["origin/HEAD"]; ok {
+		t.Error("ListBranches included origin/HEAD")
+	}
+}
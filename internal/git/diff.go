@@ -0,0 +1,793 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineKind classifies a line inside a diff hunk.
+type LineKind int
+
+const (
+	// LineContext is an unchanged line present on both sides.
+	LineContext LineKind = iota
+	// LineAdded exists only in the new version.
+	LineAdded
+	// LineDeleted exists only in the old version.
+	LineDeleted
+)
+
+// Prefix returns the unified diff marker for the kind.
+func (k LineKind) Prefix() string {
+	switch k {
+	case LineAdded:
+		return "+"
+	case LineDeleted:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+// String implements fmt.Stringer.
+func (k LineKind) String() string {
+	switch k {
+	case LineAdded:
+		return "added"
+	case LineDeleted:
+		return "deleted"
+	default:
+		return "context"
+	}
+}
+
+// FileStatus describes what happened to a file in a diff.
+type FileStatus string
+
+const (
+	FileModified FileStatus = "modified"
+	FileAdded    FileStatus = "added"
+	FileDeleted  FileStatus = "deleted"
+	FileRenamed  FileStatus = "renamed"
+	FileCopied   FileStatus = "copied"
+)
+
+// Line is a single line of a hunk with its position on each side.
+// OldNumber is zero for added lines, NewNumber is zero for deleted lines.
+type Line struct {
+	Kind      LineKind
+	Content   string
+	OldNumber int
+	NewNumber int
+	// NoNewline is set when the line is followed by
+	// "\ No newline at end of file".
+	NoNewline bool
+}
+
+// Hunk is one "@@ -a,b +c,d @@" block of a unified diff.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Section is the optional text after the closing @@, usually the
+	// enclosing function signature.
+	Section string
+	Lines   []Line
+}
+
+// FileDiff holds the metadata and hunks changing a single file.
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Status  FileStatus
+	// OldMode and NewMode are set for new/deleted files and mode changes.
+	OldMode string
+	NewMode string
+	// Similarity is the percentage reported for renames and copies.
+	Similarity int
+	// OldHash and NewHash are the abbreviated blob ids from the index line;
+	// IndexMode is the unchanged mode that git appends to it.
+	OldHash   string
+	NewHash   string
+	IndexMode string
+	// IsBinary marks files git reported as binary. BinaryPatch keeps the
+	// raw "GIT binary patch" body when the diff was made with --binary.
+	IsBinary    bool
+	BinaryPatch string
+	Hunks       []Hunk
+}
+
+// Diff is a parsed unified diff covering one or more files.
+type Diff struct {
+	Files []FileDiff
+}
+
+var (
+	hunkHeaderRe  = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+	indexHeaderRe = regexp.MustCompile(`^index ([0-9a-f]+)\.\.([0-9a-f]+)(?: ([0-7]+))?$`)
+)
+
+// diffFormatArgs pin the patch format ParseDiff expects, whatever the
+// user's configuration says about colors, path prefixes or external diff
+// tools, and turn on rename detection.
+var diffFormatArgs = []string{"--patch", "--find-renames", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/"}
+
+// LoadDiff runs git diff with --patch and --find-renames plus args (for
+// example "--cached" or "HEAD") and parses the result.
+func LoadDiff(ctx context.Context, dir string, args ...string) (*Diff, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, append(append([]string{"diff"}, diffFormatArgs...), args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out)
+}
+
+// LoadStagedDiff parses the staged changes (git diff --cached), optionally
+// limited to paths.
+func LoadStagedDiff(ctx context.Context, dir string, paths ...string) (*Diff, error) {
+	return LoadDiff(ctx, dir, withPaths([]string{"--cached"}, paths)...)
+}
+
+// LoadWorkingTreeDiff parses the unstaged changes (git diff), optionally
+// limited to paths.
+func LoadWorkingTreeDiff(ctx context.Context, dir string, paths ...string) (*Diff, error) {
+	return LoadDiff(ctx, dir, withPaths(nil, paths)...)
+}
+
+// LoadRangeDiff parses the diff for a revision range such as "main..HEAD",
+// optionally limited to paths.
+func LoadRangeDiff(ctx context.Context, dir, revRange string, paths ...string) (*Diff, error) {
+	if err := CheckRevision(revRange); err != nil {
+		return nil, err
+	}
+	return LoadDiff(ctx, dir, withPaths([]string{revRange}, paths)...)
+}
+
+// LoadCommitDiff parses the patch introduced by a single commit (git show),
+// optionally limited to paths. The commit message is not included.
+func LoadCommitDiff(ctx context.Context, dir, rev string, paths ...string) (*Diff, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	if err := CheckRevision(rev); err != nil {
+		return nil, err
+	}
+	args := append(append([]string{"show", "--format="}, diffFormatArgs...), rev)
+	out, err := Run(ctx, dir, withPaths(args, paths)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out)
+}
+
+// ParseDiff parses the output of git diff or git show. Text before the
+// first "diff --git" header (such as a commit message) is ignored, as are
+// the combined diffs ("diff --cc") git show prints for merge commits.
+func ParseDiff(text string) (*Diff, error) {
+	p := diffParser{diff: &Diff{}}
+	lines := strings.Split(text, "\n")
+	// A trailing newline yields an empty last element that is not a line.
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for _, line := range lines {
+		if err := p.parseLine(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.flushFile(); err != nil {
+		return nil, err
+	}
+	return p.diff, nil
+}
+
+// diffParser is the state machine behind ParseDiff.
+type diffParser struct {
+	diff     *Diff
+	file     *FileDiff
+	hunk     *Hunk
+	oldLine  int
+	newLine  int
+	inBinary bool
+	binary   strings.Builder
+}
+
+// parseLine consumes one line. Hunk bodies and binary patches keep carriage
+// returns, which belong to the content of CRLF files; header lines have
+// them removed.
+func (p *diffParser) parseLine(raw string) error {
+	line := strings.TrimRight(raw, "\r")
+	if strings.HasPrefix(line, "diff --git ") {
+		if err := p.flushFile(); err != nil {
+			return err
+		}
+		p.file = &FileDiff{Status: FileModified}
+		p.file.OldPath, p.file.NewPath = parseDiffGitHeader(line)
+		return nil
+	}
+	if strings.HasPrefix(line, "diff --cc ") || strings.HasPrefix(line, "diff --combined ") {
+		// Combined diffs have one column per parent and cannot be
+		// represented as a FileDiff; skip until the next "diff --git".
+		return p.flushFile()
+	}
+	if p.file == nil {
+		return nil
+	}
+
+	if p.hunk != nil && p.hunkOpen() {
+		switch {
+		case strings.HasPrefix(raw, "+"):
+			p.hunk.Lines = append(p.hunk.Lines, Line{Kind: LineAdded, Content: raw[1:], NewNumber: p.newLine})
+			p.newLine++
+			return nil
+		case strings.HasPrefix(raw, "-"):
+			p.hunk.Lines = append(p.hunk.Lines, Line{Kind: LineDeleted, Content: raw[1:], OldNumber: p.oldLine})
+			p.oldLine++
+			return nil
+		case strings.HasPrefix(raw, " "), line == "":
+			content := ""
+			if strings.HasPrefix(raw, " ") {
+				content = raw[1:]
+			}
+			p.hunk.Lines = append(p.hunk.Lines, Line{Kind: LineContext, Content: content, OldNumber: p.oldLine, NewNumber: p.newLine})
+			p.oldLine++
+			p.newLine++
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(line, `\`) {
+		// "\ No newline at end of file" applies to the previous line.
+		if p.hunk != nil && len(p.hunk.Lines) > 0 {
+			p.hunk.Lines[len(p.hunk.Lines)-1].NoNewline = true
+		}
+		return nil
+	}
+
+	if p.inBinary {
+		p.binary.WriteString(raw)
+		p.binary.WriteString("\n")
+		return nil
+	}
+
+	if strings.HasPrefix(line, "@@ ") {
+		p.flushHunk()
+		h, err := parseHunkHeader(line)
+		if err != nil {
+			return err
+		}
+		p.hunk = &h
+		p.oldLine, p.newLine = h.OldStart, h.NewStart
+		return nil
+	}
+	if p.hunk != nil {
+		// Trailing text after a complete hunk that is not another header.
+		return nil
+	}
+
+	p.parseExtendedHeader(line)
+	return nil
+}
+
+// parseExtendedHeader handles the lines between "diff --git" and the first hunk.
+func (p *diffParser) parseExtendedHeader(line string) {
+	f := p.file
+	switch {
+	case strings.HasPrefix(line, "old mode "):
+		f.OldMode = strings.TrimPrefix(line, "old mode ")
+	case strings.HasPrefix(line, "new mode "):
+		f.NewMode = strings.TrimPrefix(line, "new mode ")
+	case strings.HasPrefix(line, "new file mode "):
+		f.Status = FileAdded
+		f.NewMode = strings.TrimPrefix(line, "new file mode ")
+		f.OldPath = ""
+	case strings.HasPrefix(line, "deleted file mode "):
+		f.Status = FileDeleted
+		f.OldMode = strings.TrimPrefix(line, "deleted file mode ")
+		f.NewPath = ""
+	case strings.HasPrefix(line, "similarity index "):
+		f.Similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+	case strings.HasPrefix(line, "rename from "):
+		f.Status = FileRenamed
+		f.OldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+	case strings.HasPrefix(line, "rename to "):
+		f.Status = FileRenamed
+		f.NewPath = unquotePath(strings.TrimPrefix(line, "rename to "))
+	case strings.HasPrefix(line, "copy from "):
+		f.Status = FileCopied
+		f.OldPath = unquotePath(strings.TrimPrefix(line, "copy from "))
+	case strings.HasPrefix(line, "copy to "):
+		f.Status = FileCopied
+		f.NewPath = unquotePath(strings.TrimPrefix(line, "copy to "))
+	case strings.HasPrefix(line, "index "):
+		if m := indexHeaderRe.FindStringSubmatch(line); m != nil {
+			f.OldHash, f.NewHash, f.IndexMode = m[1], m[2], m[3]
+		}
+	case strings.HasPrefix(line, "Binary files "):
+		f.IsBinary = true
+	case line == "GIT binary patch":
+		f.IsBinary = true
+		p.inBinary = true
+	case strings.HasPrefix(line, "--- "):
+		if path, ok := parsePatchPath(line[4:]); !ok {
+			f.Status = FileAdded
+		} else if path != "" {
+			f.OldPath = path
+		}
+	case strings.HasPrefix(line, "+++ "):
+		if path, ok := parsePatchPath(line[4:]); !ok {
+			f.Status = FileDeleted
+		} else if path != "" {
+			f.NewPath = path
+		}
+	}
+}
+
+// hunkOpen reports whether the current hunk still expects body lines.
+func (p *diffParser) hunkOpen() bool {
+	return p.oldLine < p.hunk.OldStart+p.hunk.OldLines || p.newLine < p.hunk.NewStart+p.hunk.NewLines
+}
+
+func (p *diffParser) flushHunk() {
+	if p.file != nil && p.hunk != nil {
+		p.file.Hunks = append(p.file.Hunks, *p.hunk)
+	}
+	p.hunk = nil
+}
+
+func (p *diffParser) flushFile() error {
+	p.flushHunk()
+	file := p.file
+	p.file = nil
+	if file == nil {
+		return nil
+	}
+	if p.inBinary {
+		file.BinaryPatch = p.binary.String()
+	}
+	p.inBinary = false
+	p.binary.Reset()
+	file.normalizePaths()
+	if file.Status != FileRenamed && file.Status != FileCopied {
+		// The similarity index only describes renames and copies.
+		file.Similarity = 0
+	}
+	if file.Path() == "" {
+		return errors.New("invalid diff: file header without a path")
+	}
+	p.diff.Files = append(p.diff.Files, *file)
+	return nil
+}
+
+func parseHunkHeader(line string) (Hunk, error) {
+	m := hunkHeaderRe.FindStringSubmatch(line)
+	if m == nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	h := Hunk{OldLines: 1, NewLines: 1, Section: m[5]}
+	h.OldStart, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		h.OldLines, _ = strconv.Atoi(m[2])
+	}
+	h.NewStart, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		h.NewLines, _ = strconv.Atoi(m[4])
+	}
+	// Counts beyond any real file indicate a corrupt header; rejecting them
+	// keeps the parser from waiting for billions of body lines.
+	if h.OldLines > 1<<24 || h.NewLines > 1<<24 {
+		return Hunk{}, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	return h, nil
+}
+
+// parseDiffGitHeader extracts paths from "diff --git a/x b/y". Unquoted
+// paths with spaces are ambiguous here, so the later rename/copy and
+// ---/+++ lines take precedence when present.
+func parseDiffGitHeader(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+
+	if strings.HasPrefix(rest, `"`) {
+		if end := closingQuote(rest); end > 0 {
+			oldPath := unquotePath(rest[:end+1])
+			return stripPrefix(oldPath), stripPrefix(unquotePath(strings.TrimPrefix(rest[end+1:], " ")))
+		}
+	}
+	if strings.HasSuffix(rest, `"`) {
+		if start := strings.LastIndex(rest[:len(rest)-1], ` "`); start != -1 {
+			return stripPrefix(rest[:start]), stripPrefix(unquotePath(rest[start+1:]))
+		}
+	}
+
+	// Identical names on both sides: split the header in the middle.
+	if len(rest)%2 == 1 {
+		half := len(rest) / 2
+		if rest[half] == ' ' && stripPrefix(rest[:half]) == stripPrefix(rest[half+1:]) {
+			return stripPrefix(rest[:half]), stripPrefix(rest[half+1:])
+		}
+	}
+	if idx := strings.Index(rest, " b/"); idx != -1 && strings.HasPrefix(rest, "a/") {
+		return rest[2:idx], rest[idx+3:]
+	}
+	return "", ""
+}
+
+// closingQuote returns the index of the quote ending the quoted string at s[0].
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePatchPath strips the a/ or b/ prefix from a ---/+++ path. It
+// reports false for /dev/null, meaning that side of the change is missing.
+func parsePatchPath(p string) (string, bool) {
+	// A tab separates the path from an optional timestamp; git also adds a
+	// bare trailing tab when the path contains a space.
+	if strings.HasPrefix(p, `"`) {
+		if end := closingQuote(p); end > 0 {
+			p = p[:end+1]
+		}
+	} else if idx := strings.Index(p, "\t"); idx != -1 {
+		p = p[:idx]
+	}
+	if p == "/dev/null" {
+		return "", false
+	}
+	return stripPrefix(unquotePath(p)), true
+}
+
+func stripPrefix(p string) string {
+	if strings.HasPrefix(p, "a/") || strings.HasPrefix(p, "b/") {
+		return p[2:]
+	}
+	return p
+}
+
+// unquotePath decodes git's C-style quoted paths ("a/caf\303\251.txt").
+func unquotePath(p string) string {
+	if len(p) < 2 || !strings.HasPrefix(p, `"`) || !strings.HasSuffix(p, `"`) {
+		return p
+	}
+	if s, err := strconv.Unquote(p); err == nil {
+		return s
+	}
+	return p
+}
+
+// quotePath quotes a path the way git does when it contains special bytes.
+func quotePath(p string) string {
+	needsQuote := false
+	for i := 0; i < len(p); i++ {
+		if c := p[i]; c < 0x20 || c >= 0x7f || c == '"' || c == '\\' {
+			needsQuote = true
+			break
+		}
+	}
+	if !needsQuote {
+		return p
+	}
+	return cQuote(p)
+}
+
+// cQuote quotes p with git's C-style escapes.
+func cQuote(p string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\v':
+			b.WriteString(`\v`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if c < 0x20 || c >= 0x7f {
+				fmt.Fprintf(&b, `\%03o`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Path returns the path of the file after the change, or the old path for deletions.
+func (f *FileDiff) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Stats returns the number of added and deleted lines in the file.
+func (f *FileDiff) Stats() (added, deleted int) {
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case LineAdded:
+				added++
+			case LineDeleted:
+				deleted++
+			}
+		}
+	}
+	return added, deleted
+}
+
+// File returns the entry for path, matching either side of the change.
+// When no exact match exists, a unique entry whose path ends with path is
+// returned, which tolerates reviewers that cite shortened paths.
+func (d *Diff) File(path string) *FileDiff {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "./"), "b/")
+	for i := range d.Files {
+		if d.Files[i].NewPath == path || d.Files[i].OldPath == path {
+			return &d.Files[i]
+		}
+	}
+
+	var match *FileDiff
+	for i := range d.Files {
+		if path != "" && strings.HasSuffix(d.Files[i].Path(), "/"+path) {
+			if match != nil {
+				return nil
+			}
+			match = &d.Files[i]
+		}
+	}
+	return match
+}
+
+// Filter returns a new Diff containing only the files for which keep is true.
+func (d *Diff) Filter(keep func(FileDiff) bool) *Diff {
+	out := &Diff{}
+	for _, f := range d.Files {
+		if keep(f) {
+			out.Files = append(out.Files, f)
+		}
+	}
+	return out
+}
+
+// NewLines returns the lines of the new version that appear in the diff
+// (context and additions), keyed by new line number.
+func (f *FileDiff) NewLines() map[int]Line {
+	lines := make(map[int]Line)
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			if l.Kind != LineDeleted {
+				lines[l.NewNumber] = l
+			}
+		}
+	}
+	return lines
+}
+
+// ContainsNewLine reports whether line n of the new version is inside a hunk.
+func (f *FileDiff) ContainsNewLine(n int) bool {
+	for _, h := range f.Hunks {
+		if n >= h.NewStart && n < h.NewStart+h.NewLines {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the diff back into a patch that git apply accepts.
+func (d *Diff) String() string {
+	var b strings.Builder
+	for i := range d.Files {
+		d.Files[i].writePatch(&b)
+	}
+	return b.String()
+}
+
+// String renders a single file's patch.
+func (f *FileDiff) String() string {
+	var b strings.Builder
+	f.writePatch(&b)
+	return b.String()
+}
+
+func (f *FileDiff) writePatch(b *strings.Builder) {
+	oldPath, newPath := f.OldPath, f.NewPath
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+	header := quotePath("a/"+oldPath) + " " + quotePath("b/"+newPath)
+	if o, n := parseDiffGitHeader("diff --git " + header); o != oldPath || n != newPath {
+		// Names with spaces can make the unquoted header ambiguous; git
+		// accepts quoted names, which always parse back unchanged.
+		header = cQuote("a/"+oldPath) + " " + cQuote("b/"+newPath)
+	}
+	fmt.Fprintf(b, "diff --git %s\n", header)
+
+	switch {
+	case f.Status == FileAdded:
+		if f.NewMode != "" {
+			fmt.Fprintf(b, "new file mode %s\n", f.NewMode)
+		}
+	case f.Status == FileDeleted:
+		if f.OldMode != "" {
+			fmt.Fprintf(b, "deleted file mode %s\n", f.OldMode)
+		}
+	default:
+		if f.OldMode != "" {
+			fmt.Fprintf(b, "old mode %s\n", f.OldMode)
+		}
+		if f.NewMode != "" {
+			fmt.Fprintf(b, "new mode %s\n", f.NewMode)
+		}
+	}
+	switch f.Status {
+	case FileRenamed, FileCopied:
+		verb := "rename"
+		if f.Status == FileCopied {
+			verb = "copy"
+		}
+		fmt.Fprintf(b, "similarity index %d%%\n", f.Similarity)
+		fmt.Fprintf(b, "%s from %s\n%s to %s\n", verb, quotePath(f.OldPath), verb, quotePath(f.NewPath))
+	}
+	if f.OldHash != "" || f.NewHash != "" {
+		fmt.Fprintf(b, "index %s..%s", f.OldHash, f.NewHash)
+		if f.IndexMode != "" {
+			fmt.Fprintf(b, " %s", f.IndexMode)
+		}
+		b.WriteString("\n")
+	}
+
+	if f.IsBinary {
+		if f.BinaryPatch != "" {
+			b.WriteString("GIT binary patch\n")
+			b.WriteString(f.BinaryPatch)
+			return
+		}
+		fmt.Fprintf(b, "Binary files %s and %s differ\n", binaryPath("a/", f.sidePath(FileAdded, oldPath)), binaryPath("b/", f.sidePath(FileDeleted, newPath)))
+		return
+	}
+	// Without hunks the ---/+++ lines are only needed to mark an added or
+	// deleted file that has no mode line.
+	if len(f.Hunks) == 0 && !(f.Status == FileAdded && f.NewMode == "") && !(f.Status == FileDeleted && f.OldMode == "") {
+		return
+	}
+
+	fmt.Fprintf(b, "--- %s\n+++ %s\n", patchPath("a/", f.sidePath(FileAdded, oldPath)), patchPath("b/", f.sidePath(FileDeleted, newPath)))
+	for _, h := range f.Hunks {
+		fmt.Fprintf(b, "@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+		if h.Section != "" {
+			b.WriteString(" " + h.Section)
+		}
+		b.WriteString("\n")
+		for _, l := range h.Lines {
+			b.WriteString(l.Kind.Prefix())
+			b.WriteString(l.Content)
+			b.WriteString("\n")
+			if l.NoNewline {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+}
+
+// normalizePaths makes the paths agree with the status: added files have no
+// old path or mode, deleted files no new path or mode, and everything else
+// has both paths.
+func (f *FileDiff) normalizePaths() {
+	switch f.Status {
+	case FileAdded:
+		f.OldPath = ""
+		f.OldMode = ""
+	case FileDeleted:
+		f.NewPath = ""
+		f.NewMode = ""
+	default:
+		if f.OldPath == "" {
+			f.OldPath = f.NewPath
+		}
+		if f.NewPath == "" {
+			f.NewPath = f.OldPath
+		}
+	}
+}
+
+// sidePath returns "" (rendered as /dev/null) when the file has the given
+// status, meaning that side of the change does not exist.
+func (f *FileDiff) sidePath(missing FileStatus, path string) string {
+	if f.Status == missing {
+		return ""
+	}
+	return path
+}
+
+// patchPath renders a ---/+++ path, with the trailing tab git emits for
+// names containing spaces.
+func patchPath(prefix, path string) string {
+	if path == "" {
+		return "/dev/null"
+	}
+	if strings.Contains(path, " ") {
+		return quotePath(prefix+path) + "\t"
+	}
+	return quotePath(prefix + path)
+}
+
+func binaryPath(prefix, path string) string {
+	if path == "" {
+		return "/dev/null"
+	}
+	return quotePath(prefix + path)
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// AnnotatedString renders the diff with new-side line numbers in a gutter so
+// that a reader (or model) can cite exact positions. Deleted lines show the
+// old line number prefixed with "-".
+func (d *Diff) AnnotatedString() string {
+	var b strings.Builder
+	for _, f := range d.Files {
+		b.WriteString(fmt.Sprintf("=== %s", f.Path()))
+		switch {
+		case f.Status == FileRenamed || f.Status == FileCopied:
+			b.WriteString(fmt.Sprintf(" (%s from %s)", f.Status, f.OldPath))
+		case f.Status != FileModified:
+			b.WriteString(fmt.Sprintf(" (%s)", f.Status))
+		}
+		if f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode {
+			b.WriteString(fmt.Sprintf(" (mode %s -> %s)", f.OldMode, f.NewMode))
+		}
+		if f.IsBinary {
+			b.WriteString(" (binary)")
+		}
+		b.WriteString(" ===\n")
+		for _, h := range f.Hunks {
+			b.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@ %s\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines, h.Section))
+			for _, l := range h.Lines {
+				switch l.Kind {
+				case LineAdded:
+					b.WriteString(fmt.Sprintf("%6d + %s\n", l.NewNumber, l.Content))
+				case LineDeleted:
+					b.WriteString(fmt.Sprintf("%6s - %s\n", "-"+strconv.Itoa(l.OldNumber), l.Content))
+				default:
+					b.WriteString(fmt.Sprintf("%6d   %s\n", l.NewNumber, l.Content))
+				}
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,439 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var parseDiffTests = []struct {
+	name  string
+	input string
+	want  []FileDiff
+}{
+	{
+		name: "modified",
+		input: `diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@ package main
+ import "fmt"
+-func a() {}
++func a() { fmt.Println() }
++func b() {}
+ // end
+`,
+		want: []FileDiff{{
+			OldPath: "main.go", NewPath: "main.go", Status: FileModified,
+			OldHash: "83db48f", NewHash: "bf269f4", IndexMode: "100644",
+			Hunks: []Hunk{{OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 4, Section: "package main", Lines: []Line{
+				{Kind: LineContext, Content: `import "fmt"`, OldNumber: 1, NewNumber: 1},
+				{Kind: LineDeleted, Content: "func a() {}", OldNumber: 2},
+				{Kind: LineAdded, Content: "func a() { fmt.Println() }", NewNumber: 2},
+				{Kind: LineAdded, Content: "func b() {}", NewNumber: 3},
+				{Kind: LineContext, Content: "// end", OldNumber: 3, NewNumber: 4},
+			}}},
+		}},
+	},
+	{
+		name: "rename with changes",
+		input: `diff --git a/old name.go b/new name.go
+similarity index 90%
+rename from old name.go
+rename to new name.go
+index 1111111..2222222 100644
+--- a/old name.go	
++++ b/new name.go	
+@@ -1 +1 @@
+-x
++y
+`,
+		want: []FileDiff{{
+			OldPath: "old name.go", NewPath: "new name.go", Status: FileRenamed, Similarity: 90,
+			OldHash: "1111111", NewHash: "2222222", IndexMode: "100644",
+			Hunks: []Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []Line{
+				{Kind: LineDeleted, Content: "x", OldNumber: 1},
+				{Kind: LineAdded, Content: "y", NewNumber: 1},
+			}}},
+		}},
+	},
+	{
+		name: "pure rename",
+		input: `diff --git a/a.txt b/dir/b.txt
+similarity index 100%
+rename from a.txt
+rename to dir/b.txt
+`,
+		want: []FileDiff{{OldPath: "a.txt", NewPath: "dir/b.txt", Status: FileRenamed, Similarity: 100}},
+	},
+	{
+		name: "copy",
+		input: `diff --git a/src.go b/dst.go
+similarity index 75%
+copy from src.go
+copy to dst.go
+index 1111111..3333333
+--- a/src.go
++++ b/dst.go
+@@ -2,0 +3 @@ func f() {
++	return
+`,
+		want: []FileDiff{{
+			OldPath: "src.go", NewPath: "dst.go", Status: FileCopied, Similarity: 75,
+			OldHash: "1111111", NewHash: "3333333",
+			Hunks: []Hunk{{OldStart: 2, OldLines: 0, NewStart: 3, NewLines: 1, Section: "func f() {", Lines: []Line{
+				{Kind: LineAdded, Content: "\treturn", NewNumber: 3},
+			}}},
+		}},
+	},
+	{
+		name: "quoted paths",
+		input: `diff --git "a/caf\303\251.txt" "b/caf\303\251.txt"
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ "b/caf\303\251.txt"
+@@ -0,0 +1 @@
++hi
+`,
+		want: []FileDiff{{
+			NewPath: "café.txt", Status: FileAdded, NewMode: "100644",
+			OldHash: "0000000", NewHash: "e69de29",
+			Hunks: []Hunk{{OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 1, Lines: []Line{
+				{Kind: LineAdded, Content: "hi", NewNumber: 1},
+			}}},
+		}},
+	},
+	{
+		name: "binary marker",
+		input: `diff --git a/logo.png b/logo.png
+index 1111111..2222222 100644
+Binary files a/logo.png and b/logo.png differ
+`,
+		want: []FileDiff{{
+			OldPath: "logo.png", NewPath: "logo.png", Status: FileModified,
+			OldHash: "1111111", NewHash: "2222222", IndexMode: "100644", IsBinary: true,
+		}},
+	},
+	{
+		name: "new binary file",
+		input: `diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..2222222
+Binary files /dev/null and b/logo.png differ
+`,
+		want: []FileDiff{{
+			NewPath: "logo.png", Status: FileAdded, NewMode: "100644",
+			OldHash: "0000000", NewHash: "2222222", IsBinary: true,
+		}},
+	},
+	{
+		name: "git binary patch",
+		input: `diff --git a/blob.bin b/blob.bin
+index 1111111..2222222 100644
+GIT binary patch
+literal 3
+KcmZQzWMT%Q00RI3
+
+literal 0
+HcmV?d00001
+
+`,
+		want: []FileDiff{{
+			OldPath: "blob.bin", NewPath: "blob.bin", Status: FileModified,
+			OldHash: "1111111", NewHash: "2222222", IndexMode: "100644", IsBinary: true,
+			BinaryPatch: "literal 3\nKcmZQzWMT%Q00RI3\n\nliteral 0\nHcmV?d00001\n\n",
+		}},
+	},
+	{
+		name: "mode change only",
+		input: `diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`,
+		want: []FileDiff{{OldPath: "run.sh", NewPath: "run.sh", Status: FileModified, OldMode: "100644", NewMode: "100755"}},
+	},
+	{
+		name: "combined rename and mode change header",
+		input: `diff --git a/a.sh b/b.sh
+old mode 100644
+new mode 100755
+similarity index 95%
+rename from a.sh
+rename to b.sh
+index 1111111..2222222
+--- a/a.sh
++++ b/b.sh
+@@ -1 +1,2 @@
+ #!/bin/sh
++echo hi
+`,
+		want: []FileDiff{{
+			OldPath: "a.sh", NewPath: "b.sh", Status: FileRenamed, Similarity: 95,
+			OldMode: "100644", NewMode: "100755", OldHash: "1111111", NewHash: "2222222",
+			Hunks: []Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 2, Lines: []Line{
+				{Kind: LineContext, Content: "#!/bin/sh", OldNumber: 1, NewNumber: 1},
+				{Kind: LineAdded, Content: "echo hi", NewNumber: 2},
+			}}},
+		}},
+	},
+	{
+		name: "deleted file",
+		input: `diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 1111111..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1,2 +0,0 @@
+-a
+-b
+`,
+		want: []FileDiff{{
+			OldPath: "gone.txt", Status: FileDeleted, OldMode: "100644",
+			OldHash: "1111111", NewHash: "0000000",
+			Hunks: []Hunk{{OldStart: 1, OldLines: 2, NewStart: 0, NewLines: 0, Lines: []Line{
+				{Kind: LineDeleted, Content: "a", OldNumber: 1},
+				{Kind: LineDeleted, Content: "b", OldNumber: 2},
+			}}},
+		}},
+	},
+	{
+		name: "no newline at end of file",
+		input: `diff --git a/x.txt b/x.txt
+index 1111111..2222222 100644
+--- a/x.txt
++++ b/x.txt
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++c
+\ No newline at end of file
+`,
+		want: []FileDiff{{
+			OldPath: "x.txt", NewPath: "x.txt", Status: FileModified,
+			OldHash: "1111111", NewHash: "2222222", IndexMode: "100644",
+			Hunks: []Hunk{{OldStart: 1, OldLines: 2, NewStart: 1, NewLines: 2, Lines: []Line{
+				{Kind: LineContext, Content: "a", OldNumber: 1, NewNumber: 1},
+				{Kind: LineDeleted, Content: "b", OldNumber: 2, NoNewline: true},
+				{Kind: LineAdded, Content: "c", NewNumber: 2, NoNewline: true},
+			}}},
+		}},
+	},
+	{
+		name: "commit header and combined merge diff",
+		input: `commit fd3ef9fbd7e02d67545aa443135d16f0f7a61d08
+Merge: f13e0d2 11edf78
+Author: Dev <dev@example.com>
+
+    merge
+
+diff --git a/kept.txt b/kept.txt
+old mode 100644
+new mode 100755
+diff --cc f
+index 08ae698,93829c7..404511b
+--- a/f
++++ b/f
+@@@ -1,2 -1,2 +1,2 @@@
+  a
+- y
+ -x
+++xy
+`,
+		want: []FileDiff{{OldPath: "kept.txt", NewPath: "kept.txt", Status: FileModified, OldMode: "100644", NewMode: "100755"}},
+	},
+	{
+		name:  "no diff",
+		input: "commit 1234\n\n    empty\n",
+	},
+}
+
+func TestParseDiff(t *testing.T) {
+	for _, tt := range parseDiffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDiff(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(d.Files, tt.want) {
+				t.Fatalf("ParseDiff() =\n%#v\nwant\n%#v", d.Files, tt.want)
+			}
+
+			again, err := ParseDiff(d.String())
+			if err != nil {
+				t.Fatalf("ParseDiff(String()): %v\n%s", err, d.String())
+			}
+			if !reflect.DeepEqual(again, d) {
+				t.Fatalf("round trip changed the diff:\n%s\ngot\n%#v\nwant\n%#v", d.String(), again.Files, d.Files)
+			}
+		})
+	}
+}
+
+func TestParseDiffRendersInput(t *testing.T) {
+	// Diffs exactly as git prints them render back byte for byte.
+	for _, name := range []string{"modified", "rename with changes", "copy", "quoted paths", "binary marker", "git binary patch", "mode change only", "deleted file", "no newline at end of file"} {
+		for _, tt := range parseDiffTests {
+			if tt.name != name {
+				continue
+			}
+			d, err := ParseDiff(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d.String(); got != tt.input {
+				t.Errorf("%s: String() =\n%s\nwant\n%s", name, got, tt.input)
+			}
+		}
+	}
+}
+
+func TestDiffStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		file FileDiff
+	}{
+		{
+			name: "crlf content",
+			file: FileDiff{OldPath: "win.txt", NewPath: "win.txt", Status: FileModified,
+				Hunks: []Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []Line{
+					{Kind: LineDeleted, Content: "old\r", OldNumber: 1},
+					{Kind: LineAdded, Content: "new\r", NewNumber: 1},
+				}}}},
+		},
+		{
+			name: "ambiguous names with spaces",
+			file: FileDiff{OldPath: "a b/c", NewPath: "a b/c b/d", Status: FileModified, OldMode: "100644", NewMode: "100755"},
+		},
+		{
+			name: "added file without mode",
+			file: FileDiff{NewPath: "new.txt", Status: FileAdded},
+		},
+		{
+			name: "deleted file without mode",
+			file: FileDiff{OldPath: "old.txt", Status: FileDeleted},
+		},
+		{
+			name: "control characters in names",
+			file: FileDiff{OldPath: "tab\there", NewPath: "new\nline", Status: FileRenamed, Similarity: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &Diff{Files: []FileDiff{tt.file}}
+			got, err := ParseDiff(want.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("ParseDiff(%q) =\n%#v\nwant\n%#v", want.String(), got.Files, want.Files)
+			}
+		})
+	}
+}
+
+func TestParseDiffMissingPath(t *testing.T) {
+	if _, err := ParseDiff("diff --git \n@@ -1 +1 @@\n+x\n"); err == nil {
+		t.Fatal("ParseDiff accepted a file without a path")
+	}
+}
+
+func TestParseDiffInvalidHunkHeader(t *testing.T) {
+	for _, header := range []string{"@@ -a +1 @@", "@@ -1 +1,99999999999 @@"} {
+		input := "diff --git a/x b/x\n--- a/x\n+++ b/x\n" + header + "\n+y\n"
+		if _, err := ParseDiff(input); err == nil || !strings.Contains(err.Error(), "invalid hunk header") {
+			t.Errorf("ParseDiff(%q) error = %v, want invalid hunk header", header, err)
+		}
+	}
+}
+
+func FuzzParseDiff(f *testing.F) {
+	for _, tt := range parseDiffTests {
+		f.Add(tt.input)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		d, err := ParseDiff(input)
+		if err != nil {
+			return
+		}
+		text := d.String()
+		again, err := ParseDiff(text)
+		if err != nil {
+			t.Fatalf("ParseDiff(String()) failed: %v\n%s", err, text)
+		}
+		if !reflect.DeepEqual(again, d) {
+			t.Fatalf("ParseDiff(String()) differs\ninput:\n%q\nrendered:\n%q\ngot:\n%#v\nwant:\n%#v", input, text, again.Files, d.Files)
+		}
+	})
+}
+
+func TestLoadDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitCmd(t, dir, "init", "--quiet", "--initial-branch=main")
+	// Settings that would change the patch format must not reach the parser.
+	for _, kv := range [][2]string{
+		{"diff.noprefix", "true"}, {"diff.renames", "false"}, {"color.ui", "always"}, {"diff.external", "false"},
+	} {
+		gitCmd(t, dir, "config", kv[0], kv[1])
+	}
+	body := strings.Repeat("shared line\n", 20)
+	write("old.txt", body)
+	write("keep.txt", "one\n")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "--quiet", "-m", "initial")
+	gitCmd(t, dir, "mv", "old.txt", "new.txt")
+	write("keep.txt", "one\ntwo\n")
+	gitCmd(t, dir, "add", "keep.txt")
+	gitCmd(t, dir, "commit", "--quiet", "-m", "rename")
+	write("keep.txt", "one\ntwo\nthree\n")
+
+	check := func(name string, diff *Diff, err error, want map[string]FileStatus) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got := make(map[string]FileStatus)
+		for i := range diff.Files {
+			got[diff.Files[i].Path()] = diff.Files[i].Status
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: files = %v, want %v", name, got, want)
+		}
+	}
+
+	diff, err := LoadCommitDiff(ctx, dir, "HEAD")
+	check("LoadCommitDiff", diff, err, map[string]FileStatus{"new.txt": FileRenamed, "keep.txt": FileModified})
+	if f := diff.File("new.txt"); f == nil || f.OldPath != "old.txt" {
+		t.Errorf("rename source = %+v, want old.txt", f)
+	}
+	diff, err = LoadCommitDiff(ctx, dir, "HEAD", "keep.txt")
+	check("LoadCommitDiff with paths", diff, err, map[string]FileStatus{"keep.txt": FileModified})
+	diff, err = LoadRangeDiff(ctx, dir, "HEAD~1..HEAD")
+	check("LoadRangeDiff", diff, err, map[string]FileStatus{"new.txt": FileRenamed, "keep.txt": FileModified})
+	diff, err = LoadWorkingTreeDiff(ctx, dir)
+	check("LoadWorkingTreeDiff", diff, err, map[string]FileStatus{"keep.txt": FileModified})
+	diff, err = LoadStagedDiff(ctx, dir)
+	check("LoadStagedDiff", diff, err, map[string]FileStatus{})
+
+	if _, err := LoadRangeDiff(ctx, dir, "--output=/tmp/x"); err == nil {
+		t.Error("LoadRangeDiff accepted an option as revision")
+	}
+	if _, err := LoadCommitDiff(ctx, dir, "-p"); err == nil {
+		t.Error("LoadCommitDiff accepted an option as revision")
+	}
+}
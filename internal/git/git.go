@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // RepoInfo captures helpful metadata about the current repository.
@@ -23,8 +26,17 @@ var (
 
 // Run executes a git command within dir and returns combined stdout/stderr.
 func Run(ctx context.Context, dir string, args ...string) (string, error) {
+	return runEnv(ctx, dir, nil, args...)
+}
+
+// runEnv is Run with extra environment variables, e.g. LC_ALL=C for output
+// that git translates.
+func runEnv(ctx context.Context, dir string, env []string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -48,6 +60,15 @@ func EnsureRepository(ctx context.Context, dir string) error {
 	return nil
 }
 
+// TopLevel returns the absolute path of the repository root.
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	out, err := Run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", ErrNotRepository
+	}
+	return strings.TrimSpace(out), nil
+}
+
 // GetRepoInfo fetches repo metadata for AI context.
 func GetRepoInfo(ctx context.Context, dir string) (RepoInfo, error) {
 	var info RepoInfo
@@ -68,33 +89,6 @@ func GetRepoInfo(ctx context.Context, dir string) (RepoInfo, error) {
 	return info, nil
 }
 
-// GetStagedDiff returns the staged diff (git diff --cached).
-func GetStagedDiff(ctx context.Context, dir string) (string, error) {
-	if err := EnsureRepository(ctx, dir); err != nil {
-		return "", err
-	}
-	out, err := Run(ctx, dir, "diff", "--cached")
-	return out, err
-}
-
-// GetWorkingTreeDiff returns the unstaged diff (git diff).
-func GetWorkingTreeDiff(ctx context.Context, dir string) (string, error) {
-	if err := EnsureRepository(ctx, dir); err != nil {
-		return "", err
-	}
-	out, err := Run(ctx, dir, "diff")
-	return out, err
-}
-
-// GetLastCommitDiff returns the diff for the latest commit (git show HEAD).
-func GetLastCommitDiff(ctx context.Context, dir string) (string, error) {
-	if err := EnsureRepository(ctx, dir); err != nil {
-		return "", err
-	}
-	out, err := Run(ctx, dir, "show", "HEAD")
-	return out, err
-}
-
 // StatusPorcelain returns git status in porcelain format.
 func StatusPorcelain(ctx context.Context, dir string) (string, error) {
 	if err := EnsureRepository(ctx, dir); err != nil {
@@ -113,6 +107,15 @@ func AddAll(ctx context.Context, dir string) error {
 	return err
 }
 
+// AddPaths stages the given paths.
+func AddPaths(ctx context.Context, dir string, paths ...string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, append([]string{"add", "--"}, paths...)...)
+	return err
+}
+
 // Commit creates a new commit with the given message.
 func Commit(ctx context.Context, dir, message string) error {
 	if err := EnsureRepository(ctx, dir); err != nil {
@@ -149,23 +152,72 @@ func CheckoutBranch(ctx context.Context, dir, name string) error {
 	return err
 }
 
-// PushCurrentBranch pushes the current branch to origin, optionally setting upstream.
-func PushCurrentBranch(ctx context.Context, dir string, setUpstream bool) error {
-	branch, err := CurrentBranch(ctx, dir)
-	if err != nil {
+// PushOptions controls Push.
+type PushOptions struct {
+	// Remote defaults to "origin".
+	Remote string
+	// Branch is the local branch to push; empty means the current branch.
+	Branch string
+	// Dest is the branch on the remote; empty means the same name as Branch.
+	Dest        string
+	SetUpstream bool
+	// ForceWithLease overwrites Dest only if it still points at Expect
+	// (a commit hash; empty means Dest must not exist yet).
+	ForceWithLease bool
+	Expect         string
+}
+
+// Push pushes a local branch to a remote branch.
+func Push(ctx context.Context, dir string, opts PushOptions) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
 		return err
 	}
-	if branch == "" {
-		return errors.New("could not determine current branch name")
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+	if opts.Branch == "" {
+		branch, err := CurrentBranch(ctx, dir)
+		if err != nil {
+			return err
+		}
+		if branch == "" || branch == "HEAD" {
+			return errors.New("could not determine current branch name")
+		}
+		opts.Branch = branch
+	}
+	if opts.Dest == "" {
+		opts.Dest = opts.Branch
 	}
 
 	args := []string{"push"}
-	if setUpstream {
+	if opts.SetUpstream {
 		args = append(args, "-u")
 	}
-	args = append(args, "origin", branch)
+	if opts.ForceWithLease {
+		args = append(args, fmt.Sprintf("--force-with-lease=refs/heads/%s:%s", opts.Dest, opts.Expect))
+	}
+	args = append(args, opts.Remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", opts.Branch, opts.Dest))
+
+	_, err := Run(ctx, dir, args...)
+	return err
+}
 
-	_, err = Run(ctx, dir, args...)
+// CreateAnnotatedTag creates an annotated tag at HEAD.
+func CreateAnnotatedTag(ctx context.Context, dir, name, message string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	// Keep Markdown headings, which the default cleanup strips as comments.
+	_, err := Run(ctx, dir, "tag", "-a", "--cleanup=whitespace", name, "-m", message)
+	return err
+}
+
+// PushRefs pushes refs (branches or tags) to remote.
+func PushRefs(ctx context.Context, dir, remote string, refs ...string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, append([]string{"push", remote}, refs...)...)
 	return err
 }
 
@@ -188,6 +240,182 @@ func PullRebase(ctx context.Context, dir, remote, branch string) error {
 	return err
 }
 
+// BranchExists reports whether a local branch called name exists.
+func BranchExists(ctx context.Context, dir, name string) bool {
+	_, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// RemoteBranchExists asks remote whether it has a branch called name.
+func RemoteBranchExists(ctx context.Context, dir, remote, name string) (bool, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return false, err
+	}
+	_, err := Run(ctx, dir, "ls-remote", "--exit-code", "--heads", remote, "refs/heads/"+name)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		// --exit-code reports "no matching refs" as status 2.
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CheckoutRemoteBranch fetches name from remote and checks it out as a new
+// local branch tracking remote/name.
+func CheckoutRemoteBranch(ctx context.Context, dir, remote, name string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", name, remote, name)
+	if _, err := Run(ctx, dir, "fetch", remote, refspec); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "checkout", "-b", name, "--track", remote+"/"+name)
+	return err
+}
+
+// FetchBranch updates remote/name from remote and returns the commit it
+// points at, or "" when the remote has no such branch.
+func FetchBranch(ctx context.Context, dir, remote, name string) (string, error) {
+	exists, err := RemoteBranchExists(ctx, dir, remote, name)
+	if err != nil || !exists {
+		return "", err
+	}
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", name, remote, name)
+	if _, err := Run(ctx, dir, "fetch", remote, refspec); err != nil {
+		return "", err
+	}
+	out, err := Run(ctx, dir, "rev-parse", "--verify", fmt.Sprintf("refs/remotes/%s/%s", remote, name))
+	return strings.TrimSpace(out), err
+}
+
+// AheadBehind counts the commits only in a (ahead) and only in b (behind).
+func AheadBehind(ctx context.Context, dir, a, b string) (ahead, behind int, err error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return 0, 0, err
+	}
+	out, err := Run(ctx, dir, "rev-list", "--left-right", "--count", a+"..."+b)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscan(out, &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q: %w", out, err)
+	}
+	return ahead, behind, nil
+}
+
+// BranchRemote returns the remote configured for branch, or "" if none.
+func BranchRemote(ctx context.Context, dir, branch string) string {
+	out, err := Run(ctx, dir, "config", "--get", "branch."+branch+".remote")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// Rebase rebases the current branch onto upstream, stashing local
+// changes around it.
+func Rebase(ctx context.Context, dir, upstream string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "rebase", "--autostash", upstream)
+	return err
+}
+
+// StashPush stashes tracked and untracked changes under message and
+// returns the new stash commit, or "" when git found nothing to stash (for
+// example when only submodules changed).
+func StashPush(ctx context.Context, dir, message string) (string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	before := stashHead(ctx, dir)
+	if _, err := Run(ctx, dir, "stash", "push", "--include-untracked", "-m", message); err != nil {
+		return "", err
+	}
+	if after := stashHead(ctx, dir); after != before {
+		return after, nil
+	}
+	return "", nil
+}
+
+// stashHead returns the commit of the latest stash, or "" when there is none.
+func stashHead(ctx context.Context, dir string) string {
+	out, err := Run(ctx, dir, "rev-parse", "-q", "--verify", "refs/stash")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// StashSelector returns the stash@{n} name of the stash whose commit is
+// commit; entries shift whenever another stash is pushed or dropped.
+func StashSelector(ctx context.Context, dir, commit string) (string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	out, err := Run(ctx, dir, "stash", "list", "--format=%gd %H")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		selector, hash, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && hash == commit {
+			return selector, nil
+		}
+	}
+	return "", fmt.Errorf("stash %s not found", commit)
+}
+
+// StashPop re-applies the stash whose commit is commit and drops it. When
+// applying conflicts, git leaves the stash in place.
+func StashPop(ctx context.Context, dir, commit string) error {
+	selector, err := StashSelector(ctx, dir, commit)
+	if err != nil {
+		return err
+	}
+	_, err = Run(ctx, dir, "stash", "pop", selector)
+	return err
+}
+
+// UntrackedFiles lists untracked files that are not ignored.
+func UntrackedFiles(ctx context.Context, dir string) ([]string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, "-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// StagedFiles lists the paths staged for the next commit, relative to the
+// repository root.
+func StagedFiles(ctx context.Context, dir string) ([]string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, "-c", "core.quotePath=false", "diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
 // HasUpstream reports whether the current branch has an upstream configured.
 func HasUpstream(ctx context.Context, dir string) (bool, error) {
 	if err := EnsureRepository(ctx, dir); err != nil {
@@ -209,3 +437,363 @@ func LastCommitSubject(ctx context.Context, dir string) (string, error) {
 	out, err := Run(ctx, dir, "log", "-1", "--pretty=%s")
 	return strings.TrimSpace(out), err
 }
+
+// LogEntry describes a single commit as returned by CommitLog.
+type LogEntry struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body,omitempty"`
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (c LogEntry) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+	// commitLogFormat matches the field order parsed by parseCommitLog.
+	commitLogFormat = "--pretty=format:%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b%x1e"
+)
+
+// CommitLog returns the commits selected by the given git log arguments
+// (e.g. a revision range and/or "--" followed by paths), newest first.
+func CommitLog(ctx context.Context, dir string, args ...string) ([]LogEntry, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, append([]string{"log", commitLogFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(out), nil
+}
+
+func parseCommitLog(out string) []LogEntry {
+	var commits []LogEntry
+	for _, record := range strings.Split(out, recordSep) {
+		record = strings.TrimLeft(record, "\r\n")
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		fields := strings.SplitN(record, fieldSep, 6)
+		if len(fields) < 5 {
+			continue
+		}
+		commit := LogEntry{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Subject: fields[4],
+		}
+		if date, err := time.Parse(time.RFC3339, fields[3]); err == nil {
+			commit.Date = date
+		}
+		if len(fields) == 6 {
+			commit.Body = strings.TrimSpace(fields[5])
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// MergeBase returns the best common ancestor of a and b (git merge-base).
+func MergeBase(ctx context.Context, dir, a, b string) (string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	for _, rev := range []string{a, b} {
+		if err := CheckRevision(rev); err != nil {
+			return "", err
+		}
+	}
+	out, err := Run(ctx, dir, "merge-base", a, b)
+	return strings.TrimSpace(out), err
+}
+
+// DefaultBranch returns the name of the remote's default branch (from
+// refs/remotes/<remote>/HEAD), falling back to main or master when they exist
+// locally or on the remote. The name may only exist as a remote-tracking ref,
+// so use BaseRef before handing it to git as a revision.
+func DefaultBranch(ctx context.Context, dir, remote string) (string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	if out, err := Run(ctx, dir, "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD"); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(out), remote+"/"), nil
+	}
+	for _, name := range []string{"main", "master"} {
+		if _, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", name); err == nil {
+			return name, nil
+		}
+		if _, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", remote+"/"+name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the default branch of %s", remote)
+}
+
+// BaseRef returns the revision to compare against for branch on remote: the
+// remote-tracking ref when it exists, since the local copy of a base branch is
+// often stale or missing entirely, and otherwise branch itself.
+func BaseRef(ctx context.Context, dir, remote, branch string) string {
+	if remote != "" {
+		if _, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", remote+"/"+branch); err == nil {
+			return remote + "/" + branch
+		}
+	}
+	return branch
+}
+
+// LatestTag returns the most recent tag reachable from rev
+// (git describe --tags --abbrev=0). It returns "" when there is none.
+func LatestTag(ctx context.Context, dir, rev string) (string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	// The C locale keeps the "no tags" messages below untranslated.
+	out, err := runEnv(ctx, dir, []string{"LC_ALL=C"}, "describe", "--tags", "--abbrev=0", rev)
+	if err != nil {
+		if strings.Contains(err.Error(), "No names found") || strings.Contains(err.Error(), "No tags can describe") {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// IsTag reports whether name is an existing tag.
+func IsTag(ctx context.Context, dir, name string) bool {
+	_, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	return err == nil
+}
+
+// withPaths appends a "--" pathspec separator and paths when any are given.
+func withPaths(args, paths []string) []string {
+	if len(paths) == 0 {
+		return args
+	}
+	args = append(args, "--")
+	return append(args, paths...)
+}
+
+// CheckAttributes returns the values of attrs for each path, as reported by
+// git check-attr (e.g. "set", "unset", "unspecified" or a string value).
+func CheckAttributes(ctx context.Context, dir string, attrs, paths []string) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string, len(paths))
+	if len(paths) == 0 || len(attrs) == 0 {
+		return result, nil
+	}
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"check-attr", "-z"}, attrs...)
+	args = append(args, "--")
+	args = append(args, paths...)
+	out, err := Run(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// With -z the output is a flat list of path, attribute, value triples.
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		path, attr, value := fields[i], fields[i+1], fields[i+2]
+		if result[path] == nil {
+			result[path] = make(map[string]string, len(attrs))
+		}
+		result[path][attr] = value
+	}
+	return result, nil
+}
+
+// ShowFile returns the contents of path at rev (e.g. "HEAD", a commit SHA,
+// or ":" for the index), relative to the repository root.
+func ShowFile(ctx context.Context, dir, rev, path string) (string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	spec := rev + ":" + path
+	if rev == ":" {
+		spec = ":" + path
+	}
+	cmd := exec.CommandContext(ctx, "git", "show", spec)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git show %s failed: %w\n%s", spec, err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// Operation is a multi-step git operation that can stop on conflicts.
+type Operation string
+
+const (
+	OperationNone       Operation = ""
+	OperationRebase     Operation = "rebase"
+	OperationMerge      Operation = "merge"
+	OperationCherryPick Operation = "cherry-pick"
+	OperationRevert     Operation = "revert"
+)
+
+// InProgress reports which operation, if any, is waiting to be continued.
+func InProgress(ctx context.Context, dir string) (Operation, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return OperationNone, err
+	}
+	checks := []struct {
+		path string
+		op   Operation
+	}{
+		{"rebase-merge", OperationRebase},
+		{"rebase-apply", OperationRebase},
+		{"MERGE_HEAD", OperationMerge},
+		{"CHERRY_PICK_HEAD", OperationCherryPick},
+		{"REVERT_HEAD", OperationRevert},
+	}
+	for _, c := range checks {
+		path, err := GitPath(ctx, dir, c.path)
+		if err != nil {
+			return OperationNone, err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return c.op, nil
+		}
+	}
+	return OperationNone, nil
+}
+
+// ConflictedFiles lists unmerged paths relative to the repository root.
+func ConflictedFiles(ctx context.Context, dir string) ([]string, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, "-c", "core.quotePath=false", "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// RecreateConflict rewrites path with diff3-style conflict markers
+// (ours, base and theirs), discarding any edits made to it since.
+func RecreateConflict(ctx context.Context, dir, path string) error {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "checkout", "--conflict=diff3", "--", path)
+	return err
+}
+
+// ContinueOperation runs "git <op> --continue" without opening an editor.
+func ContinueOperation(ctx context.Context, dir string, op Operation) error {
+	if op == OperationNone {
+		return errors.New("no rebase, merge, cherry-pick or revert in progress")
+	}
+	cmd := exec.CommandContext(ctx, "git", string(op), "--continue")
+	cmd.Dir = dir
+	// Keep the prepared commit message instead of prompting for it.
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s --continue failed: %w\n%s", op, err, out)
+	}
+	return nil
+}
+
+// AbortOperation runs "git <op> --abort", restoring the pre-operation state.
+func AbortOperation(ctx context.Context, dir string, op Operation) error {
+	if op == OperationNone {
+		return errors.New("no rebase, merge, cherry-pick or revert in progress")
+	}
+	_, err := Run(ctx, dir, string(op), "--abort")
+	return err
+}
+
+// ReflogEntry is one entry of the HEAD reflog: HEAD moved to Hash at Time.
+type ReflogEntry struct {
+	Hash    string
+	Time    time.Time
+	Subject string
+}
+
+// Reflog returns up to n HEAD reflog entries, newest first.
+func Reflog(ctx context.Context, dir string, n int) ([]ReflogEntry, error) {
+	if err := EnsureRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+	out, err := Run(ctx, dir, "log", "--walk-reflogs", "-n", fmt.Sprint(n), "--date=iso-strict",
+		"--format=%H"+fieldSep+"%gd"+fieldSep+"%gs"+recordSep, "HEAD")
+	if err != nil {
+		// A repository without commits has no reflog yet.
+		return nil, nil
+	}
+	var entries []ReflogEntry
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.SplitN(strings.TrimLeft(record, "\r\n"), fieldSep, 3)
+		if len(fields) < 3 {
+			continue
+		}
+		e := ReflogEntry{Hash: fields[0], Subject: strings.TrimSpace(fields[2])}
+		// %gd is "HEAD@{<date>}" when --date is given.
+		if i := strings.Index(fields[1], "@{"); i >= 0 {
+			e.Time, _ = time.Parse(time.RFC3339, strings.TrimSuffix(fields[1][i+2:], "}"))
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// GitPath resolves a path inside the repository's git directory, e.g.
+// "ORIG_HEAD" or "smartgit/undo.jsonl".
+func GitPath(ctx context.Context, dir, name string) (string, error) {
+	out, err := Run(ctx, dir, "rev-parse", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(out)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// CheckRevision rejects a user-supplied revision or range ("a..b",
+// "a...b") that git would parse as an option, such as "--output=x".
+func CheckRevision(rev string) error {
+	for _, end := range strings.SplitN(strings.Replace(rev, "...", "..", 1), "..", 2) {
+		if strings.HasPrefix(strings.TrimSpace(end), "-") {
+			return fmt.Errorf("invalid revision %q: revisions must not start with '-'", rev)
+		}
+	}
+	return nil
+}
+
+// RevParse resolves rev to a full commit hash.
+func RevParse(ctx context.Context, dir, rev string) (string, error) {
+	if err := CheckRevision(rev); err != nil {
+		return "", err
+	}
+	out, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return strings.TrimSpace(out), nil
+}
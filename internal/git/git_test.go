@@ -0,0 +1,193 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckRevision(t *testing.T) {
+	tests := []struct {
+		rev     string
+		wantErr bool
+	}{
+		{"HEAD", false},
+		{"main", false},
+		{"1a2b3c4", false},
+		{"main..HEAD", false},
+		{"main...feature", false},
+		{"..HEAD", false},
+		{"HEAD~3..", false},
+		{"feature-x", false},
+		{"v1.0.0-rc.1", false},
+		{"-p", true},
+		{"--output=/tmp/x", true},
+		{"main..--output=/tmp/x", true},
+		{"--all..HEAD", true},
+		{"main...-n1", true},
+		{" -p", true},
+	}
+	for _, tt := range tests {
+		err := CheckRevision(tt.rev)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckRevision(%q) error = %v, wantErr %v", tt.rev, err, tt.wantErr)
+		}
+	}
+}
+
+// gitCmd runs git in dir and fails the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+		"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestDefaultBranchRemoteOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	root := t.TempDir()
+	upstream := filepath.Join(root, "upstream")
+	clone := filepath.Join(root, "clone")
+
+	gitCmd(t, root, "init", "--quiet", "--initial-branch=main", upstream)
+	gitCmd(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	gitCmd(t, root, "clone", "--quiet", upstream, clone)
+	// Leave only origin/main: a feature branch without a local main and
+	// without refs/remotes/origin/HEAD.
+	gitCmd(t, clone, "checkout", "--quiet", "-b", "feature")
+	gitCmd(t, clone, "branch", "--quiet", "-D", "main")
+	gitCmd(t, clone, "remote", "set-head", "origin", "--delete")
+	gitCmd(t, clone, "commit", "--quiet", "--allow-empty", "-m", "feature work")
+
+	base, err := DefaultBranch(ctx, clone, "origin")
+	if err != nil {
+		t.Fatalf("DefaultBranch: %v", err)
+	}
+	if base != "main" {
+		t.Fatalf("DefaultBranch = %q, want main", base)
+	}
+	ref := BaseRef(ctx, clone, "origin", base)
+	if ref != "origin/main" {
+		t.Fatalf("BaseRef = %q, want origin/main", ref)
+	}
+	if _, err := MergeBase(ctx, clone, ref, "HEAD"); err != nil {
+		t.Fatalf("MergeBase(%s, HEAD): %v", ref, err)
+	}
+	commits, err := CommitLog(ctx, clone, ref+"..HEAD")
+	if err != nil {
+		t.Fatalf("CommitLog: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "feature work" {
+		t.Fatalf("CommitLog = %+v, want the feature commit", commits)
+	}
+
+	if got := BaseRef(ctx, clone, "origin", "feature"); got != "feature" {
+		t.Errorf("BaseRef(feature) = %q, want the local branch", got)
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	// "No tags yet" must be recognized whatever the user's locale.
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	ctx := context.Background()
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "--quiet", "--initial-branch=main")
+	gitCmd(t, dir, "commit", "--quiet", "--allow-empty", "-m", "initial")
+
+	tag, err := LatestTag(ctx, dir, "HEAD")
+	if err != nil || tag != "" {
+		t.Fatalf("LatestTag without tags = %q, %v; want no tag", tag, err)
+	}
+
+	gitCmd(t, dir, "tag", "v1.0.0")
+	gitCmd(t, dir, "commit", "--quiet", "--allow-empty", "-m", "second")
+	if tag, err := LatestTag(ctx, dir, "HEAD"); err != nil || tag != "v1.0.0" {
+		t.Errorf("LatestTag = %q, %v; want v1.0.0", tag, err)
+	}
+	if _, err := LatestTag(ctx, dir, "no-such-rev"); err == nil {
+		t.Error("LatestTag accepted an unknown revision")
+	}
+}
+
+func TestStashPushPop(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "t"}, {"GIT_AUTHOR_EMAIL", "t@example.com"},
+		{"GIT_COMMITTER_NAME", "t"}, {"GIT_COMMITTER_EMAIL", "t@example.com"},
+		{"GIT_CONFIG_GLOBAL", "/dev/null"}, {"GIT_CONFIG_NOSYSTEM", "1"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitCmd(t, dir, "init", "--quiet", "--initial-branch=main")
+	write("a.txt", "one\n")
+	gitCmd(t, dir, "add", "a.txt")
+	gitCmd(t, dir, "commit", "--quiet", "-m", "initial")
+
+	// An older stash of the user's must never be popped by mistake.
+	write("a.txt", "users own work\n")
+	gitCmd(t, dir, "stash", "push", "--quiet", "-m", "user stash")
+
+	stash, err := StashPush(ctx, dir, "nothing here")
+	if err != nil || stash != "" {
+		t.Fatalf("StashPush on a clean tree = %q, %v; want no stash", stash, err)
+	}
+
+	write("a.txt", "sg work\n")
+	write("new.txt", "untracked\n")
+	stash, err = StashPush(ctx, dir, "sg stash")
+	if err != nil || stash == "" {
+		t.Fatalf("StashPush = %q, %v; want a stash", stash, err)
+	}
+
+	// Another stash pushed meanwhile moves ours to stash@{1}.
+	write("a.txt", "later work\n")
+	gitCmd(t, dir, "stash", "push", "--quiet", "-m", "later stash")
+	if selector, err := StashSelector(ctx, dir, stash); err != nil || selector != "stash@{1}" {
+		t.Fatalf("StashSelector = %q, %v; want stash@{1}", selector, err)
+	}
+
+	if err := StashPop(ctx, dir, stash); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "sg work\n" {
+		t.Errorf("a.txt = %q after popping, want the sg stash", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); err != nil {
+		t.Errorf("untracked file not restored: %v", err)
+	}
+	out, err := Run(ctx, dir, "stash", "list", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "later stash") || !strings.Contains(out, "user stash") || strings.Contains(out, "sg stash") {
+		t.Errorf("stash list after pop:\n%s", out)
+	}
+	if err := StashPop(ctx, dir, stash); err == nil {
+		t.Error("StashPop of a dropped stash succeeded")
+	}
+}
@@ -0,0 +1,433 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Key identifies a user-facing message in the catalog.
+type Key string
+
+const (
+	MsgNoChangesToCommit   Key = "commit.no_changes"
+	MsgProposedCommit      Key = "commit.proposed"
+	MsgPrivacyDetected     Key = "commit.privacy_detected"
+	MsgPrivacyRisk         Key = "commit.privacy_risk"
+	MsgPrivacyConfirm      Key = "commit.privacy_confirm"
+	MsgPrivacyAborted      Key = "commit.privacy_aborted"
+	MsgPrivacyNotSent      Key = "commit.privacy_not_sent"
+	MsgCreatingBranch      Key = "commit.creating_branch"
+	MsgCommitCreated       Key = "commit.created"
+	MsgNoChangesToReview   Key = "review.no_changes"
+	MsgOnlySkippedChanged  Key = "review.only_skipped"
+	MsgReviewHeader        Key = "review.header"
+	MsgNoFindings          Key = "review.no_findings"
+	MsgFindingsSuppressed  Key = "review.suppressed"
+	MsgAPIKeyMissing       Key = "apikey.missing"
+	MsgAPIKeyCreateAt      Key = "apikey.create_at"
+	MsgAPIKeyEnter         Key = "apikey.enter"
+	MsgProfileKeyMissing   Key = "apikey.profile_missing"
+	MsgProfileKeyEnter     Key = "apikey.profile_enter"
+	MsgProfileKeyOptional  Key = "apikey.profile_optional"
+	MsgDryRun              Key = "cmd.dry_run"
+	MsgCommandCancelled    Key = "cmd.cancelled"
+	MsgAboutToExecute      Key = "cmd.about_to_execute"
+	MsgHighRiskWarning     Key = "cmd.high_risk"
+	MsgHighRiskConfirm     Key = "cmd.high_risk_confirm"
+	MsgHighRiskCancelled   Key = "cmd.high_risk_cancelled"
+	MsgMediumRiskNote      Key = "cmd.medium_risk"
+	MsgRunning             Key = "cmd.running"
+	MsgCommandExitStatus   Key = "cmd.exit_status"
+	MsgCommandFailed       Key = "cmd.failed"
+	MsgProtectedBranch     Key = "push.protected"
+	MsgSuggestedBranch     Key = "push.suggested"
+	MsgCreateAndPush       Key = "push.create_confirm"
+	MsgPushAborted         Key = "push.aborted"
+	MsgPushed              Key = "push.pushed"
+	MsgPushedWithUpstream  Key = "push.pushed_upstream"
+	MsgOpenPullRequest     Key = "push.open_pr"
+	MsgPullRequestOpened   Key = "push.pr_opened"
+	MsgPullRequestFallback Key = "push.pr_fallback"
+	MsgSwitched            Key = "switch.switched"
+	MsgSwitchedNotRebased  Key = "switch.switched_not_rebased"
+	MsgSwitchedTracking    Key = "switch.switched_tracking"
+	MsgUpdateAvailable     Key = "version.update_available"
+	MsgUpdateConfirm       Key = "version.update_confirm"
+	MsgUpdateSkipped       Key = "version.update_skipped"
+	MsgUpdateWarning       Key = "version.update_warning"
+	MsgForceDiscard        Key = "push.force_discard"
+	MsgForceConfirm        Key = "push.force_confirm"
+	MsgForceAborted        Key = "push.force_aborted"
+	MsgBranchDiverged      Key = "push.diverged"
+	MsgBranchBehind        Key = "push.behind"
+	MsgRebaseBeforePush    Key = "push.rebase_confirm"
+	MsgDivergedForceHint   Key = "push.diverged_force_hint"
+	MsgRebaseDiverged      Key = "push.rebase_diverged_confirm"
+	MsgRebasedOnto         Key = "push.rebased"
+	MsgStashConfirm        Key = "switch.stash_confirm"
+	MsgResolveContinue     Key = "resolve.continue_confirm"
+	MsgResolveStage        Key = "resolve.stage_confirm"
+	MsgResolveChoice       Key = "resolve.choice"
+	MsgResolveChoiceManual Key = "resolve.choice_manual"
+	MsgUndoSelect          Key = "undo.select"
+	MsgPruneSelect         Key = "branches.prune_select"
+	MsgNoBranchesDeleted   Key = "branches.none_deleted"
+	MsgReleaseAborted      Key = "release.aborted"
+	MsgBisectVerdict       Key = "bisect.verdict"
+	MsgProceed             Key = "confirm.proceed"
+	MsgNothingChanged      Key = "confirm.nothing_changed"
+	MsgConfirmWord         Key = "confirm.word"
+)
+
+// catalogs holds UI translations by primary language subtag. Messages use
+// fmt verbs; missing entries fall back to English.
+var catalogs = map[string]map[Key]string{
+	"en": {
+		MsgNoChangesToCommit:   "There are no changes to commit.",
+		MsgProposedCommit:      "Proposed commit message:",
+		MsgPrivacyDetected:     "Potential sensitive/private information detected in this commit:",
+		MsgPrivacyRisk:         "Privacy risk level reported by AI: %s",
+		MsgPrivacyConfirm:      "Do you still want to proceed with staging and committing these changes? (y/N): ",
+		MsgPrivacyAborted:      "Commit aborted due to potential sensitive information.",
+		MsgPrivacyNotSent:      "%s (found locally in a file that was not sent to AI)",
+		MsgCreatingBranch:      "Creating and switching to branch: %s",
+		MsgCommitCreated:       "Commit created successfully.",
+		MsgNoChangesToReview:   "There are no changes to review.",
+		MsgOnlySkippedChanged:  "Only ignored, generated or binary files changed:",
+		MsgReviewHeader:        "AI Review:",
+		MsgNoFindings:          "No findings.",
+		MsgFindingsSuppressed:  "%d known finding(s) suppressed by the baseline.",
+		MsgAPIKeyMissing:       "Gemini API key is not configured.",
+		MsgAPIKeyCreateAt:      "You can create a Gemini API key at: %s",
+		MsgAPIKeyEnter:         "Enter your Gemini API key (it will be stored for future use): ",
+		MsgProfileKeyMissing:   "No API key is configured for profile %q.",
+		MsgProfileKeyEnter:     "Enter the API key for this profile (it will be stored for future use): ",
+		MsgProfileKeyOptional:  "Enter the API key for this profile (leave empty if the gateway needs none): ",
+		MsgDryRun:              "Dry run mode: no commands will be executed.",
+		MsgCommandCancelled:    "Cancelled. No command was executed.",
+		MsgAboutToExecute:      "About to execute: %s",
+		MsgHighRiskWarning:     "WARNING: this command is considered HIGH RISK.",
+		MsgHighRiskConfirm:     "Type %q to confirm execution, or press Enter to cancel: ",
+		MsgHighRiskCancelled:   "Cancelled due to high risk.",
+		MsgMediumRiskNote:      "Note: this command has MEDIUM RISK (it may change system state).",
+		MsgRunning:             "Running: %s",
+		MsgCommandExitStatus:   "Command exited with status code %d",
+		MsgCommandFailed:       "Error while executing command: %v",
+		MsgProtectedBranch:     "On protected branch '%s'.",
+		MsgSuggestedBranch:     "Suggested branch: %s",
+		MsgCreateAndPush:       "Create, switch, and push this branch to %s? (Y/n): ",
+		MsgPushAborted:         "Push aborted. No branch was created or pushed.",
+		MsgPushed:              "Pushed branch '%s' to %s.",
+		MsgPushedWithUpstream:  "Pushed branch '%s' to %s and set upstream tracking.",
+		MsgOpenPullRequest:     "Open a %s pull request from '%s' into '%s'? (y/N): ",
+		MsgPullRequestOpened:   "Opened pull request #%d: %s",
+		MsgPullRequestFallback: "Could not generate a description (%v); using the commit messages instead.",
+		MsgSwitched:            "Switched to '%s' and rebased on origin/%s.",
+		MsgSwitchedNotRebased:  "Switched to '%s', but it was not updated from origin/%s.",
+		MsgSwitchedTracking:    "Switched to '%s', tracking origin/%s.",
+		MsgUpdateAvailable:     "A new version is available: %s (current %s)",
+		MsgUpdateConfirm:       "Do you want to update now? (y/N): ",
+		MsgUpdateSkipped:       "Update skipped.",
+		MsgUpdateWarning:       "Warning: a new version of sg is available: %s (current %s). Run 'sg version' to update.",
+		MsgForceDiscard:        "Force-pushing will discard %d commit(s) on %s that are not in your branch:",
+		MsgForceConfirm:        "Overwrite them? (y/N): ",
+		MsgForceAborted:        "Push aborted.",
+		MsgBranchDiverged:      "Your branch and %s have diverged (%d local, %d remote commit(s)).",
+		MsgBranchBehind:        "Your branch is %d commit(s) behind %s.",
+		MsgRebaseBeforePush:    "Rebase onto %s before pushing? (Y/n): ",
+		MsgDivergedForceHint:   "If you amended or rebased commits that were already pushed, you probably want 'sg p --force', which overwrites %s only if nobody pushed to it since your last fetch.",
+		MsgRebaseDiverged:      "Rebase onto %s instead? (y/N): ",
+		MsgRebasedOnto:         "Rebased onto %s.",
+		MsgStashConfirm:        "You have uncommitted changes. Stash them, switch, and re-apply them on %s? (y/N): ",
+		MsgResolveContinue:     "All conflicts resolved. Continue the %s? [y]es / [n]o, leave it / [a]bort: ",
+		MsgResolveStage:        "No conflict markers left. Stage it? (y/N): ",
+		MsgResolveChoice:       "Accept [a], edit [e], take ours [o], take theirs [t], skip [s], quit [q]: ",
+		MsgResolveChoiceManual: "Edit [e], take ours [o], take theirs [t], skip [s], quit [q]: ",
+		MsgUndoSelect:          "Undo up to and including which operation? (number, Enter to cancel): ",
+		MsgPruneSelect:         "Delete which branches? (e.g. 1 3-5, 'all', or Enter for none): ",
+		MsgNoBranchesDeleted:   "No branches deleted.",
+		MsgReleaseAborted:      "Release aborted.",
+		MsgBisectVerdict:       "Does this commit show %q? [b]ad (yes) / [g]ood (no) / [s]kip / [q]uit: ",
+		MsgProceed:             "Proceed? (y/N): ",
+		MsgNothingChanged:      "Nothing changed.",
+		MsgConfirmWord:         "yes",
+	},
+	"vi": {
+		MsgNoChangesToCommit:   "Không có thay đổi nào để commit.",
+		MsgProposedCommit:      "Commit message đề xuất:",
+		MsgPrivacyDetected:     "Phát hiện thông tin nhạy cảm/riêng tư có thể bị lộ trong commit này:",
+		MsgPrivacyRisk:         "Mức độ rủi ro riêng tư do AI đánh giá: %s",
+		MsgPrivacyConfirm:      "Bạn vẫn muốn stage và commit các thay đổi này? (y/N): ",
+		MsgPrivacyAborted:      "Đã huỷ commit do có thể chứa thông tin nhạy cảm.",
+		MsgPrivacyNotSent:      "%s (phát hiện cục bộ trong tệp không được gửi cho AI)",
+		MsgCreatingBranch:      "Đang tạo và chuyển sang nhánh: %s",
+		MsgCommitCreated:       "Đã tạo commit thành công.",
+		MsgNoChangesToReview:   "Không có thay đổi nào để review.",
+		MsgOnlySkippedChanged:  "Chỉ có các file bị bỏ qua, được sinh tự động hoặc nhị phân thay đổi:",
+		MsgReviewHeader:        "AI Review:",
+		MsgNoFindings:          "Không có vấn đề nào.",
+		MsgFindingsSuppressed:  "Đã ẩn %d vấn đề đã biết theo baseline.",
+		MsgAPIKeyMissing:       "Chưa cấu hình Gemini API key.",
+		MsgAPIKeyCreateAt:      "Bạn có thể tạo Gemini API key tại: %s",
+		MsgAPIKeyEnter:         "Nhập Gemini API key (sẽ được lưu cho các lần sau): ",
+		MsgProfileKeyMissing:   "Chưa cấu hình API key cho profile %q.",
+		MsgProfileKeyEnter:     "Nhập API key cho profile này (sẽ được lưu cho các lần sau): ",
+		MsgProfileKeyOptional:  "Nhập API key cho profile này (để trống nếu gateway không cần): ",
+		MsgDryRun:              "Chế độ dry run: sẽ không chạy lệnh nào.",
+		MsgCommandCancelled:    "Đã huỷ. Không có lệnh nào được chạy.",
+		MsgAboutToExecute:      "Sắp chạy: %s",
+		MsgHighRiskWarning:     "CẢNH BÁO: lệnh này có RỦI RO CAO.",
+		MsgHighRiskConfirm:     "Gõ %q để xác nhận, hoặc nhấn Enter để huỷ: ",
+		MsgHighRiskCancelled:   "Đã huỷ do rủi ro cao.",
+		MsgMediumRiskNote:      "Lưu ý: lệnh này có RỦI RO TRUNG BÌNH (có thể thay đổi trạng thái hệ thống).",
+		MsgRunning:             "Đang chạy: %s",
+		MsgCommandExitStatus:   "Lệnh kết thúc với mã %d",
+		MsgCommandFailed:       "Lỗi khi chạy lệnh: %v",
+		MsgProtectedBranch:     "Đang ở nhánh được bảo vệ '%s'.",
+		MsgSuggestedBranch:     "Nhánh đề xuất: %s",
+		MsgCreateAndPush:       "Tạo, chuyển sang và push nhánh này lên %s? (Y/n): ",
+		MsgPushAborted:         "Đã huỷ push. Không có nhánh nào được tạo hoặc push.",
+		MsgPushed:              "Đã push nhánh '%s' lên %s.",
+		MsgPushedWithUpstream:  "Đã push nhánh '%s' lên %s và thiết lập upstream.",
+		MsgOpenPullRequest:     "Mở pull request trên %s từ '%s' vào '%s'? (y/N): ",
+		MsgPullRequestOpened:   "Đã mở pull request #%d: %s",
+		MsgPullRequestFallback: "Không tạo được mô tả (%v); dùng commit message thay thế.",
+		MsgSwitched:            "Đã chuyển sang '%s' và rebase theo origin/%s.",
+		MsgSwitchedNotRebased:  "Đã chuyển sang '%s' nhưng chưa cập nhật theo origin/%s.",
+		MsgSwitchedTracking:    "Đã chuyển sang '%s', theo dõi origin/%s.",
+		MsgUpdateAvailable:     "Đã có phiên bản mới: %s (hiện tại %s)",
+		MsgUpdateConfirm:       "Bạn có muốn cập nhật ngay không? (y/N): ",
+		MsgUpdateSkipped:       "Đã bỏ qua cập nhật.",
+		MsgUpdateWarning:       "Lưu ý: đã có phiên bản sg mới: %s (hiện tại %s). Chạy 'sg version' để cập nhật.",
+		MsgForceDiscard:        "Force push sẽ xoá %d commit trên %s không có trong nhánh của bạn:",
+		MsgForceConfirm:        "Ghi đè chúng? (y/N): ",
+		MsgForceAborted:        "Đã huỷ push.",
+		MsgBranchDiverged:      "Nhánh của bạn và %s đã tách nhau (%d commit cục bộ, %d commit trên remote).",
+		MsgBranchBehind:        "Nhánh của bạn chậm hơn %[2]s %[1]d commit.",
+		MsgRebaseBeforePush:    "Rebase lên %s trước khi push? (Y/n): ",
+		MsgDivergedForceHint:   "Nếu bạn đã amend hoặc rebase các commit đã push, có lẽ bạn cần 'sg p --force', lệnh này chỉ ghi đè %s khi chưa ai push lên đó kể từ lần fetch gần nhất của bạn.",
+		MsgRebaseDiverged:      "Rebase lên %s thay vào đó? (y/N): ",
+		MsgRebasedOnto:         "Đã rebase lên %s.",
+		MsgStashConfirm:        "Bạn có thay đổi chưa commit. Stash chúng, chuyển nhánh và áp dụng lại trên %s? (y/N): ",
+		MsgResolveContinue:     "Đã giải quyết mọi xung đột. Tiếp tục %s? [y] có / [n] không, để tạm / [a] huỷ bỏ: ",
+		MsgResolveStage:        "Không còn dấu xung đột. Stage tệp này? (y/N): ",
+		MsgResolveChoice:       "Chấp nhận [a], sửa [e], lấy bản của mình [o], lấy bản của họ [t], bỏ qua [s], thoát [q]: ",
+		MsgResolveChoiceManual: "Sửa [e], lấy bản của mình [o], lấy bản của họ [t], bỏ qua [s], thoát [q]: ",
+		MsgUndoSelect:          "Hoàn tác đến (và gồm cả) thao tác nào? (số thứ tự, Enter để huỷ): ",
+		MsgPruneSelect:         "Xoá những nhánh nào? (ví dụ 1 3-5, 'all', hoặc Enter để không xoá): ",
+		MsgNoBranchesDeleted:   "Không xoá nhánh nào.",
+		MsgReleaseAborted:      "Đã huỷ phát hành.",
+		MsgBisectVerdict:       "Commit này có lỗi %q không? [b] có lỗi (y) / [g] không lỗi (n) / [s] bỏ qua / [q] thoát: ",
+		MsgProceed:             "Tiếp tục? (y/N): ",
+		MsgNothingChanged:      "Không có gì thay đổi.",
+		MsgConfirmWord:         "yes",
+	},
+	"ja": {
+		MsgNoChangesToCommit:   "コミットする変更はありません。",
+		MsgProposedCommit:      "提案されたコミットメッセージ:",
+		MsgPrivacyDetected:     "このコミットに機密情報や個人情報が含まれている可能性があります:",
+		MsgPrivacyRisk:         "AI が判定したプライバシーリスク: %s",
+		MsgPrivacyConfirm:      "それでも変更をステージしてコミットしますか? (y/N): ",
+		MsgPrivacyAborted:      "機密情報の可能性があるため、コミットを中止しました。",
+		MsgPrivacyNotSent:      "%s (AI に送信されなかったファイル内でローカルに検出)",
+		MsgCreatingBranch:      "ブランチを作成して切り替えます: %s",
+		MsgCommitCreated:       "コミットを作成しました。",
+		MsgNoChangesToReview:   "レビューする変更はありません。",
+		MsgOnlySkippedChanged:  "変更されたのは除外・自動生成・バイナリのファイルのみです:",
+		MsgReviewHeader:        "AI レビュー:",
+		MsgNoFindings:          "指摘事項はありません。",
+		MsgFindingsSuppressed:  "ベースラインにより既知の指摘 %d 件を非表示にしました。",
+		MsgAPIKeyMissing:       "Gemini API キーが設定されていません。",
+		MsgAPIKeyCreateAt:      "Gemini API キーはこちらで作成できます: %s",
+		MsgAPIKeyEnter:         "Gemini API キーを入力してください (次回以降のために保存されます): ",
+		MsgProfileKeyMissing:   "プロファイル %q の API キーが設定されていません。",
+		MsgProfileKeyEnter:     "このプロファイルの API キーを入力してください (次回以降のために保存されます): ",
+		MsgProfileKeyOptional:  "このプロファイルの API キーを入力してください (ゲートウェイが不要なら空欄のまま): ",
+		MsgDryRun:              "ドライランモード: コマンドは実行されません。",
+		MsgCommandCancelled:    "キャンセルしました。コマンドは実行されていません。",
+		MsgAboutToExecute:      "実行するコマンド: %s",
+		MsgHighRiskWarning:     "警告: このコマンドは高リスクです。",
+		MsgHighRiskConfirm:     "実行するには %q と入力してください。Enter でキャンセルします: ",
+		MsgHighRiskCancelled:   "高リスクのためキャンセルしました。",
+		MsgMediumRiskNote:      "注意: このコマンドは中リスクです (システムの状態を変更する可能性があります)。",
+		MsgRunning:             "実行中: %s",
+		MsgCommandExitStatus:   "コマンドは終了コード %d で終了しました",
+		MsgCommandFailed:       "コマンドの実行中にエラーが発生しました: %v",
+		MsgProtectedBranch:     "保護されたブランチ '%s' にいます。",
+		MsgSuggestedBranch:     "提案するブランチ: %s",
+		MsgCreateAndPush:       "このブランチを作成して切り替え、%s にプッシュしますか? (Y/n): ",
+		MsgPushAborted:         "プッシュを中止しました。ブランチは作成もプッシュもされていません。",
+		MsgPushed:              "ブランチ '%s' を %s にプッシュしました。",
+		MsgPushedWithUpstream:  "ブランチ '%s' を %s にプッシュし、upstream を設定しました。",
+		MsgOpenPullRequest:     "%s で '%s' から '%s' へのプルリクエストを作成しますか? (y/N): ",
+		MsgPullRequestOpened:   "プルリクエスト #%d を作成しました: %s",
+		MsgPullRequestFallback: "説明を生成できませんでした (%v)。代わりにコミットメッセージを使います。",
+		MsgSwitched:            "'%s' に切り替え、origin/%s にリベースしました。",
+		MsgSwitchedNotRebased:  "'%s' に切り替えましたが、origin/%s からは更新していません。",
+		MsgSwitchedTracking:    "'%s' に切り替えました (origin/%s を追跡)。",
+		MsgUpdateAvailable:     "新しいバージョンがあります: %s (現在 %s)",
+		MsgUpdateConfirm:       "今すぐ更新しますか? (y/N): ",
+		MsgUpdateSkipped:       "更新をスキップしました。",
+		MsgUpdateWarning:       "お知らせ: sg の新しいバージョン %s があります (現在 %s)。'sg version' で更新できます。",
+		MsgForceDiscard:        "強制プッシュすると、ブランチに含まれていない %[2]s 上の %[1]d 件のコミットが失われます:",
+		MsgForceConfirm:        "上書きしますか? (y/N): ",
+		MsgForceAborted:        "プッシュを中止しました。",
+		MsgBranchDiverged:      "ブランチと %s が分岐しています (ローカル %d 件、リモート %d 件のコミット)。",
+		MsgBranchBehind:        "ブランチは %[2]s より %[1]d コミット遅れています。",
+		MsgRebaseBeforePush:    "プッシュする前に %s にリベースしますか? (Y/n): ",
+		MsgDivergedForceHint:   "プッシュ済みのコミットを amend またはリベースした場合は、'sg p --force' を使うのがおそらく正解です。最後の fetch 以降に誰も %s にプッシュしていない場合にのみ上書きします。",
+		MsgRebaseDiverged:      "代わりに %s にリベースしますか? (y/N): ",
+		MsgRebasedOnto:         "%s にリベースしました。",
+		MsgStashConfirm:        "コミットされていない変更があります。stash して切り替え、%s で再適用しますか? (y/N): ",
+		MsgResolveContinue:     "すべての競合を解決しました。%s を続行しますか? [y] はい / [n] いいえ (保留) / [a] 中止: ",
+		MsgResolveStage:        "競合マーカーは残っていません。ステージしますか? (y/N): ",
+		MsgResolveChoice:       "採用 [a]、編集 [e]、自分側 [o]、相手側 [t]、スキップ [s]、終了 [q]: ",
+		MsgResolveChoiceManual: "編集 [e]、自分側 [o]、相手側 [t]、スキップ [s]、終了 [q]: ",
+		MsgUndoSelect:          "どの操作まで (その操作を含めて) 取り消しますか? (番号、Enter でキャンセル): ",
+		MsgPruneSelect:         "どのブランチを削除しますか? (例: 1 3-5、'all'、Enter で削除しない): ",
+		MsgNoBranchesDeleted:   "ブランチは削除されませんでした。",
+		MsgReleaseAborted:      "リリースを中止しました。",
+		MsgBisectVerdict:       "このコミットで %q が発生しますか? [b] 発生する (y) / [g] 発生しない (n) / [s] スキップ / [q] 終了: ",
+		MsgProceed:             "続行しますか? (y/N): ",
+		MsgNothingChanged:      "何も変更されていません。",
+		MsgConfirmWord:         "はい",
+	},
+	"de": {
+		MsgNoChangesToCommit:   "Es gibt keine Änderungen zum Committen.",
+		MsgProposedCommit:      "Vorgeschlagene Commit-Nachricht:",
+		MsgPrivacyDetected:     "In diesem Commit wurden möglicherweise sensible oder private Informationen gefunden:",
+		MsgPrivacyRisk:         "Von der KI gemeldetes Datenschutzrisiko: %s",
+		MsgPrivacyConfirm:      "Möchtest du diese Änderungen trotzdem stagen und committen? (j/N): ",
+		MsgPrivacyAborted:      "Commit wegen möglicher sensibler Informationen abgebrochen.",
+		MsgPrivacyNotSent:      "%s (lokal in einer Datei gefunden, die nicht an die KI gesendet wurde)",
+		MsgCreatingBranch:      "Branch wird erstellt und ausgecheckt: %s",
+		MsgCommitCreated:       "Commit erfolgreich erstellt.",
+		MsgNoChangesToReview:   "Es gibt keine Änderungen zum Reviewen.",
+		MsgOnlySkippedChanged:  "Es wurden nur ignorierte, generierte oder binäre Dateien geändert:",
+		MsgReviewHeader:        "KI-Review:",
+		MsgNoFindings:          "Keine Befunde.",
+		MsgFindingsSuppressed:  "%d bekannte Befunde durch die Baseline unterdrückt.",
+		MsgAPIKeyMissing:       "Es ist kein Gemini-API-Schlüssel konfiguriert.",
+		MsgAPIKeyCreateAt:      "Einen Gemini-API-Schlüssel kannst du hier erstellen: %s",
+		MsgAPIKeyEnter:         "Gemini-API-Schlüssel eingeben (wird für später gespeichert): ",
+		MsgProfileKeyMissing:   "Für das Profil %q ist kein API-Schlüssel konfiguriert.",
+		MsgProfileKeyEnter:     "API-Schlüssel für dieses Profil eingeben (wird für später gespeichert): ",
+		MsgProfileKeyOptional:  "API-Schlüssel für dieses Profil eingeben (leer lassen, wenn das Gateway keinen braucht): ",
+		MsgDryRun:              "Probelauf: Es werden keine Befehle ausgeführt.",
+		MsgCommandCancelled:    "Abgebrochen. Es wurde kein Befehl ausgeführt.",
+		MsgAboutToExecute:      "Folgender Befehl wird ausgeführt: %s",
+		MsgHighRiskWarning:     "WARNUNG: Dieser Befehl gilt als HOHES RISIKO.",
+		MsgHighRiskConfirm:     "Gib %q ein, um die Ausführung zu bestätigen, oder drücke Enter zum Abbrechen: ",
+		MsgHighRiskCancelled:   "Wegen hohen Risikos abgebrochen.",
+		MsgMediumRiskNote:      "Hinweis: Dieser Befehl hat ein MITTLERES RISIKO (er kann den Systemzustand ändern).",
+		MsgRunning:             "Ausführung: %s",
+		MsgCommandExitStatus:   "Befehl beendet mit Statuscode %d",
+		MsgCommandFailed:       "Fehler beim Ausführen des Befehls: %v",
+		MsgProtectedBranch:     "Du bist auf dem geschützten Branch '%s'.",
+		MsgSuggestedBranch:     "Vorgeschlagener Branch: %s",
+		MsgCreateAndPush:       "Diesen Branch erstellen, auschecken und nach %s pushen? (J/n): ",
+		MsgPushAborted:         "Push abgebrochen. Es wurde kein Branch erstellt oder gepusht.",
+		MsgPushed:              "Branch '%s' nach %s gepusht.",
+		MsgPushedWithUpstream:  "Branch '%s' nach %s gepusht und Upstream-Tracking gesetzt.",
+		MsgOpenPullRequest:     "Einen %s-Pull-Request von '%s' nach '%s' öffnen? (j/N): ",
+		MsgPullRequestOpened:   "Pull-Request #%d geöffnet: %s",
+		MsgPullRequestFallback: "Beschreibung konnte nicht erstellt werden (%v); stattdessen werden die Commit-Nachrichten verwendet.",
+		MsgSwitched:            "Zu '%s' gewechselt und auf origin/%s rebased.",
+		MsgSwitchedNotRebased:  "Zu '%s' gewechselt, aber nicht von origin/%s aktualisiert.",
+		MsgSwitchedTracking:    "Zu '%s' gewechselt, folgt origin/%s.",
+		MsgUpdateAvailable:     "Eine neue Version ist verfügbar: %s (aktuell %s)",
+		MsgUpdateConfirm:       "Jetzt aktualisieren? (j/N): ",
+		MsgUpdateSkipped:       "Aktualisierung übersprungen.",
+		MsgUpdateWarning:       "Hinweis: Eine neue Version von sg ist verfügbar: %s (aktuell %s). Aktualisiere mit 'sg version'.",
+		MsgForceDiscard:        "Der Force-Push verwirft %d Commit(s) auf %s, die nicht in deinem Branch sind:",
+		MsgForceConfirm:        "Überschreiben? (j/N): ",
+		MsgForceAborted:        "Push abgebrochen.",
+		MsgBranchDiverged:      "Dein Branch und %s sind auseinandergelaufen (%d lokale, %d entfernte Commit(s)).",
+		MsgBranchBehind:        "Dein Branch liegt %[1]d Commit(s) hinter %[2]s.",
+		MsgRebaseBeforePush:    "Vor dem Push auf %s rebasen? (J/n): ",
+		MsgDivergedForceHint:   "Wenn du bereits gepushte Commits geändert oder rebased hast, willst du wahrscheinlich 'sg p --force'; es überschreibt %s nur, wenn seit deinem letzten Fetch niemand dorthin gepusht hat.",
+		MsgRebaseDiverged:      "Stattdessen auf %s rebasen? (j/N): ",
+		MsgRebasedOnto:         "Auf %s rebased.",
+		MsgStashConfirm:        "Du hast nicht committete Änderungen. Stashen, wechseln und auf %s wieder anwenden? (j/N): ",
+		MsgResolveContinue:     "Alle Konflikte gelöst. %s fortsetzen? [j]a / [n]ein, pausieren / [a]bbrechen: ",
+		MsgResolveStage:        "Keine Konfliktmarker mehr. Stagen? (j/N): ",
+		MsgResolveChoice:       "Übernehmen [a], bearbeiten [e], unsere [o], ihre [t], überspringen [s], beenden [q]: ",
+		MsgResolveChoiceManual: "Bearbeiten [e], unsere [o], ihre [t], überspringen [s], beenden [q]: ",
+		MsgUndoSelect:          "Bis einschließlich welcher Operation rückgängig machen? (Nummer, Enter zum Abbrechen): ",
+		MsgPruneSelect:         "Welche Branches löschen? (z. B. 1 3-5, 'all' oder Enter für keine): ",
+		MsgNoBranchesDeleted:   "Keine Branches gelöscht.",
+		MsgReleaseAborted:      "Release abgebrochen.",
+		MsgBisectVerdict:       "Zeigt dieser Commit %q? [b] schlecht (j) / [g] gut (n) / [s] überspringen / [q] beenden: ",
+		MsgProceed:             "Fortfahren? (j/N): ",
+		MsgNothingChanged:      "Nichts geändert.",
+		MsgConfirmWord:         "ja",
+	},
+}
+
+// yesAnswers lists the accepted affirmative answers per language, on top of
+// "y" and "yes" which are always accepted.
+var yesAnswers = map[string][]string{
+	"de": {"j", "ja"},
+	"ja": {"はい"},
+	"vi": {"c", "có", "co"},
+}
+
+var (
+	mu      sync.RWMutex
+	current = English
+)
+
+// SetLanguage selects the language used by T.
+func SetLanguage(l Language) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = l
+}
+
+// Current returns the language selected with SetLanguage.
+func Current() Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the message for key in the current language, formatted with args.
+func T(key Key, args ...any) string {
+	format := lookup(Current().Base, key)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func lookup(base string, key Key) string {
+	if msg, ok := catalogs[base][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs["en"][key]; ok {
+		return msg
+	}
+	return string(key)
+}
+
+// IsYes reports whether answer confirms a y/N prompt in the current language.
+func IsYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "y" || answer == "yes" {
+		return true
+	}
+	for _, yes := range yesAnswers[Current().Base] {
+		if answer == yes {
+			return true
+		}
+	}
+	return false
+}
+
+// IsConfirmWord reports whether answer is the word required to confirm a
+// high-risk action, in the current language or in English.
+func IsConfirmWord(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "yes" || answer == strings.ToLower(T(MsgConfirmWord))
+}
@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+)
+
+var verbRe = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*\d*(?:\.\d+)?[a-zA-Z%]`)
+
+// verbs returns the sorted fmt verbs of msg, ignoring explicit argument
+// indexes so translations may reorder arguments.
+func verbs(msg string) []string {
+	found := verbRe.FindAllString(msg, -1)
+	out := make([]string, 0, len(found))
+	for _, v := range found {
+		out = append(out, regexp.MustCompile(`\[\d+\]`).ReplaceAllString(v, ""))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	english := catalogs["en"]
+	for lang, catalog := range catalogs {
+		for key, msg := range english {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing %s", lang, key)
+				continue
+			}
+			if got, want := verbs(translated), verbs(msg); !equal(got, want) {
+				t.Errorf("%s: %s has verbs %v, want %v", lang, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%s: %s is not in the English catalog", lang, key)
+			}
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIsYes(t *testing.T) {
+	defer SetLanguage(Current())
+
+	tests := []struct {
+		lang   string
+		answer string
+		want   bool
+	}{
+		{"en", "y", true},
+		{"en", " Yes\n", true},
+		{"en", "", false},
+		{"en", "n", false},
+		{"en", "ja", false},
+		{"de", "j", true},
+		{"de", "Ja\n", true},
+		{"de", "y", true},
+		{"de", "nein", false},
+		{"ja", "はい", true},
+		{"ja", "は", false},
+		{"ja", "いいえ", false},
+		{"vi", "c", true},
+		{"vi", "có", true},
+		{"vi", "không", false},
+	}
+	for _, tt := range tests {
+		lang, err := Parse(tt.lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		SetLanguage(lang)
+		if got := IsYes(tt.answer); got != tt.want {
+			t.Errorf("IsYes(%q) in %s = %v, want %v", tt.answer, tt.lang, got, tt.want)
+		}
+	}
+}
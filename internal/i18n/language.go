@@ -0,0 +1,173 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Language is a validated BCP-47 language tag.
+type Language struct {
+	// Tag is the canonical tag, e.g. "de", "ja-JP" or "zh-Hant-TW".
+	Tag string
+	// Base is the primary language subtag, e.g. "de".
+	Base string
+	// Name is the English name of the base language, used in AI prompts.
+	Name string
+}
+
+// English is the default language.
+var English = Language{Tag: "en", Base: "en", Name: "English"}
+
+// supported maps primary language subtags to their English names. Any
+// script or region is accepted on top of a supported base language.
+var supported = map[string]string{
+	"ar": "Arabic",
+	"cs": "Czech",
+	"da": "Danish",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"es": "Spanish",
+	"fi": "Finnish",
+	"fr": "French",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"hu": "Hungarian",
+	"id": "Indonesian",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nb": "Norwegian Bokmål",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ro": "Romanian",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"th": "Thai",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"vi": "Vietnamese",
+	"zh": "Chinese",
+}
+
+// ErrUnsupported is returned for well-formed tags whose language is not supported.
+var ErrUnsupported = errors.New("unsupported language")
+
+// Supported returns the supported primary language subtags, sorted.
+func Supported() []string {
+	tags := make([]string, 0, len(supported))
+	for tag := range supported {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// IsEnglish reports whether l is any variant of English.
+func (l Language) IsEnglish() bool {
+	return l.Base == "en"
+}
+
+// Label describes l for prompts, e.g. "German (de-AT)".
+func (l Language) Label() string {
+	if l.Tag == l.Base {
+		return l.Name
+	}
+	return fmt.Sprintf("%s (%s)", l.Name, l.Tag)
+}
+
+// Parse validates a BCP-47 tag such as "ja", "de-AT" or "zh-Hant-TW" and
+// returns it in canonical case. POSIX locale names like "de_DE.UTF-8" are
+// accepted too.
+func Parse(tag string) (Language, error) {
+	raw := strings.TrimSpace(tag)
+	// Strip POSIX encoding and modifier suffixes: de_DE.UTF-8@euro.
+	if i := strings.IndexAny(raw, ".@"); i >= 0 {
+		raw = raw[:i]
+	}
+	raw = strings.ReplaceAll(raw, "_", "-")
+	if raw == "" {
+		return Language{}, fmt.Errorf("empty language tag")
+	}
+
+	parts := strings.Split(raw, "-")
+	base := strings.ToLower(parts[0])
+	if len(base) < 2 || len(base) > 3 || !isAlpha(base) {
+		return Language{}, fmt.Errorf("invalid language tag %q", tag)
+	}
+
+	canonical := []string{base}
+	for i, p := range parts[1:] {
+		switch {
+		case len(p) == 4 && isAlpha(p) && i == 0:
+			// Script, e.g. Hant.
+			canonical = append(canonical, strings.ToUpper(p[:1])+strings.ToLower(p[1:]))
+		case len(p) == 2 && isAlpha(p), len(p) == 3 && isDigit(p):
+			// Region, e.g. DE or 419.
+			canonical = append(canonical, strings.ToUpper(p))
+		case len(p) >= 5 && len(p) <= 8 && isAlnum(p), len(p) == 4 && isDigit(p[:1]) && isAlnum(p):
+			// Variant.
+			canonical = append(canonical, strings.ToLower(p))
+		default:
+			return Language{}, fmt.Errorf("invalid language tag %q", tag)
+		}
+	}
+
+	name, ok := supported[base]
+	if !ok {
+		return Language{}, fmt.Errorf("%w %q (supported: %s)", ErrUnsupported, tag, strings.Join(Supported(), ", "))
+	}
+	return Language{Tag: strings.Join(canonical, "-"), Base: base, Name: name}, nil
+}
+
+// FromEnv returns the language of the user's locale from LC_ALL,
+// LC_MESSAGES or LANG, reporting false when none names a supported language.
+func FromEnv() (Language, bool) {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		// The first non-empty variable decides, as in setlocale(3).
+		if value == "C" || value == "POSIX" || strings.HasPrefix(value, "C.") {
+			return English, false
+		}
+		lang, err := Parse(value)
+		if err != nil {
+			return English, false
+		}
+		return lang, true
+	}
+	return English, false
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for _, r := range s {
+		if !isAlpha(string(r)) && !isDigit(string(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag   string
+		want  Language
+		label string
+	}{
+		{"ja", Language{Tag: "ja", Base: "ja", Name: "Japanese"}, "Japanese"},
+		{" DE-at ", Language{Tag: "de-AT", Base: "de", Name: "German"}, "German (de-AT)"},
+		{"zh-hant-tw", Language{Tag: "zh-Hant-TW", Base: "zh", Name: "Chinese"}, "Chinese (zh-Hant-TW)"},
+		{"es-419", Language{Tag: "es-419", Base: "es", Name: "Spanish"}, "Spanish (es-419)"},
+		{"de_DE.UTF-8", Language{Tag: "de-DE", Base: "de", Name: "German"}, "German (de-DE)"},
+		{"de_DE.UTF-8@euro", Language{Tag: "de-DE", Base: "de", Name: "German"}, "German (de-DE)"},
+		{"de-CH-1996", Language{Tag: "de-CH-1996", Base: "de", Name: "German"}, "German (de-CH-1996)"},
+		{"en-GB-oxendict", Language{Tag: "en-GB-oxendict", Base: "en", Name: "English"}, "English (en-GB-oxendict)"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.tag)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.tag, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+		if got.Label() != tt.label {
+			t.Errorf("Parse(%q).Label() = %q, want %q", tt.tag, got.Label(), tt.label)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		tag         string
+		unsupported bool
+	}{
+		{"", false},
+		{"  ", false},
+		{"e", false},
+		{"engl", false},
+		{"e1", false},
+		{"de-", false},
+		{"de-Latn-Latn", false},
+		{"de-A", false},
+		{"de-toolongvariant", false},
+		{"ja-JP-!", false},
+		{"xx", true},
+		{"tlh-Latn", true},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.tag)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", tt.tag)
+			continue
+		}
+		if errors.Is(err, ErrUnsupported) != tt.unsupported {
+			t.Errorf("Parse(%q) error = %v, unsupported %v", tt.tag, err, tt.unsupported)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name              string
+		lcAll, msgs, lang string
+		want              string
+		ok                bool
+	}{
+		{"unset", "", "", "", "en", false},
+		{"lang", "", "", "vi_VN.UTF-8", "vi-VN", true},
+		{"lc_messages wins over lang", "", "ja_JP.UTF-8", "de_DE.UTF-8", "ja-JP", true},
+		{"lc_all wins", "fr_FR", "ja_JP.UTF-8", "de_DE.UTF-8", "fr-FR", true},
+		{"C locale", "C.UTF-8", "", "de_DE.UTF-8", "en", false},
+		{"unsupported", "", "", "xx_XX", "en", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.msgs)
+			t.Setenv("LANG", tt.lang)
+			got, ok := FromEnv()
+			if got.Tag != tt.want || ok != tt.ok {
+				t.Errorf("FromEnv() = %q, %v, want %q, %v", got.Tag, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
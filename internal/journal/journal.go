@@ -0,0 +1,162 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vinhtran/git-smart/internal/config"
+)
+
+const fileName = "journal.jsonl"
+
+// Action names what sg did.
+type Action string
+
+const (
+	ActionBranchCreate Action = "branch-create"
+	ActionBranchDelete Action = "branch-delete"
+	ActionCheckout     Action = "checkout"
+	ActionStage        Action = "stage"
+	ActionCommit       Action = "commit"
+	ActionTag          Action = "tag"
+	ActionPush         Action = "push"
+	ActionRebase       Action = "rebase"
+	ActionStash        Action = "stash"
+	ActionReset        Action = "reset"
+	ActionContinue     Action = "continue"
+	ActionAbort        Action = "abort"
+	ActionBisect       Action = "bisect"
+	ActionShell        Action = "shell"
+)
+
+// Outcome is how an action ended.
+type Outcome string
+
+const (
+	OutcomeOK        Outcome = "ok"
+	OutcomeFailed    Outcome = "failed"
+	OutcomeCancelled Outcome = "cancelled"
+)
+
+// Entry is one line of the journal. Only the fields that apply to the
+// action are set.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Repo is the repository root, or the working directory for shell
+	// commands run outside a repository.
+	Repo    string   `json:"repo"`
+	Command string   `json:"command"`
+	Action  Action   `json:"action"`
+	Branch  string   `json:"branch,omitempty"`
+	Files   []string `json:"files,omitempty"`
+	Commit  string   `json:"commit,omitempty"`
+	// Target is where a push went ("origin feat:feat") or what a rebase,
+	// reset or checkout moved to.
+	Target  string  `json:"target,omitempty"`
+	Shell   string  `json:"shell,omitempty"`
+	Outcome Outcome `json:"outcome"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Summary describes the entry in a few words for listings.
+func (e Entry) Summary() string {
+	var parts []string
+	if e.Shell != "" {
+		parts = append(parts, "$ "+e.Shell)
+	}
+	if e.Branch != "" {
+		parts = append(parts, e.Branch)
+	}
+	if e.Commit != "" {
+		c := e.Commit
+		if len(c) > 7 {
+			c = c[:7]
+		}
+		parts = append(parts, c)
+	}
+	if e.Target != "" {
+		parts = append(parts, "-> "+e.Target)
+	}
+	switch len(e.Files) {
+	case 0:
+	case 1:
+		parts = append(parts, e.Files[0])
+	default:
+		parts = append(parts, fmt.Sprintf("%d files", len(e.Files)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Path returns the location of the journal file.
+func Path() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Append adds e to the journal, stamping it with the current time if unset.
+func Append(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Outcome == "" {
+		e.Outcome = OutcomeOK
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	// Lines are written with a single append so concurrent sg processes do
+	// not interleave them.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Read returns every journal entry, oldest first. Lines that cannot be
+// parsed are skipped.
+func Read() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,120 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+
+	entries, err := Read()
+	if err != nil || entries != nil {
+		t.Fatalf("Read without a journal = %v, %v; want nothing", entries, err)
+	}
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := Append(Entry{Time: at, Repo: "/src/app", Command: "sg cm", Action: ActionCommit, Commit: "abc1234def"}); err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now()
+	if err := Append(Entry{Repo: "/src/app", Command: "sg p", Action: ActionPush, Outcome: OutcomeFailed, Error: "rejected"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(path, state) {
+		t.Errorf("Path() = %q, want it under XDG_STATE_HOME %q", path, state)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("journal permissions = %o, want 600", perm)
+	}
+
+	entries, err = Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Read returned %d entries, want 2", len(entries))
+	}
+	first, second := entries[0], entries[1]
+	if !first.Time.Equal(at) || first.Outcome != OutcomeOK || first.Commit != "abc1234def" {
+		t.Errorf("first entry = %+v, want the given time, commit and outcome ok", first)
+	}
+	if second.Time.Before(before.Add(-time.Second)) || second.Outcome != OutcomeFailed || second.Error != "rejected" {
+		t.Errorf("second entry = %+v, want a current time and the failure", second)
+	}
+}
+
+func TestReadSkipsCorruptLines(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if err := Append(Entry{Command: "sg cm", Action: ActionCommit}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A line cut short by a crash, an empty line and a foreign line.
+	if _, err := f.WriteString(`{"time":"2024-05-01T12:00:00Z","command":"sg p","act` + "\n\nnot json\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := Append(Entry{Command: "sg p", Action: ActionPush}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Action != ActionCommit || entries[1].Action != ActionPush {
+		t.Errorf("Read = %+v, want the commit and the push only", entries)
+	}
+}
+
+func TestAppendCreatesStateDir(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "nested", "state")
+	t.Setenv("XDG_STATE_HOME", state)
+	if err := Append(Entry{Command: "sg cm", Action: ActionStage}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := Read(); err != nil || len(entries) != 1 {
+		t.Errorf("Read = %v, %v; want one entry", entries, err)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  string
+	}{
+		{"empty", Entry{}, ""},
+		{"commit", Entry{Branch: "main", Commit: "0123456789abcdef", Files: []string{"a.go", "b.go"}}, "main 0123456 2 files"},
+		{"short commit", Entry{Commit: "abc"}, "abc"},
+		{"push", Entry{Branch: "feat", Target: "origin feat:feat"}, "feat -> origin feat:feat"},
+		{"one file", Entry{Files: []string{"README.md"}}, "README.md"},
+		{"shell", Entry{Shell: "git gc", Branch: "main"}, "$ git gc main"},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.Summary(); got != tt.want {
+			t.Errorf("%s: Summary() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
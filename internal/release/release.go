@@ -0,0 +1,197 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/changelog"
+	"github.com/vinhtran/git-smart/internal/conventional"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/semver"
+)
+
+// ConfigFileName is the per-repository release configuration.
+const ConfigFileName = ".smartgit/release.json"
+
+// versionPlaceholder marks where the version appears in a FileSpec pattern.
+const versionPlaceholder = "{version}"
+
+// versionRegexp matches a semantic version without prefix.
+const versionRegexp = `(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?)`
+
+// FileSpec names files holding the version.
+type FileSpec struct {
+	// Path is relative to the repository root and may be a glob.
+	Path string `json:"path"`
+	// Pattern is the text around the version, with {version} marking it,
+	// e.g. `const Current = "{version}"`. Empty means the whole file is the
+	// version (like VERSION).
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Config is read from .smartgit/release.json.
+type Config struct {
+	Files []FileSpec `json:"files,omitempty"`
+	// TagPrefix is put before the version in tag names (default "v").
+	TagPrefix *string `json:"tag_prefix,omitempty"`
+}
+
+// DefaultFiles are used when the config lists none: a VERSION file and Go
+// constants named Current in a version package.
+var DefaultFiles = []FileSpec{
+	{Path: "VERSION"},
+	{Path: "internal/version/*.go", Pattern: `Current = "{version}"`},
+	{Path: "version/*.go", Pattern: `Current = "{version}"`},
+}
+
+// LoadConfig reads the release config under root, returning defaults when
+// the file does not exist.
+func LoadConfig(root string) (Config, bool, error) {
+	var cfg Config
+	data, err := os.ReadFile(filepath.Join(root, ConfigFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, false, nil
+	}
+	if err != nil {
+		return cfg, false, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, false, fmt.Errorf("failed to parse %s: %w", ConfigFileName, err)
+	}
+	return cfg, true, nil
+}
+
+// Prefix returns the tag prefix, "v" by default.
+func (c Config) Prefix() string {
+	if c.TagPrefix == nil {
+		return "v"
+	}
+	return *c.TagPrefix
+}
+
+// NextBump derives the increment from commits: breaking changes bump the
+// major version, features the minor version, anything else the patch.
+func NextBump(commits []git.LogEntry) semver.Bump {
+	bump := semver.BumpNone
+	for _, c := range commits {
+		if strings.HasPrefix(c.Subject, "Merge ") {
+			continue
+		}
+		if changelog.IsBreaking(c) {
+			return semver.BumpMajor
+		}
+		header, ok := conventional.ParseHeader(c.Subject)
+		if ok && header.Type == "feat" {
+			bump = semver.BumpMinor
+		} else if bump == semver.BumpNone {
+			bump = semver.BumpPatch
+		}
+	}
+	return bump
+}
+
+// Change is a pending edit of one version file.
+type Change struct {
+	Path    string
+	Old     string
+	Content string
+}
+
+// PlanFiles computes the edits that set version in the configured files.
+// Globs that match nothing are skipped for the defaults but are errors
+// when explicitly configured, as are patterns that are not found.
+func PlanFiles(root string, specs []FileSpec, explicit bool, version semver.Version) ([]Change, error) {
+	var changes []Change
+	for _, spec := range specs {
+		paths, err := filepath.Glob(filepath.Join(root, spec.Path))
+		if err != nil {
+			return nil, fmt.Errorf("invalid release file pattern %q: %w", spec.Path, err)
+		}
+		if len(paths) == 0 && explicit {
+			return nil, fmt.Errorf("release file %q not found", spec.Path)
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			updated, found, err := replaceVersion(string(data), spec.Pattern, version.Core()+preSuffix(version))
+			if err != nil {
+				return nil, err
+			}
+			rel, _ := filepath.Rel(root, path)
+			if !found {
+				if explicit || spec.Pattern == "" {
+					return nil, fmt.Errorf("no version matching %q found in %s", spec.Pattern, rel)
+				}
+				continue
+			}
+			if updated != string(data) {
+				changes = append(changes, Change{Path: rel, Old: string(data), Content: updated})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// CurrentFromFiles returns the version in the first configured file that
+// holds one, for repositories without tags.
+func CurrentFromFiles(root string, specs []FileSpec) (semver.Version, bool) {
+	for _, spec := range specs {
+		paths, _ := filepath.Glob(filepath.Join(root, spec.Path))
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			re, err := patternRegexp(spec.Pattern)
+			if err != nil {
+				continue
+			}
+			if m := re.FindStringSubmatch(string(data)); m != nil {
+				if v, err := semver.Parse(m[1]); err == nil {
+					return v, true
+				}
+			}
+		}
+	}
+	return semver.Version{}, false
+}
+
+func preSuffix(v semver.Version) string {
+	if v.Pre == "" {
+		return ""
+	}
+	return "-" + v.Pre
+}
+
+// patternRegexp turns a FileSpec pattern into a regexp whose first group is
+// the version.
+func patternRegexp(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return regexp.MustCompile(`^\s*v?` + versionRegexp + `\s*$`), nil
+	}
+	if strings.Count(pattern, versionPlaceholder) != 1 {
+		return nil, fmt.Errorf("release pattern %q must contain %s exactly once", pattern, versionPlaceholder)
+	}
+	before, after, _ := strings.Cut(pattern, versionPlaceholder)
+	return regexp.Compile(regexp.QuoteMeta(before) + versionRegexp + regexp.QuoteMeta(after))
+}
+
+// replaceVersion substitutes the first version matched by pattern.
+func replaceVersion(content, pattern, version string) (string, bool, error) {
+	re, err := patternRegexp(pattern)
+	if err != nil {
+		return "", false, err
+	}
+	loc := re.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return content, false, nil
+	}
+	return content[:loc[2]] + version + content[loc[3]:], true, nil
+}
@@ -0,0 +1,250 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/semver"
+	"github.com/vinhtran/git-smart/internal/version"
+)
+
+func TestNextBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		commits []git.LogEntry
+		want    semver.Bump
+	}{
+		{"no commits", nil, semver.BumpNone},
+		{"merges only", []git.LogEntry{
+			{Subject: "Merge branch 'feature'"},
+			{Subject: "Merge pull request #4 from x/feat!: y"},
+		}, semver.BumpNone},
+		{"fix", []git.LogEntry{{Subject: "fix: handle nil"}}, semver.BumpPatch},
+		{"non-conventional", []git.LogEntry{{Subject: "update readme"}}, semver.BumpPatch},
+		{"feat and fix", []git.LogEntry{
+			{Subject: "fix: handle nil"},
+			{Subject: "feat(api): add search"},
+			{Subject: "chore: bump deps"},
+		}, semver.BumpMinor},
+		{"bang", []git.LogEntry{
+			{Subject: "feat: add search"},
+			{Subject: "refactor!: drop v1 handlers"},
+		}, semver.BumpMajor},
+		{"breaking footer", []git.LogEntry{
+			{Subject: "fix: stricter parsing", Body: "Some detail.\n\nBREAKING CHANGE: empty input is now an error"},
+		}, semver.BumpMajor},
+		{"breaking hyphen footer", []git.LogEntry{
+			{Subject: "fix: stricter parsing", Body: "BREAKING-CHANGE: empty input is now an error"},
+		}, semver.BumpMajor},
+		{"breaking merge is ignored", []git.LogEntry{
+			{Subject: "Merge branch 'x'", Body: "BREAKING CHANGE: from a merged branch"},
+			{Subject: "fix: typo"},
+		}, semver.BumpPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextBump(tt.commits); got != tt.want {
+				t.Errorf("NextBump = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeFiles creates files (path -> content) under a new temporary root.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+const versionGo = "package version\n\n// Current is the version string of this CLI.\nconst Current = \"0.2.1\"\n"
+
+func TestPlanFiles(t *testing.T) {
+	next := semver.Version{Major: 0, Minor: 3, Patch: 0}
+	tests := []struct {
+		name     string
+		files    map[string]string
+		specs    []FileSpec
+		explicit bool
+		version  semver.Version
+		want     map[string]string
+	}{
+		{
+			name:  "VERSION with trailing newline",
+			files: map[string]string{"VERSION": "0.2.1\n"},
+			specs: DefaultFiles,
+			want:  map[string]string{"VERSION": "0.3.0\n"},
+		},
+		{
+			name:  "VERSION without trailing newline",
+			files: map[string]string{"VERSION": "0.2.1"},
+			specs: DefaultFiles,
+			want:  map[string]string{"VERSION": "0.3.0"},
+		},
+		{
+			name:  "VERSION with v prefix",
+			files: map[string]string{"VERSION": "v0.2.1\n"},
+			specs: DefaultFiles,
+			want:  map[string]string{"VERSION": "v0.3.0\n"},
+		},
+		{
+			name: "Go constant next to other files",
+			files: map[string]string{
+				"internal/version/version.go": versionGo,
+				"internal/version/check.go":   "package version\n\nconst LatestURL = \"https://example.com/1.0.0\"\n",
+			},
+			specs: DefaultFiles,
+			want:  map[string]string{"internal/version/version.go": strings.Replace(versionGo, "0.2.1", "0.3.0", 1)},
+		},
+		{
+			name:    "pre-release",
+			files:   map[string]string{"VERSION": "0.2.1\n"},
+			specs:   DefaultFiles,
+			version: semver.Version{Major: 1, Pre: "rc.1", Prefix: "v"},
+			want:    map[string]string{"VERSION": "1.0.0-rc.1\n"},
+		},
+		{
+			name:  "already up to date",
+			files: map[string]string{"VERSION": "0.3.0\n"},
+			specs: DefaultFiles,
+		},
+		{
+			name:  "defaults matching nothing",
+			files: map[string]string{"README.md": "0.2.1\n"},
+			specs: DefaultFiles,
+		},
+		{
+			name:     "explicit pattern",
+			files:    map[string]string{"package.json": "{\n  \"version\": \"0.2.1\",\n  \"dep\": \"0.2.1\"\n}\n"},
+			specs:    []FileSpec{{Path: "package.json", Pattern: `"version": "{version}"`}},
+			explicit: true,
+			want:     map[string]string{"package.json": "{\n  \"version\": \"0.3.0\",\n  \"dep\": \"0.2.1\"\n}\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeFiles(t, tt.files)
+			v := tt.version
+			if v == (semver.Version{}) {
+				v = next
+			}
+			changes, err := PlanFiles(root, tt.specs, tt.explicit, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, c := range changes {
+				if c.Old != tt.files[c.Path] {
+					t.Errorf("%s: Old = %q, want the file content %q", c.Path, c.Old, tt.files[c.Path])
+				}
+				got[c.Path] = c.Content
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("changes = %v, want %v", got, tt.want)
+			}
+			for path, want := range tt.want {
+				if got[path] != want {
+					t.Errorf("%s = %q, want %q", path, got[path], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanFilesErrors(t *testing.T) {
+	next := semver.Version{Major: 0, Minor: 3, Patch: 0}
+	tests := []struct {
+		name  string
+		files map[string]string
+		specs []FileSpec
+	}{
+		{"explicit glob matching nothing", nil, []FileSpec{{Path: "pkg/*/version.go", Pattern: `Version = "{version}"`}}},
+		{"pattern without placeholder", map[string]string{"VERSION": "0.2.1\n"}, []FileSpec{{Path: "VERSION", Pattern: "version"}}},
+		{"placeholder twice", map[string]string{"VERSION": "0.2.1\n"}, []FileSpec{{Path: "VERSION", Pattern: "{version}-{version}"}}},
+		{"pattern not found", map[string]string{"setup.py": "version='0.2.1'\n"}, []FileSpec{{Path: "setup.py", Pattern: `version="{version}"`}}},
+		{"bad glob", nil, []FileSpec{{Path: "[", Pattern: "{version}"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeFiles(t, tt.files)
+			if changes, err := PlanFiles(root, tt.specs, true, next); err == nil {
+				t.Errorf("PlanFiles succeeded with %v, want an error", changes)
+			}
+		})
+	}
+
+	// A default VERSION file must hold nothing but the version.
+	root := writeFiles(t, map[string]string{"VERSION": "release 0.2.1\n"})
+	if _, err := PlanFiles(root, DefaultFiles, false, next); err == nil {
+		t.Error("PlanFiles accepted a VERSION file with extra text")
+	}
+}
+
+func TestCurrentFromFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+		ok    bool
+	}{
+		{"VERSION first", map[string]string{"VERSION": "v1.4.0\n", "internal/version/version.go": versionGo}, "1.4.0", true},
+		{"Go constant", map[string]string{"internal/version/version.go": versionGo}, "0.2.1", true},
+		{"pre-release", map[string]string{"VERSION": "2.0.0-beta.2"}, "2.0.0-beta.2", true},
+		{"unparsable VERSION falls through", map[string]string{"VERSION": "next\n", "version/v.go": versionGo}, "0.2.1", true},
+		{"nothing", map[string]string{"README.md": "1.0.0"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CurrentFromFiles(writeFiles(t, tt.files), DefaultFiles)
+			if ok != tt.ok || (ok && got.String() != tt.want) {
+				t.Errorf("CurrentFromFiles = %v, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// The default patterns must keep matching this repository's own files.
+func TestCurrentFromFilesRepository(t *testing.T) {
+	root := filepath.Join("..", "..")
+	got, ok := CurrentFromFiles(root, []FileSpec{DefaultFiles[1]})
+	if !ok || got.String() != version.Current {
+		t.Errorf("version.go holds %v (found %v), want %s", got, ok, version.Current)
+	}
+}
+
+func TestReplaceVersion(t *testing.T) {
+	tests := []struct {
+		content, pattern string
+		want             string
+		found            bool
+	}{
+		{"1.0.0\n", "", "2.0.0\n", true},
+		{"  v1.0.0  \n", "", "  v2.0.0  \n", true},
+		{"1.0\n", "", "1.0\n", false},
+		{"1.0.0\n2.0.0\n", "", "1.0.0\n2.0.0\n", false},
+		{`Current = "1.0.0-rc.1"`, `Current = "{version}"`, `Current = "2.0.0"`, true},
+		{`a = "1.0.0"; Current = "1.0.0"`, `Current = "{version}"`, `a = "1.0.0"; Current = "2.0.0"`, true},
+		{"v=(1.0.0) v=(1.1.0)", "v=({version})", "v=(2.0.0) v=(1.1.0)", true},
+	}
+	for _, tt := range tests {
+		got, found, err := replaceVersion(tt.content, tt.pattern, "2.0.0")
+		if err != nil {
+			t.Fatalf("replaceVersion(%q, %q): %v", tt.content, tt.pattern, err)
+		}
+		if got != tt.want || found != tt.found {
+			t.Errorf("replaceVersion(%q, %q) = %q, %v; want %q, %v", tt.content, tt.pattern, got, found, tt.want, tt.found)
+		}
+	}
+}
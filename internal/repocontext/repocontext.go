@@ -0,0 +1,324 @@
+package repocontext
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+const (
+	// charsPerToken is a rough estimate used to keep context within budget.
+	charsPerToken = 4
+	// windowLines is the number of lines shown around hunks in non-Go files.
+	windowLines = 20
+	// historyLimit caps the recent commits listed per review.
+	historyLimit = 10
+	// readmeShare is the largest fraction of the budget the README may use.
+	readmeShare = 4
+)
+
+// Mode selects how much of each changed file is included.
+type Mode string
+
+const (
+	ModeNone      Mode = ""
+	ModeFunctions Mode = "functions"
+	ModeFiles     Mode = "files"
+)
+
+// Options controls which context is gathered.
+type Options struct {
+	Mode    Mode
+	Readme  bool
+	History bool
+	// BudgetTokens caps the estimated size of all gathered context.
+	BudgetTokens int
+}
+
+// Enabled reports whether any context was requested.
+func (o Options) Enabled() bool {
+	return o.Mode != ModeNone || o.Readme || o.History
+}
+
+// Source locates the new version of the changed files.
+type Source struct {
+	// Root is the repository root.
+	Root string
+	// Rev is the revision holding the new file contents: "" for the
+	// working tree, ":" for the index, otherwise a commit.
+	Rev string
+	// HistoryRev is where to start listing earlier commits, e.g. the
+	// parent of the reviewed changes.
+	HistoryRev string
+}
+
+// Snippet is a piece of unchanged code or documentation around the diff.
+type Snippet struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	// Label describes the snippet, e.g. "func (c *Client) Run" or "whole file".
+	Label   string
+	Content string
+}
+
+// Bundle is the context gathered for one review.
+type Bundle struct {
+	Snippets []Snippet
+	History  []git.LogEntry
+	Readme   *Snippet
+	// Truncated is set when the budget forced some context to be dropped.
+	Truncated bool
+}
+
+// Empty reports whether the bundle holds nothing.
+func (b Bundle) Empty() bool {
+	return len(b.Snippets) == 0 && len(b.History) == 0 && b.Readme == nil
+}
+
+// Build gathers context for the files in diff within opts.BudgetTokens.
+// Snippets for changed code take priority over history and the README.
+func Build(ctx context.Context, src Source, diff *git.Diff, opts Options) (Bundle, error) {
+	var bundle Bundle
+	if diff == nil || !opts.Enabled() {
+		return bundle, nil
+	}
+	budget := opts.BudgetTokens * charsPerToken
+
+	if opts.Mode != ModeNone {
+		for _, f := range diff.Files {
+			if f.Status == git.FileDeleted || f.IsBinary {
+				continue
+			}
+			content, err := src.ReadFile(ctx, f.Path())
+			if err != nil {
+				continue
+			}
+			for _, s := range fileSnippets(f, content, opts.Mode) {
+				if len(s.Content) > budget {
+					bundle.Truncated = true
+					continue
+				}
+				budget -= len(s.Content)
+				bundle.Snippets = append(bundle.Snippets, s)
+			}
+		}
+	}
+
+	if opts.History {
+		paths := make([]string, 0, len(diff.Files))
+		for _, f := range diff.Files {
+			paths = append(paths, f.Path())
+		}
+		rev := src.HistoryRev
+		if rev == "" {
+			rev = "HEAD"
+		}
+		args := append([]string{"-n", fmt.Sprint(historyLimit), rev, "--"}, paths...)
+		if commits, err := git.CommitLog(ctx, src.Root, args...); err == nil {
+			for _, c := range commits {
+				size := len(c.Subject) + len(c.Body) + 20
+				if size > budget {
+					bundle.Truncated = true
+					break
+				}
+				budget -= size
+				bundle.History = append(bundle.History, c)
+			}
+		}
+	}
+
+	if opts.Readme {
+		if readme := readReadme(ctx, src, min(budget, opts.BudgetTokens*charsPerToken/readmeShare)); readme != nil {
+			if strings.HasSuffix(readme.Label, "(truncated)") {
+				bundle.Truncated = true
+			}
+			bundle.Readme = readme
+		}
+	}
+
+	return bundle, nil
+}
+
+// ReadFile returns the contents of path, relative to the repository root,
+// at the source revision. Paths that would leave the repository are refused.
+func (src Source) ReadFile(ctx context.Context, path string) (string, error) {
+	if filepath.IsAbs(path) || !filepath.IsLocal(filepath.FromSlash(path)) {
+		return "", fmt.Errorf("path %q is outside the repository", path)
+	}
+	if src.Rev == "" {
+		data, err := os.ReadFile(filepath.Join(src.Root, path))
+		return string(data), err
+	}
+	return git.ShowFile(ctx, src.Root, src.Rev, path)
+}
+
+// fileSnippets picks the parts of content relevant to the hunks of f.
+func fileSnippets(f git.FileDiff, content string, mode Mode) []Snippet {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if mode == ModeFiles {
+		return []Snippet{{Path: f.Path(), StartLine: 1, EndLine: len(lines), Label: "whole file", Content: content}}
+	}
+
+	changed := changedLines(f)
+	if strings.HasSuffix(f.Path(), ".go") {
+		if snippets, err := goFunctionSnippets(f.Path(), content, lines, changed); err == nil {
+			return snippets
+		}
+	}
+	return windowSnippets(f, lines)
+}
+
+// changedLines returns the new-side line numbers touched by the diff. A
+// deletion is attributed to the line that now follows it.
+func changedLines(f git.FileDiff) []int {
+	var out []int
+	for _, h := range f.Hunks {
+		next := h.NewStart
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case git.LineAdded:
+				out = append(out, l.NewNumber)
+				next = l.NewNumber + 1
+			case git.LineDeleted:
+				out = append(out, next)
+			default:
+				next = l.NewNumber + 1
+			}
+		}
+	}
+	return out
+}
+
+// goFunctionSnippets returns each top-level declaration containing a changed line.
+func goFunctionSnippets(path, content string, lines []string, changed []int) ([]Snippet, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var snippets []Snippet
+	for _, decl := range file.Decls {
+		start := fset.Position(decl.Pos()).Line
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+			start = fset.Position(fn.Doc.Pos()).Line
+		}
+		end := fset.Position(decl.End()).Line
+		if !anyWithin(changed, start, end) || end > len(lines) {
+			continue
+		}
+		snippets = append(snippets, Snippet{
+			Path:      path,
+			StartLine: start,
+			EndLine:   end,
+			Label:     declLabel(decl),
+			Content:   strings.Join(lines[start-1:end], "\n"),
+		})
+	}
+	return snippets, nil
+}
+
+func declLabel(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			return fmt.Sprintf("method %s.%s", exprString(d.Recv.List[0].Type), d.Name.Name)
+		}
+		return "func " + d.Name.Name
+	case *ast.GenDecl:
+		return d.Tok.String() + " declaration"
+	default:
+		return "declaration"
+	}
+}
+
+func exprString(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		return "*" + exprString(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return exprString(t.X)
+	case *ast.IndexListExpr:
+		return exprString(t.X)
+	default:
+		return "?"
+	}
+}
+
+func anyWithin(nums []int, start, end int) bool {
+	for _, n := range nums {
+		if n >= start && n <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// windowSnippets returns merged line windows around each hunk.
+func windowSnippets(f git.FileDiff, lines []string) []Snippet {
+	type span struct{ start, end int }
+	var spans []span
+	for _, h := range f.Hunks {
+		s := span{start: max(1, h.NewStart-windowLines), end: min(len(lines), h.NewStart+h.NewLines+windowLines)}
+		if n := len(spans); n > 0 && s.start <= spans[n-1].end+1 {
+			spans[n-1].end = max(spans[n-1].end, s.end)
+			continue
+		}
+		spans = append(spans, s)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	snippets := make([]Snippet, 0, len(spans))
+	for _, s := range spans {
+		if s.start > s.end {
+			continue
+		}
+		snippets = append(snippets, Snippet{
+			Path:      f.Path(),
+			StartLine: s.start,
+			EndLine:   s.end,
+			Label:     "surrounding lines",
+			Content:   strings.Join(lines[s.start-1:s.end], "\n"),
+		})
+	}
+	return snippets
+}
+
+// readReadme loads the repository README at the source revision, like the
+// changed files, cut to at most limit characters.
+func readReadme(ctx context.Context, src Source, limit int) *Snippet {
+	if limit <= 0 {
+		return nil
+	}
+	for _, name := range []string{"README.md", "README", "README.rst", "README.txt", "readme.md"} {
+		content, err := src.ReadFile(ctx, name)
+		if err != nil {
+			// git show does not tell a missing file from other failures,
+			// so any error moves on to the next candidate.
+			continue
+		}
+		label := "README"
+		if len(content) > limit {
+			// Cut at a rune boundary so the prompt stays valid UTF-8.
+			for limit > 0 && !utf8.RuneStart(content[limit]) {
+				limit--
+			}
+			content = content[:limit]
+			label += " (truncated)"
+		}
+		return &Snippet{Path: name, Label: label, Content: content}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package repocontext
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+func TestSourceReadFileContainment(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(root), "outside.txt"), []byte("secret\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := Source{Root: root}
+
+	if got, err := src.ReadFile(context.Background(), "main.go"); err != nil || got != "package main\n" {
+		t.Fatalf("ReadFile(main.go) = %q, %v", got, err)
+	}
+	for _, path := range []string{"../outside.txt", "a/../../outside.txt", filepath.Join(filepath.Dir(root), "outside.txt"), ""} {
+		if got, err := src.ReadFile(context.Background(), path); err == nil {
+			t.Errorf("ReadFile(%q) = %q, want error", path, got)
+		}
+	}
+}
+
+func TestReadReadmeTruncatesAtRuneBoundary(t *testing.T) {
+	root := t.TempDir()
+	// "héllo wörld" has two-byte runes at byte offsets 1 and 7.
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("héllo wörld"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for limit := 1; limit <= 13; limit++ {
+		readme := readReadme(context.Background(), Source{Root: root}, limit)
+		if readme == nil {
+			t.Fatalf("readReadme(%d) = nil", limit)
+		}
+		if !utf8.ValidString(readme.Content) {
+			t.Errorf("readReadme(%d) content %q is not valid UTF-8", limit, readme.Content)
+		}
+		if len(readme.Content) > limit {
+			t.Errorf("readReadme(%d) content %q exceeds the limit", limit, readme.Content)
+		}
+		if truncated := strings.HasSuffix(readme.Label, "(truncated)"); truncated != (limit < 13) {
+			t.Errorf("readReadme(%d) label = %q", limit, readme.Label)
+		}
+	}
+}
+
+const goSource = `package demo
+
+import "fmt"
+
+// Greet says hello.
+func Greet(name string) string {
+	return fmt.Sprint("hi ", name)
+}
+
+var limit = 3
+
+type Box[T any] struct{ v T }
+
+func (b *Box[T]) Get() T {
+	return b.v
+}
+`
+
+func TestGoFunctionSnippets(t *testing.T) {
+	type span struct {
+		label      string
+		start, end int
+	}
+	tests := []struct {
+		name    string
+		changed []int
+		want    []span
+	}{
+		{"function body", []int{7}, []span{{"func Greet", 5, 8}}},
+		{"doc comment", []int{5}, []span{{"func Greet", 5, 8}}},
+		{"variable", []int{10}, []span{{"var declaration", 10, 10}}},
+		{"generic method", []int{15}, []span{{"method *Box.Get", 14, 16}}},
+		{"several declarations", []int{3, 15, 16}, []span{{"import declaration", 3, 3}, {"method *Box.Get", 14, 16}}},
+		{"between declarations", []int{2, 9, 13}, nil},
+		{"nothing changed", nil, nil},
+	}
+	lines := strings.Split(strings.TrimSuffix(goSource, "\n"), "\n")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippets, err := goFunctionSnippets("demo.go", goSource, lines, tt.changed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []span
+			for _, s := range snippets {
+				got = append(got, span{s.Label, s.StartLine, s.EndLine})
+				if want := strings.Join(lines[s.StartLine-1:s.EndLine], "\n"); s.Content != want || s.Path != "demo.go" {
+					t.Errorf("snippet %s = %q in %s, want %q", s.Label, s.Content, s.Path, want)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("goFunctionSnippets(%v) = %v, want %v", tt.changed, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := goFunctionSnippets("bad.go", "package demo\nfunc {", nil, []int{2}); err == nil {
+		t.Error("goFunctionSnippets accepted invalid Go")
+	}
+}
+
+func TestWindowSnippets(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprint("line ", i+1)
+	}
+	hunk := func(start, count int) git.Hunk { return git.Hunk{NewStart: start, NewLines: count} }
+	tests := []struct {
+		name  string
+		hunks []git.Hunk
+		want  [][2]int
+	}{
+		{"single hunk", []git.Hunk{hunk(50, 2)}, [][2]int{{30, 72}}},
+		{"clamped to the file", []git.Hunk{hunk(10, 2), hunk(95, 10)}, [][2]int{{1, 32}, {75, 100}}},
+		{"overlapping windows merge", []git.Hunk{hunk(10, 2), hunk(40, 1)}, [][2]int{{1, 61}}},
+		{"adjacent windows merge", []git.Hunk{hunk(21, 1), hunk(63, 1)}, [][2]int{{1, 84}}},
+		{"separate windows", []git.Hunk{hunk(21, 1), hunk(64, 1)}, [][2]int{{1, 42}, {44, 85}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := git.FileDiff{NewPath: "notes.txt", Hunks: tt.hunks}
+			var got [][2]int
+			for _, s := range windowSnippets(f, lines) {
+				got = append(got, [2]int{s.StartLine, s.EndLine})
+				if first := fmt.Sprint("line ", s.StartLine); !strings.HasPrefix(s.Content, first+"\n") {
+					t.Errorf("snippet %d-%d starts with %q, want %q", s.StartLine, s.EndLine, s.Content[:10], first)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("windowSnippets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildBudget(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"a.txt":     strings.Repeat("a", 99) + "\n",
+		"b.txt":     strings.Repeat("b", 99) + "\n",
+		"README.md": strings.Repeat("r", 200),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	diff := &git.Diff{Files: []git.FileDiff{
+		{NewPath: "a.txt", Status: git.FileModified},
+		{NewPath: "b.txt", Status: git.FileModified},
+		{OldPath: "gone.txt", Status: git.FileDeleted},
+	}}
+
+	tests := []struct {
+		name       string
+		budget     int
+		wantFiles  []string
+		wantReadme int
+	}{
+		// 160 characters: one file fits, the README gets a quarter of the budget.
+		{"tight", 40, []string{"a.txt"}, 40},
+		// 240 characters: both files fit, leaving 40 for the README.
+		{"files first", 60, []string{"a.txt", "b.txt"}, 40},
+		{"generous", 1000, []string{"a.txt", "b.txt"}, 200},
+		// 40 characters: no file fits and the README gets a quarter.
+		{"too small for files", 10, nil, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := Build(context.Background(), Source{Root: root}, diff, Options{Mode: ModeFiles, Readme: true, BudgetTokens: tt.budget})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			used := 0
+			for _, s := range bundle.Snippets {
+				got = append(got, s.Path)
+				used += len(s.Content)
+			}
+			if !reflect.DeepEqual(got, tt.wantFiles) {
+				t.Errorf("snippets = %v, want %v", got, tt.wantFiles)
+			}
+			if bundle.Readme == nil {
+				t.Fatal("no README")
+			}
+			if n := len(bundle.Readme.Content); n != tt.wantReadme {
+				t.Errorf("README has %d characters, want %d", n, tt.wantReadme)
+			}
+			if used+len(bundle.Readme.Content) > tt.budget*charsPerToken {
+				t.Errorf("context uses %d characters, over the budget of %d", used+len(bundle.Readme.Content), tt.budget*charsPerToken)
+			}
+			wantTruncated := len(tt.wantFiles) < 2 || tt.wantReadme < 200
+			if bundle.Truncated != wantTruncated {
+				t.Errorf("Truncated = %v, want %v", bundle.Truncated, wantTruncated)
+			}
+		})
+	}
+}
+
+func TestBuildReadsReviewedRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "--quiet", "--initial-branch=main")
+	write("README.md", "committed readme\n")
+	write("main.txt", "committed\n")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "initial")
+	write("README.md", "staged readme\n")
+	write("main.txt", "staged\n")
+	run("add", ".")
+	write("README.md", "working readme\n")
+	write("main.txt", "working\n")
+
+	diff := &git.Diff{Files: []git.FileDiff{{NewPath: "main.txt", Status: git.FileModified}}}
+	tests := []struct {
+		rev  string
+		want string
+	}{
+		{"HEAD", "committed"},
+		{":", "staged"},
+		{"", "working"},
+	}
+	for _, tt := range tests {
+		src := Source{Root: root, Rev: tt.rev}
+		bundle, err := Build(context.Background(), src, diff, Options{Mode: ModeFiles, Readme: true, BudgetTokens: 1000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bundle.Snippets) != 1 || bundle.Snippets[0].Content != tt.want+"\n" {
+			t.Errorf("Rev %q: snippets = %+v, want the %s file", tt.rev, bundle.Snippets, tt.want)
+		}
+		if bundle.Readme == nil || bundle.Readme.Content != tt.want+" readme\n" {
+			t.Errorf("Rev %q: README = %+v, want the %s README", tt.rev, bundle.Readme, tt.want)
+		}
+	}
+}
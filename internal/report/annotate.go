@@ -0,0 +1,204 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+const (
+	// annotationContext is the number of lines shown around a finding.
+	annotationContext = 2
+	// maxAnnotatedLines caps how many flagged lines get a caret underline.
+	maxAnnotatedLines = 8
+	// maxSnippetLines caps the whole snippet, so a huge range reported by the
+	// model cannot flood the terminal.
+	maxSnippetLines = 40
+	tabWidth        = 4
+)
+
+// ResolveLocations rewrites finding paths to the exact paths used in diff,
+// so shortened or prefixed paths from the model still map onto real files.
+func ResolveLocations(findings []ai.Finding, diff *git.Diff) {
+	if diff == nil {
+		return
+	}
+	for i := range findings {
+		if findings[i].File == "" {
+			continue
+		}
+		if fd := diff.File(findings[i].File); fd != nil {
+			findings[i].File = fd.Path()
+		}
+	}
+}
+
+// sourceLine is a line shown in an annotation gutter.
+type sourceLine struct {
+	number  int
+	marker  string
+	content string
+}
+
+// writeAnnotation prints a finding like a compiler diagnostic: a header, the
+// location, the offending lines with surrounding context in a numbered
+// gutter, and a caret underline below each flagged line.
+func writeAnnotation(w io.Writer, f ai.Finding, diff *git.Diff, files FileSource) {
+	fmt.Fprintf(w, "%s[%s]: %s\n", f.Severity, f.Category, f.Message)
+	if f.File == "" {
+		writeSuggestion(w, f, "  ")
+		fmt.Fprintln(w)
+		return
+	}
+
+	location := f.Location()
+	var fd *git.FileDiff
+	if diff != nil {
+		fd = diff.File(f.File)
+	}
+	if fd != nil && f.StartLine > 0 && !fd.ContainsNewLine(f.StartLine) {
+		location += " (outside the changed lines)"
+	}
+	fmt.Fprintf(w, "  --> %s\n", location)
+
+	lines := annotationLines(f, fd, files)
+	if len(lines) == 0 {
+		writeSuggestion(w, f, "   = ")
+		fmt.Fprintln(w)
+		return
+	}
+
+	width := len(strconv.Itoa(lines[len(lines)-1].number))
+	gutter := strings.Repeat(" ", width)
+	fmt.Fprintf(w, "%s |\n", gutter)
+
+	end := f.EndLine
+	if end < f.StartLine {
+		end = f.StartLine
+	}
+	underlined := 0
+	for _, l := range lines {
+		content := strings.ReplaceAll(l.content, "\t", strings.Repeat(" ", tabWidth))
+		fmt.Fprintf(w, "%*d |%s %s\n", width, l.number, l.marker, content)
+
+		if l.number < f.StartLine || l.number > end {
+			continue
+		}
+		underlined++
+		if underlined > maxAnnotatedLines {
+			continue
+		}
+		trimmed := strings.TrimRight(content, " ")
+		indent := len(trimmed) - len(strings.TrimLeft(trimmed, " "))
+		carets := len(trimmed) - indent
+		if carets < 1 {
+			carets = 1
+		}
+		fmt.Fprintf(w, "%s | %s%s\n", gutter, strings.Repeat(" ", indent+1), strings.Repeat("^", carets))
+	}
+	if underlined > maxAnnotatedLines {
+		fmt.Fprintf(w, "%s | ... (%d more flagged lines)\n", gutter, underlined-maxAnnotatedLines)
+	}
+	fmt.Fprintf(w, "%s |\n", gutter)
+	writeSuggestion(w, f, gutter+" = ")
+	fmt.Fprintln(w)
+}
+
+func writeSuggestion(w io.Writer, f ai.Finding, prefix string) {
+	if f.Suggestion == "" {
+		return
+	}
+	parts := strings.Split(f.Suggestion, "\n")
+	fmt.Fprintf(w, "%ssuggestion: %s\n", prefix, parts[0])
+	pad := strings.Repeat(" ", len(prefix)+len("suggestion: "))
+	for _, p := range parts[1:] {
+		fmt.Fprintf(w, "%s%s\n", pad, p)
+	}
+}
+
+// annotationLines collects the flagged lines plus context. Lines come from
+// the diff when available, otherwise from the reviewed version of the file.
+// Only files in the diff are read, so a path made up by the model cannot
+// point anywhere else. Line numbers from the model are clamped to the file.
+func annotationLines(f ai.Finding, fd *git.FileDiff, files FileSource) []sourceLine {
+	if f.StartLine <= 0 || fd == nil {
+		return nil
+	}
+	end := f.EndLine
+	if end < f.StartLine {
+		end = f.StartLine
+	}
+
+	diffLines := fd.NewLines()
+	var fileLines []string
+	fileLoaded := false
+
+	last := 0
+	for n := range diffLines {
+		if n > last {
+			last = n
+		}
+	}
+	if end+annotationContext > last {
+		fileLines = readFileLines(files, fd)
+		fileLoaded = true
+		if len(fileLines) > last {
+			last = len(fileLines)
+		}
+	}
+	if f.StartLine > last {
+		return nil
+	}
+	if end > last {
+		end = last
+	}
+
+	from := f.StartLine - annotationContext
+	if from < 1 {
+		from = 1
+	}
+	to := end + annotationContext
+	if to > last {
+		to = last
+	}
+	if to-from+1 > maxSnippetLines {
+		to = from + maxSnippetLines - 1
+	}
+
+	var out []sourceLine
+	for n := from; n <= to; n++ {
+		if l, ok := diffLines[n]; ok {
+			marker := " "
+			if l.Kind == git.LineAdded {
+				marker = "+"
+			}
+			out = append(out, sourceLine{number: n, marker: marker, content: l.Content})
+			continue
+		}
+		if !fileLoaded {
+			fileLines = readFileLines(files, fd)
+			fileLoaded = true
+		}
+		if n-1 < len(fileLines) {
+			out = append(out, sourceLine{number: n, marker: " ", content: fileLines[n-1]})
+		}
+	}
+	return out
+}
+
+// readFileLines returns the reviewed version of the file changed by fd.
+// Deleted and binary files have no lines to show.
+func readFileLines(files FileSource, fd *git.FileDiff) []string {
+	if files == nil || fd.Status == git.FileDeleted || fd.IsBinary {
+		return nil
+	}
+	content, err := files(fd.Path())
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
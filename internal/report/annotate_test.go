@@ -0,0 +1,146 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+const annotateDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -5,3 +5,4 @@ import "fmt"
+ func main() {
+ 	x := 1
++	fmt.Println(x)
+ }
+diff --git a/old.go b/old.go
+deleted file mode 100644
+index 3333333..0000000
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+`
+
+// reviewedMain is main.go at the reviewed revision; the working tree may
+// hold something else entirely.
+const reviewedMain = "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tx := 1\n\tfmt.Println(x)\n}\n"
+
+func TestAnnotationLines(t *testing.T) {
+	diff, err := git.ParseDiff(annotateDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var read []string
+	files := func(path string) (string, error) {
+		read = append(read, path)
+		if path == "main.go" {
+			return reviewedMain, nil
+		}
+		return "secret\n", nil
+	}
+
+	tests := []struct {
+		name    string
+		finding ai.Finding
+		want    []string
+		reads   []string
+	}{
+		{
+			name:    "lines from the diff and the reviewed file",
+			finding: ai.Finding{File: "main.go", StartLine: 5},
+			want:    []string{"3  import \"fmt\"", "4  ", "5  func main() {", "6  \tx := 1", "7 +\tfmt.Println(x)"},
+			reads:   []string{"main.go"},
+		},
+		{
+			name:    "lines only from the diff",
+			finding: ai.Finding{File: "main.go", StartLine: 6, EndLine: 7},
+			want:    []string{"4  ", "5  func main() {", "6  \tx := 1", "7 +\tfmt.Println(x)", "8  }"},
+			reads:   []string{"main.go"},
+		},
+		{
+			name:    "end line past the file",
+			finding: ai.Finding{File: "main.go", StartLine: 7, EndLine: 2000000000},
+			want:    []string{"5  func main() {", "6  \tx := 1", "7 +\tfmt.Println(x)", "8  }"},
+			reads:   []string{"main.go"},
+		},
+		{
+			name:    "start line past the file",
+			finding: ai.Finding{File: "main.go", StartLine: 2000000000},
+			reads:   []string{"main.go"},
+		},
+		{
+			name:    "file outside the diff",
+			finding: ai.Finding{File: "../../etc/passwd", StartLine: 1},
+		},
+		{
+			name:    "absolute path",
+			finding: ai.Finding{File: "/etc/passwd", StartLine: 1},
+		},
+		{
+			name:    "deleted file",
+			finding: ai.Finding{File: "old.go", StartLine: 1},
+		},
+		{
+			name:    "no line",
+			finding: ai.Finding{File: "main.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read = nil
+			lines := annotationLines(tt.finding, diff.File(tt.finding.File), files)
+			var got []string
+			for _, l := range lines {
+				got = append(got, strconv.Itoa(l.number)+" "+l.marker+l.content)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("lines =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if strings.Join(read, ",") != strings.Join(tt.reads, ",") {
+				t.Errorf("read files %v, want %v", read, tt.reads)
+			}
+		})
+	}
+}
+
+func TestAnnotationLinesLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("diff --git a/big.go b/big.go\nnew file mode 100644\nindex 0000000..1111111\n--- /dev/null\n+++ b/big.go\n@@ -0,0 +1,100 @@\n")
+	for i := 1; i <= 100; i++ {
+		fmt.Fprintf(&b, "+line %d\n", i)
+	}
+	diff, err := git.ParseDiff(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := ai.Finding{File: "big.go", StartLine: 10, EndLine: 90}
+	lines := annotationLines(f, diff.File("big.go"), nil)
+	if len(lines) != maxSnippetLines {
+		t.Fatalf("got %d lines, want %d", len(lines), maxSnippetLines)
+	}
+	if lines[0].number != 8 || lines[len(lines)-1].number != 8+maxSnippetLines-1 {
+		t.Errorf("lines %d-%d, want 8-%d", lines[0].number, lines[len(lines)-1].number, 8+maxSnippetLines-1)
+	}
+}
+
+func TestWriteAnnotationWithoutFiles(t *testing.T) {
+	diff, err := git.ParseDiff(annotateDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	f := ai.Finding{File: "main.go", StartLine: 7, Severity: ai.SeverityLow, Category: "style", Message: "Debug print left in"}
+	writeAnnotation(&out, f, diff, nil)
+	if !strings.Contains(out.String(), "7 |+     fmt.Println(x)") {
+		t.Errorf("annotation does not show the added line:\n%s", out.String())
+	}
+}
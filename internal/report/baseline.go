@@ -0,0 +1,163 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+)
+
+const (
+	baselineVersion = 1
+	// similarityThreshold is the minimum word overlap for two messages about
+	// the same file and category to be treated as the same finding. AI
+	// reviewers rarely phrase a finding identically twice.
+	similarityThreshold = 0.6
+)
+
+// Baseline lists accepted findings that should not fail the build again.
+type Baseline struct {
+	Version   int             `json:"version"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Findings  []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry is a suppressed finding.
+type BaselineEntry struct {
+	Fingerprint string      `json:"fingerprint"`
+	File        string      `json:"file"`
+	Category    string      `json:"category"`
+	Severity    ai.Severity `json:"severity"`
+	Message     string      `json:"message"`
+}
+
+// LoadBaseline reads a baseline file. A missing file yields an empty baseline.
+func LoadBaseline(path string) (Baseline, error) {
+	var b Baseline
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return b, err
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if b.Version != 0 && b.Version != baselineVersion {
+		return b, fmt.Errorf("unsupported baseline version %d in %s", b.Version, path)
+	}
+	return b, nil
+}
+
+// SaveBaseline writes findings as the new baseline at path.
+func SaveBaseline(path string, findings []ai.Finding) error {
+	b := Baseline{
+		Version:   baselineVersion,
+		UpdatedAt: time.Now().UTC(),
+		Findings:  make([]BaselineEntry, 0, len(findings)),
+	}
+	for _, f := range findings {
+		b.Findings = append(b.Findings, BaselineEntry{
+			Fingerprint: Fingerprint(f),
+			File:        f.File,
+			Category:    f.Category,
+			Severity:    f.Severity,
+			Message:     f.Message,
+		})
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Filter splits findings into new ones and ones already accepted in the baseline.
+func (b Baseline) Filter(findings []ai.Finding) (fresh, suppressed []ai.Finding) {
+	for _, f := range findings {
+		if b.contains(f) {
+			suppressed = append(suppressed, f)
+			continue
+		}
+		fresh = append(fresh, f)
+	}
+	return fresh, suppressed
+}
+
+// contains reports whether f was accepted in the baseline. An accepted
+// finding only covers the severity it was accepted at: if the reviewer later
+// rates the same issue as more serious, it is reported again.
+func (b Baseline) contains(f ai.Finding) bool {
+	fp := Fingerprint(f)
+	words := messageWords(f.Message)
+	for _, entry := range b.Findings {
+		if f.Severity.Rank() > entry.Severity.Rank() {
+			continue
+		}
+		if entry.Fingerprint == fp {
+			return true
+		}
+		if entry.File == f.File && entry.Category == f.Category &&
+			similarity(words, messageWords(entry.Message)) >= similarityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint identifies a finding independently of its line numbers and of
+// minor wording differences, so it stays stable across pushes.
+func Fingerprint(f ai.Finding) string {
+	normalized := strings.Join(messageWords(f.Message), " ")
+	sum := sha256.Sum256([]byte(f.File + "\x00" + f.Category + "\x00" + normalized))
+	return hex.EncodeToString(sum[:16])
+}
+
+// messageWords lowercases a message and splits it into words, dropping
+// digits so that shifted line numbers do not change the result.
+func messageWords(msg string) []string {
+	return strings.FieldsFunc(strings.ToLower(msg), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// similarity returns the Jaccard index of two word lists.
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, w := range a {
+		set[w] = true
+	}
+	union := len(set)
+	inter := 0
+	seen := make(map[string]bool, len(b))
+	for _, w := range b {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			inter++
+		} else {
+			union++
+		}
+	}
+	return float64(inter) / float64(union)
+}
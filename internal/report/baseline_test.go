@@ -0,0 +1,83 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+)
+
+func TestBaselineFilter(t *testing.T) {
+	accepted := []ai.Finding{
+		{File: "api/handler.go", StartLine: 10, Severity: ai.SeverityMedium, Category: "security", Message: "User input is passed to the SQL query without escaping"},
+		{File: "main.go", Severity: ai.SeverityLow, Category: "style", Message: "Function name should be camel case"},
+	}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := SaveBaseline(path, accepted); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		finding    ai.Finding
+		suppressed bool
+	}{
+		{
+			name:       "same finding on a shifted line",
+			finding:    ai.Finding{File: "api/handler.go", StartLine: 42, Severity: ai.SeverityMedium, Category: "security", Message: "User input is passed to the SQL query without escaping"},
+			suppressed: true,
+		},
+		{
+			name:       "reworded finding",
+			finding:    ai.Finding{File: "api/handler.go", Severity: ai.SeverityMedium, Category: "security", Message: "User input passed to SQL query without escaping it"},
+			suppressed: true,
+		},
+		{
+			name:       "lower severity",
+			finding:    ai.Finding{File: "api/handler.go", Severity: ai.SeverityLow, Category: "security", Message: "User input is passed to the SQL query without escaping"},
+			suppressed: true,
+		},
+		{
+			name:    "escalated severity",
+			finding: ai.Finding{File: "api/handler.go", Severity: ai.SeverityCritical, Category: "security", Message: "User input is passed to the SQL query without escaping"},
+		},
+		{
+			name:    "escalated reworded finding",
+			finding: ai.Finding{File: "api/handler.go", Severity: ai.SeverityHigh, Category: "security", Message: "User input passed to SQL query without escaping it"},
+		},
+		{
+			name:    "other file",
+			finding: ai.Finding{File: "api/other.go", Severity: ai.SeverityMedium, Category: "security", Message: "User input is passed to the SQL query without escaping"},
+		},
+		{
+			name:    "other category",
+			finding: ai.Finding{File: "main.go", Severity: ai.SeverityLow, Category: "perf", Message: "Function name should be camel case"},
+		},
+		{
+			name:    "different message",
+			finding: ai.Finding{File: "main.go", Severity: ai.SeverityLow, Category: "style", Message: "Exported type lacks a doc comment"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fresh, suppressed := baseline.Filter([]ai.Finding{tt.finding})
+			if got := len(suppressed) == 1; got != tt.suppressed {
+				t.Errorf("suppressed = %v, want %v (fresh %v)", got, tt.suppressed, fresh)
+			}
+		})
+	}
+}
+
+func TestLoadBaselineMissing(t *testing.T) {
+	b, err := LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Findings) != 0 {
+		t.Errorf("Findings = %v, want none", b.Findings)
+	}
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+)
+
+// writeMarkdown renders a review suitable for a pull request comment.
+func writeMarkdown(w io.Writer, review Review) error {
+	var b strings.Builder
+	b.WriteString("## AI Review\n\n")
+	if review.Target != "" {
+		b.WriteString(fmt.Sprintf("_Reviewed: %s_\n\n", review.Target))
+	}
+	if review.Summary != "" {
+		b.WriteString(review.Summary + "\n\n")
+	}
+
+	if review.Suppressed > 0 {
+		b.WriteString(fmt.Sprintf("_%d known finding(s) suppressed by the baseline._\n\n", review.Suppressed))
+	}
+
+	if len(review.Findings) == 0 {
+		b.WriteString("No findings.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("| Severity | Location | Category | Message |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, f := range review.Findings {
+		b.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n",
+			severityBadge(f.Severity), f.Location(), f.Category, markdownCell(f.Message)))
+	}
+
+	var withFixes []ai.Finding
+	for _, f := range review.Findings {
+		if f.Suggestion != "" {
+			withFixes = append(withFixes, f)
+		}
+	}
+	if len(withFixes) > 0 {
+		b.WriteString("\n### Suggested fixes\n")
+		for _, f := range withFixes {
+			b.WriteString(fmt.Sprintf("\n**`%s`** – %s\n\n", f.Location(), f.Message))
+			b.WriteString("```\n" + strings.TrimRight(f.Suggestion, "\n") + "\n```\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func severityBadge(s ai.Severity) string {
+	switch s {
+	case ai.SeverityCritical:
+		return "🛑 critical"
+	case ai.SeverityHigh:
+		return "🔴 high"
+	case ai.SeverityMedium:
+		return "🟠 medium"
+	case ai.SeverityLow:
+		return "🟡 low"
+	default:
+		return "🔵 info"
+	}
+}
+
+// markdownCell makes text safe to place inside a single table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,123 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/git"
+	"github.com/vinhtran/git-smart/internal/i18n"
+)
+
+// Format selects how a review is rendered.
+type Format string
+
+const (
+	// FormatText prints findings as annotated source snippets.
+	FormatText Format = "text"
+	// FormatProse prints the free-form sectioned review.
+	FormatProse    Format = "prose"
+	FormatJSON     Format = "json"
+	FormatSARIF    Format = "sarif"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat validates a --format value.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatProse, FormatJSON, FormatSARIF, FormatMarkdown:
+		return f, nil
+	case "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (expected text, prose, json, sarif or markdown)", s)
+	}
+}
+
+// Structured reports whether the format needs findings rather than prose.
+func (f Format) Structured() bool {
+	return f != FormatProse
+}
+
+// Review is a completed AI review ready to be rendered.
+type Review struct {
+	Target   string       `json:"target"`
+	Summary  string       `json:"summary"`
+	Findings []ai.Finding `json:"findings"`
+	// Suppressed counts findings hidden because they are in the baseline.
+	Suppressed int `json:"suppressed,omitempty"`
+
+	// Diff and Files let the text renderer show source context for findings.
+	Diff  *git.Diff  `json:"-"`
+	Files FileSource `json:"-"`
+}
+
+// FileSource returns the reviewed version of a file changed in the diff,
+// e.g. from the index or a commit rather than the working tree.
+type FileSource func(path string) (string, error)
+
+// Write renders review to w in the given format.
+func Write(w io.Writer, format Format, review Review) error {
+	sortFindings(review.Findings)
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, review)
+	case FormatSARIF:
+		return writeSARIF(w, review)
+	case FormatMarkdown:
+		return writeMarkdown(w, review)
+	default:
+		return writeText(w, review)
+	}
+}
+
+// sortFindings orders findings by severity (most serious first), then location.
+func sortFindings(findings []ai.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() > b.Severity.Rank()
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+}
+
+func writeJSON(w io.Writer, review Review) error {
+	if review.Findings == nil {
+		review.Findings = []ai.Finding{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(review)
+}
+
+func writeText(w io.Writer, review Review) error {
+	divider := strings.Repeat("-", 60)
+	fmt.Fprintln(w, divider)
+	fmt.Fprintln(w, i18n.T(i18n.MsgReviewHeader))
+	fmt.Fprintln(w, divider)
+	if review.Summary != "" {
+		fmt.Fprintln(w, review.Summary)
+		fmt.Fprintln(w)
+	}
+	if len(review.Findings) == 0 {
+		fmt.Fprintln(w, i18n.T(i18n.MsgNoFindings))
+	}
+	if review.Suppressed > 0 {
+		fmt.Fprintln(w, i18n.T(i18n.MsgFindingsSuppressed, review.Suppressed))
+	}
+	for _, f := range review.Findings {
+		writeAnnotation(w, f, review.Diff, review.Files)
+	}
+	fmt.Fprintln(w, divider)
+	return nil
+}
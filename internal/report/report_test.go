@@ -0,0 +1,242 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vinhtran/git-smart/internal/ai"
+	"github.com/vinhtran/git-smart/internal/version"
+)
+
+// sampleReview returns findings deliberately out of order, so the renderers
+// also show that findings are sorted by severity.
+func sampleReview() Review {
+	return Review{
+		Target:  "staged changes",
+		Summary: "Two issues.",
+		Findings: []ai.Finding{
+			{File: "internal/db/query.go", StartLine: 12, EndLine: 14, Severity: ai.SeverityMedium, Category: "performance", Message: "Query runs inside the loop", Suggestion: "Batch the lookups"},
+			{File: "cmd/server/main.go", StartLine: 40, EndLine: 40, Severity: ai.SeverityCritical, Category: "security", Message: "Token | logged in plain text"},
+			{Severity: ai.SeverityInfo, Category: "docs", Message: "README lacks the new flag"},
+		},
+	}
+}
+
+const wantSARIF = `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "smartgit",
+          "version": "{version}",
+          "informationUri": "https://github.com/vinitran/smart-git",
+          "rules": [
+            {
+              "id": "docs",
+              "shortDescription": {
+                "text": "AI review: docs"
+              }
+            },
+            {
+              "id": "performance",
+              "shortDescription": {
+                "text": "AI review: performance"
+              }
+            },
+            {
+              "id": "security",
+              "shortDescription": {
+                "text": "AI review: security"
+              }
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "security",
+          "level": "error",
+          "message": {
+            "text": "Token | logged in plain text"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "cmd/server/main.go"
+                },
+                "region": {
+                  "startLine": 40,
+                  "endLine": 40
+                }
+              }
+            }
+          ],
+          "partialFingerprints": {
+            "smartgitFinding/v1": "6595cf7aaa0e9fdbba15e9ca1c4a755d"
+          },
+          "properties": {
+            "severity": "critical"
+          }
+        },
+        {
+          "ruleId": "performance",
+          "level": "warning",
+          "message": {
+            "text": "Query runs inside the loop\n\nSuggested fix:\nBatch the lookups"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "internal/db/query.go"
+                },
+                "region": {
+                  "startLine": 12,
+                  "endLine": 14
+                }
+              }
+            }
+          ],
+          "partialFingerprints": {
+            "smartgitFinding/v1": "8ac1497c6c6205e79188f24b862ea42f"
+          },
+          "properties": {
+            "severity": "medium"
+          }
+        },
+        {
+          "ruleId": "docs",
+          "level": "note",
+          "message": {
+            "text": "README lacks the new flag"
+          },
+          "partialFingerprints": {
+            "smartgitFinding/v1": "d94f6c6ad38216772852bcf18dfe628f"
+          },
+          "properties": {
+            "severity": "info"
+          }
+        }
+      ]
+    }
+  ]
+}
+`
+
+func TestWriteSARIF(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, FormatSARIF, sampleReview()); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(wantSARIF, "{version}", version.Current, 1)
+	if out.String() != want {
+		t.Errorf("SARIF =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestSARIFLevel(t *testing.T) {
+	tests := []struct {
+		severity ai.Severity
+		want     string
+	}{
+		{ai.SeverityCritical, "error"},
+		{ai.SeverityHigh, "error"},
+		{ai.SeverityMedium, "warning"},
+		{ai.SeverityLow, "note"},
+		{ai.SeverityInfo, "note"},
+	}
+	for _, tt := range tests {
+		if got := sarifLevel(tt.severity); got != tt.want {
+			t.Errorf("sarifLevel(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+const wantMarkdown = "## AI Review\n\n" +
+	"_Reviewed: staged changes_\n\n" +
+	"Two issues.\n\n" +
+	"| Severity | Location | Category | Message |\n" +
+	"|---|---|---|---|\n" +
+	"| 🛑 critical | `cmd/server/main.go:40` | security | Token \\| logged in plain text |\n" +
+	"| 🟠 medium | `internal/db/query.go:12-14` | performance | Query runs inside the loop |\n" +
+	"| 🔵 info | `(general)` | docs | README lacks the new flag |\n" +
+	"\n### Suggested fixes\n" +
+	"\n**`internal/db/query.go:12-14`** – Query runs inside the loop\n\n" +
+	"```\nBatch the lookups\n```\n"
+
+func TestWriteMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, FormatMarkdown, sampleReview()); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != wantMarkdown {
+		t.Errorf("Markdown =\n%s\nwant\n%s", out.String(), wantMarkdown)
+	}
+
+	out.Reset()
+	if err := Write(&out, FormatMarkdown, Review{Suppressed: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "## AI Review\n\n_2 known finding(s) suppressed by the baseline._\n\nNo findings.\n"; out.String() != want {
+		t.Errorf("empty Markdown = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, FormatJSON, sampleReview()); err != nil {
+		t.Fatal(err)
+	}
+	var got Review
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if got.Target != "staged changes" || len(got.Findings) != 3 {
+		t.Fatalf("decoded review = %+v", got)
+	}
+	var order []ai.Severity
+	for _, f := range got.Findings {
+		order = append(order, f.Severity)
+	}
+	if want := []ai.Severity{ai.SeverityCritical, ai.SeverityMedium, ai.SeverityInfo}; !slices.Equal(order, want) {
+		t.Errorf("findings ordered %v, want %v", order, want)
+	}
+
+	out.Reset()
+	if err := Write(&out, FormatJSON, Review{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"findings": []`) {
+		t.Errorf("empty review JSON has no findings array:\n%s", out.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Format
+	}{
+		{"", FormatText},
+		{"TEXT", FormatText},
+		{"prose", FormatProse},
+		{" json ", FormatJSON},
+		{"sarif", FormatSARIF},
+		{"md", FormatMarkdown},
+		{"markdown", FormatMarkdown},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseFormat("html"); err == nil {
+		t.Error("ParseFormat accepted html")
+	}
+}
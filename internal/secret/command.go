@@ -0,0 +1,65 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandStore reads secrets by running an external credential helper such
+// as `pass show` or `op read`. It is read-only: secrets must be written with
+// the helper's own tooling.
+type CommandStore struct {
+	command string
+}
+
+// NewCommandStore creates a store backed by the given shell command template.
+func NewCommandStore(command string) *CommandStore {
+	return &CommandStore{command: command}
+}
+
+// Name implements Store.
+func (s *CommandStore) Name() string {
+	return BackendCommand
+}
+
+// Get implements Store.
+func (s *CommandStore) Get(ctx context.Context, name string) (string, error) {
+	cmdStr := strings.ReplaceAll(s.command, "{name}", name)
+
+	shell := strings.TrimSpace(os.Getenv("SHELL"))
+	if shell == "" {
+		shell = "sh"
+	}
+
+	cmd := exec.CommandContext(ctx, shell, "-c", cmdStr)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Credential helpers like pass or op may need to prompt for unlock.
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("secret command %q failed: %w: %s", cmdStr, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Helpers like pass print the secret on the first line and metadata after it.
+	value := strings.TrimSpace(strings.SplitN(stdout.String(), "\n", 2)[0])
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set implements Store.
+func (s *CommandStore) Set(ctx context.Context, name, value string) error {
+	return ErrReadOnly
+}
+
+// Delete implements Store.
+func (s *CommandStore) Delete(ctx context.Context, name string) error {
+	return ErrReadOnly
+}
@@ -0,0 +1,57 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCommandStore(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr error
+	}{
+		{"first line only", `printf 'hunter2\nlogin: me\nurl: example.com\n'`, "hunter2", nil},
+		{"trimmed", `printf '  s3cret \t\n'`, "s3cret", nil},
+		{"name placeholder", `echo "value-for-{name}"`, "value-for-gemini_api_key", nil},
+		{"no output", `true`, "", ErrNotFound},
+		{"blank first line", `printf '\nsecond\n'`, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCommandStore(tt.command).Get(ctx, "gemini_api_key")
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Get = %q, %v; want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := NewCommandStore(`echo "vault is locked" >&2; exit 3`).Get(ctx, "gemini_api_key")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "vault is locked") {
+		t.Errorf("Get with a failing helper: err = %v, want the helper's error", err)
+	}
+}
+
+func TestCommandStoreIsReadOnly(t *testing.T) {
+	store := NewCommandStore("pass show smartgit/{name}")
+	ctx := context.Background()
+	if store.Name() != BackendCommand {
+		t.Errorf("Name() = %q, want %q", store.Name(), BackendCommand)
+	}
+	if err := store.Set(ctx, "gemini_api_key", "x"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Set: err = %v, want ErrReadOnly", err)
+	}
+	if err := store.Delete(ctx, "gemini_api_key"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete: err = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,175 @@
+package secret
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	fileFormatVersion = 1
+	kdfIterations     = 210000
+	keyLength         = 32
+	saltLength        = 16
+)
+
+// FileStore keeps secrets in a single AES-256-GCM encrypted JSON file.
+// The key is derived from a passphrase with PBKDF2-HMAC-SHA256.
+type FileStore struct {
+	path       string
+	passphrase func() (string, error)
+}
+
+// encryptedFile is the on-disk envelope of a FileStore.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// NewFileStore creates an encrypted file store at path.
+func NewFileStore(path string, passphrase func() (string, error)) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase}
+}
+
+// Name implements Store.
+func (s *FileStore) Name() string {
+	return BackendFile
+}
+
+// Get implements Store.
+func (s *FileStore) Get(ctx context.Context, name string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set implements Store.
+func (s *FileStore) Set(ctx context.Context, name, value string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return s.save(secrets)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(ctx context.Context, name string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return nil
+	}
+	delete(secrets, name)
+	return s.save(secrets)
+}
+
+func (s *FileStore) load() (map[string]string, error) {
+	secrets := map[string]string{}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope encryptedFile
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted secrets file %s: %w", s.path, err)
+	}
+	if envelope.Version != fileFormatVersion {
+		return nil, fmt.Errorf("unsupported encrypted secrets file version %d", envelope.Version)
+	}
+
+	gcm, err := s.cipher(envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, envelope.Nonce, envelope.Data, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt secrets file (wrong passphrase?)")
+	}
+
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+func (s *FileStore) save(secrets map[string]string) error {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	envelope := encryptedFile{
+		Version:    fileFormatVersion,
+		Iterations: kdfIterations,
+		Salt:       make([]byte, saltLength),
+	}
+	if _, err := rand.Read(envelope.Salt); err != nil {
+		return err
+	}
+
+	gcm, err := s.cipher(envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return err
+	}
+	envelope.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return err
+	}
+	envelope.Data = gcm.Seal(nil, envelope.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *FileStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	block, err := aes.NewCipher(deriveKey(passphrase, salt, iterations))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives the AES-256 key from passphrase with PBKDF2-HMAC-SHA256.
+func deriveKey(passphrase string, salt []byte, iterations int) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, iterations, keyLength, sha256.New)
+}
@@ -0,0 +1,183 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RFC 7914 section 11 PBKDF2-HMAC-SHA256 vectors, truncated to the AES-256
+// key length; PBKDF2 output prefixes do not depend on the requested length.
+func TestDeriveKeyRFC7914(t *testing.T) {
+	tests := []struct {
+		passphrase string
+		salt       string
+		iterations int
+		want       string
+	}{
+		{
+			passphrase: "passwd",
+			salt:       "salt",
+			iterations: 1,
+			want:       "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			passphrase: "Password",
+			salt:       "NaCl",
+			iterations: 80000,
+			want:       "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(deriveKey(tt.passphrase, []byte(tt.salt), tt.iterations))
+		if got != tt.want[:keyLength*2] {
+			t.Errorf("deriveKey(%q, %q, %d) = %s, want %s", tt.passphrase, tt.salt, tt.iterations, got, tt.want[:keyLength*2])
+		}
+	}
+}
+
+func passphrase(p string) func() (string, error) {
+	return func() (string, error) { return p, nil }
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+
+	store := NewFileStore(path, passphrase("correct horse"))
+	if _, err := store.Get(ctx, "gemini_api_key"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on missing file: err = %v, want ErrNotFound", err)
+	}
+	if err := store.Set(ctx, "gemini_api_key", "AIza-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(ctx, "profile_work_api_key", "sk-work"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("AIza-secret")) || bytes.Contains(data, []byte("sk-work")) {
+		t.Fatal("secrets file contains plaintext values")
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o600 {
+		t.Errorf("secrets file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	reopened := NewFileStore(path, passphrase("correct horse"))
+	for name, want := range map[string]string{"gemini_api_key": "AIza-secret", "profile_work_api_key": "sk-work"} {
+		got, err := reopened.Get(ctx, name)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", name, err)
+		}
+		if got != want {
+			t.Errorf("Get(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	if err := reopened.Delete(ctx, "gemini_api_key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Get(ctx, "gemini_api_key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if got, err := reopened.Get(ctx, "profile_work_api_key"); err != nil || got != "sk-work" {
+		t.Errorf("Get(profile_work_api_key) after Delete = %q, %v", got, err)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+
+	if err := NewFileStore(path, passphrase("right")).Set(ctx, "key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewFileStore(path, passphrase("wrong")).Get(ctx, "key")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Fatalf("Get with wrong passphrase: err = %v", err)
+	}
+	// A failed decryption must not let Set overwrite the existing secrets.
+	if err := NewFileStore(path, passphrase("wrong")).Set(ctx, "other", "x"); err == nil {
+		t.Fatal("Set with wrong passphrase succeeded")
+	}
+	if got, err := NewFileStore(path, passphrase("right")).Get(ctx, "key"); err != nil || got != "value" {
+		t.Fatalf("Get after rejected Set = %q, %v", got, err)
+	}
+}
+
+func TestFileStoreEmptyPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	if err := NewFileStore(path, passphrase("")).Set(context.Background(), "key", "value"); err == nil {
+		t.Fatal("Set with empty passphrase succeeded")
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("secrets file written with empty passphrase: %v", err)
+	}
+}
+
+func TestFileStoreTampered(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		tamper func(*encryptedFile)
+	}{
+		{"ciphertext", func(e *encryptedFile) { e.Data[0] ^= 0x01 }},
+		{"truncated ciphertext", func(e *encryptedFile) { e.Data = e.Data[:len(e.Data)-1] }},
+		{"nonce", func(e *encryptedFile) { e.Nonce[0] ^= 0x01 }},
+		{"salt", func(e *encryptedFile) { e.Salt[0] ^= 0x01 }},
+		{"iterations", func(e *encryptedFile) { e.Iterations-- }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "secrets.enc")
+			store := NewFileStore(path, passphrase("pass"))
+			if err := store.Set(ctx, "key", "value"); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var envelope encryptedFile
+			if err := json.Unmarshal(data, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(&envelope)
+			data, err = json.Marshal(envelope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := store.Get(ctx, "key"); err == nil {
+				t.Fatalf("Get on tampered file = %q, want error", got)
+			}
+		})
+	}
+}
+
+func TestFileStoreUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewFileStore(path, passphrase("pass")).Get(context.Background(), "key")
+	if err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Fatalf("Get on unknown version: err = %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// KeyringStore talks to the freedesktop Secret Service (GNOME Keyring,
+// KWallet, KeePassXC, ...) through the libsecret secret-tool binary.
+// The binary can be overridden with SMARTGIT_SECRET_TOOL, which also lets
+// a file-backed stand-in replace the real service on headless machines.
+type KeyringStore struct {
+	tool string
+}
+
+// NewKeyringStore creates a Secret Service backed store.
+func NewKeyringStore() *KeyringStore {
+	tool := strings.TrimSpace(os.Getenv("SMARTGIT_SECRET_TOOL"))
+	if tool == "" {
+		tool = "secret-tool"
+	}
+	return &KeyringStore{tool: tool}
+}
+
+// Name implements Store.
+func (s *KeyringStore) Name() string {
+	return BackendKeyring
+}
+
+// Get implements Store.
+func (s *KeyringStore) Get(ctx context.Context, name string) (string, error) {
+	out, errOut, err := s.run(ctx, "", "lookup", "service", ServiceName, "account", name)
+	if err != nil {
+		// secret-tool exits with status 1 and no output when nothing matches;
+		// anything on stderr means the service itself failed.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.TrimSpace(out) == "" && errOut == "" {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	value := strings.TrimRight(out, "\r\n")
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set implements Store.
+func (s *KeyringStore) Set(ctx context.Context, name, value string) error {
+	label := fmt.Sprintf("SmartGit %s", name)
+	_, _, err := s.run(ctx, value, "store", "--label", label, "service", ServiceName, "account", name)
+	return err
+}
+
+// Delete implements Store.
+func (s *KeyringStore) Delete(ctx context.Context, name string) error {
+	_, _, err := s.run(ctx, "", "clear", "service", ServiceName, "account", name)
+	return err
+}
+
+// run returns the tool's stdout and trimmed stderr.
+func (s *KeyringStore) run(ctx context.Context, stdin string, args ...string) (string, string, error) {
+	if _, err := exec.LookPath(s.tool); err != nil {
+		return "", "", fmt.Errorf("secret service tool %q not found (install libsecret-tools): %w", s.tool, err)
+	}
+
+	cmd := exec.CommandContext(ctx, s.tool, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	err := cmd.Run()
+	msg := strings.TrimSpace(stderr.String())
+	if err != nil {
+		if msg != "" {
+			return stdout.String(), msg, fmt.Errorf("%s %s failed: %w: %s", s.tool, args[0], err, msg)
+		}
+		return stdout.String(), "", fmt.Errorf("%s %s failed: %w", s.tool, args[0], err)
+	}
+	return stdout.String(), msg, nil
+}
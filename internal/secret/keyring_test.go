@@ -0,0 +1,124 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSecretTool mimics secret-tool's lookup, store and clear commands,
+// keeping one file per account in FAKE_KEYRING_DIR. It records its
+// arguments so tests can check the attributes sg passes.
+const fakeSecretTool = `#!/bin/sh
+dir="$FAKE_KEYRING_DIR"
+echo "$@" >> "$dir/.calls"
+cmd=$1
+shift
+while [ $# -gt 0 ]; do
+	case "$1" in
+	account) account=$2; shift 2 ;;
+	*) shift ;;
+	esac
+done
+case "$account" in
+broken) echo "Cannot autolaunch D-Bus without X11 \$DISPLAY" >&2; exit 1 ;;
+esac
+case "$cmd" in
+lookup) [ -f "$dir/$account" ] || exit 1; cat "$dir/$account" ;;
+store) cat > "$dir/$account" ;;
+clear) rm -f "$dir/$account" ;;
+*) exit 2 ;;
+esac
+`
+
+func newFakeKeyring(t *testing.T) (*KeyringStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "secret-tool")
+	if err := os.WriteFile(tool, []byte(fakeSecretTool), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SMARTGIT_SECRET_TOOL", tool)
+	t.Setenv("FAKE_KEYRING_DIR", dir)
+	return NewKeyringStore(), dir
+}
+
+func TestKeyringStore(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	store, dir := newFakeKeyring(t)
+	ctx := context.Background()
+
+	if store.Name() != BackendKeyring {
+		t.Errorf("Name() = %q, want %q", store.Name(), BackendKeyring)
+	}
+	if _, err := store.Get(ctx, "gemini_api_key"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing secret: err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Set(ctx, "gemini_api_key", "AIza-secret"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, "gemini_api_key")
+	if err != nil || got != "AIza-secret" {
+		t.Fatalf("Get = %q, %v; want the stored secret", got, err)
+	}
+
+	// A trailing newline from the tool is not part of the secret.
+	if err := os.WriteFile(filepath.Join(dir, "forge_token"), []byte("tok\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get(ctx, "forge_token"); err != nil || got != "tok" {
+		t.Errorf("Get = %q, %v; want %q", got, err, "tok")
+	}
+	// An empty entry counts as missing.
+	if err := os.WriteFile(filepath.Join(dir, "empty"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "empty"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of an empty secret: err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Delete(ctx, "gemini_api_key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "gemini_api_key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+
+	calls, err := os.ReadFile(filepath.Join(dir, ".calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "store --label SmartGit gemini_api_key service " + ServiceName + " account gemini_api_key"
+	if !strings.Contains(string(calls), want) {
+		t.Errorf("tool calls:\n%s\nwant a call %q", calls, want)
+	}
+}
+
+func TestKeyringStoreErrors(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	store, _ := newFakeKeyring(t)
+	ctx := context.Background()
+
+	// A failing service is reported, not mistaken for a missing secret.
+	_, err := store.Get(ctx, "broken")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "D-Bus") {
+		t.Errorf("Get with a failing service: err = %v, want the tool's error", err)
+	}
+	if err := store.Set(ctx, "broken", "x"); err == nil {
+		t.Error("Set with a failing service succeeded")
+	}
+
+	t.Setenv("SMARTGIT_SECRET_TOOL", filepath.Join(t.TempDir(), "missing-tool"))
+	_, err = NewKeyringStore().Get(ctx, "gemini_api_key")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Get without the tool: err = %v, want a missing tool error", err)
+	}
+}
@@ -0,0 +1,78 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Backend names accepted by New.
+const (
+	BackendPlain   = ""
+	BackendKeyring = "keyring"
+	BackendFile    = "file"
+	BackendCommand = "command"
+)
+
+// ServiceName is the attribute used to group SmartGit secrets in external stores.
+const ServiceName = "smartgit"
+
+var (
+	// ErrNotFound indicates the requested secret does not exist in the store.
+	ErrNotFound = errors.New("secret not found")
+	// ErrReadOnly indicates the store cannot persist new secrets.
+	ErrReadOnly = errors.New("secret store is read-only")
+)
+
+// Store persists named secrets such as API keys outside of config.json.
+type Store interface {
+	// Name returns a short, human-readable backend name.
+	Name() string
+	// Get returns the secret stored under name, or ErrNotFound.
+	Get(ctx context.Context, name string) (string, error)
+	// Set stores value under name, replacing any existing value.
+	Set(ctx context.Context, name, value string) error
+	// Delete removes the secret stored under name. Missing secrets are not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// Options configures the store returned by New.
+type Options struct {
+	// Backend selects the implementation (keyring, file or command).
+	Backend string
+	// Command is the shell command used by the command backend. The
+	// placeholder {name} is replaced with the secret name, e.g.
+	// "pass show smartgit/{name}" or "op read op://dev/smartgit/{name}".
+	Command string
+	// FilePath is the location of the encrypted file used by the file backend.
+	FilePath string
+	// Passphrase returns the passphrase protecting the encrypted file.
+	Passphrase func() (string, error)
+}
+
+// New returns the store selected by opts.Backend. It returns a nil store
+// for BackendPlain, meaning secrets stay in config.json.
+func New(opts Options) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.Backend)) {
+	case BackendPlain:
+		return nil, nil
+	case BackendKeyring:
+		return NewKeyringStore(), nil
+	case BackendFile:
+		if strings.TrimSpace(opts.FilePath) == "" {
+			return nil, errors.New("encrypted file store requires a file path")
+		}
+		if opts.Passphrase == nil {
+			return nil, errors.New("encrypted file store requires a passphrase source")
+		}
+		return NewFileStore(opts.FilePath, opts.Passphrase), nil
+	case BackendCommand:
+		if strings.TrimSpace(opts.Command) == "" {
+			return nil, errors.New("command store requires secret_command to be configured")
+		}
+		return NewCommandStore(opts.Command), nil
+	default:
+		return nil, fmt.Errorf("unknown secret store %q (expected keyring, file or command)", opts.Backend)
+	}
+}
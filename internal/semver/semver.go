@@ -0,0 +1,199 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, e.g. "v1.2.3-rc.1".
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+	// Prefix is kept from the parsed string ("v" or "").
+	Prefix string
+}
+
+// Parse reads "1.2.3" or "v1.2.3", with optional "-pre" and "+build" parts.
+func Parse(s string) (Version, error) {
+	var v Version
+	raw := strings.TrimSpace(s)
+	if strings.HasPrefix(raw, "v") || strings.HasPrefix(raw, "V") {
+		v.Prefix = raw[:1]
+		raw = raw[1:]
+	}
+	if i := strings.Index(raw, "+"); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.Index(raw, "-"); i >= 0 {
+		v.Pre = raw[i+1:]
+		raw = raw[:i]
+		if v.Pre == "" {
+			return Version{}, fmt.Errorf("invalid semantic version %q", s)
+		}
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		// Atoi alone would accept signs such as "+1".
+		if !isNumeric(p) {
+			return Version{}, fmt.Errorf("invalid semantic version %q", s)
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid semantic version %q", s)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// String formats v with its prefix.
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Core formats v without prefix or pre-release, e.g. "1.2.3".
+func (v Version) Core() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 as a is lower than, equal to or higher than b.
+// A pre-release sorts before its release, and pre-releases are compared
+// field by field as the spec requires, so "rc.2" sorts before "rc.10".
+func Compare(a, b Version) int {
+	for _, d := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case a.Pre == b.Pre:
+		return 0
+	case a.Pre == "":
+		return 1
+	case b.Pre == "":
+		return -1
+	}
+	return comparePre(a.Pre, b.Pre)
+}
+
+// comparePre orders dot-separated pre-release identifiers: numeric ones
+// numerically and below alphanumeric ones, and a shorter list first when
+// it is a prefix of the longer one.
+func comparePre(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		if x == y {
+			continue
+		}
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		switch {
+		case xNum && yNum:
+			// Compare by length first so long numbers cannot overflow.
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if len(x) != len(y) {
+				return cmpInt(len(x), len(y))
+			}
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+			continue
+		case xNum:
+			return -1
+		case yNum:
+			return 1
+		}
+		return strings.Compare(x, y)
+	}
+	return cmpInt(len(as), len(bs))
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Bump is the kind of version increment.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// ParseBump reads "major", "minor" or "patch".
+func ParseBump(s string) (Bump, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "major":
+		return BumpMajor, nil
+	case "minor":
+		return BumpMinor, nil
+	case "patch":
+		return BumpPatch, nil
+	}
+	return BumpNone, fmt.Errorf("invalid bump %q (expected major, minor or patch)", s)
+}
+
+func (b Bump) String() string {
+	switch b {
+	case BumpMajor:
+		return "major"
+	case BumpMinor:
+		return "minor"
+	case BumpPatch:
+		return "patch"
+	}
+	return "none"
+}
+
+// Next returns v incremented by b, dropping any pre-release.
+func (v Version) Next(b Bump) Version {
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prefix: v.Prefix}
+	switch b {
+	case BumpMajor:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case BumpMinor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	case BumpPatch:
+		// Releasing 1.2.3-rc.1 produces 1.2.3.
+		if v.Pre == "" {
+			next.Patch = v.Patch + 1
+		}
+	default:
+		return v
+	}
+	return next
+}
@@ -0,0 +1,123 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+		str  string
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, "1.2.3"},
+		{" v0.10.0 ", Version{Minor: 10, Prefix: "v"}, "v0.10.0"},
+		{"V2.0.0", Version{Major: 2, Prefix: "V"}, "V2.0.0"},
+		{"v1.2.3-rc.1", Version{Major: 1, Minor: 2, Patch: 3, Pre: "rc.1", Prefix: "v"}, "v1.2.3-rc.1"},
+		{"1.2.3-beta-2", Version{Major: 1, Minor: 2, Patch: 3, Pre: "beta-2"}, "1.2.3-beta-2"},
+		{"1.2.3+build.5", Version{Major: 1, Minor: 2, Patch: 3}, "1.2.3"},
+		{"1.2.3-rc.1+build", Version{Major: 1, Minor: 2, Patch: 3, Pre: "rc.1"}, "1.2.3-rc.1"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got.String(), tt.str)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"", "v", "1", "1.2", "1.2.3.4", "1.a.3", "1..3", "+1.2.3", "v1.-2.3", "1.2.3-", "Unreleased", "v 1.2.3"} {
+		if v, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", in, v)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.3.0", "1.2.9", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.10", "1.0.0-rc.9", 1},
+		{"1.0.0-rc.01.a", "1.0.0-rc.1.b", -1},
+		{"1.0.0-rc.1+x", "1.0.0-rc.1+y", 0},
+	}
+	for _, tt := range tests {
+		a, err := Parse(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := Parse(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Compare(a, b); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(b, a); got != -tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		in   string
+		bump Bump
+		want string
+	}{
+		{"v1.2.3", BumpPatch, "v1.2.4"},
+		{"v1.2.3", BumpMinor, "v1.3.0"},
+		{"v1.2.3", BumpMajor, "v2.0.0"},
+		{"1.2.3", BumpNone, "1.2.3"},
+		{"1.2.3-rc.1", BumpPatch, "1.2.3"},
+		{"1.2.3-rc.1", BumpMinor, "1.3.0"},
+		{"0.9.9-beta", BumpMajor, "1.0.0"},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.Next(tt.bump).String(); got != tt.want {
+			t.Errorf("Parse(%q).Next(%s) = %q, want %q", tt.in, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestParseBump(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Bump
+		wantErr bool
+	}{
+		{"major", BumpMajor, false},
+		{" Minor ", BumpMinor, false},
+		{"PATCH", BumpPatch, false},
+		{"", BumpNone, true},
+		{"micro", BumpNone, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBump(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseBump(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package undo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+// pointsFile lives in the git directory so undo points stay with the
+// repository (and its worktrees) without touching the working tree.
+const pointsFile = "smartgit/undo.jsonl"
+
+// Point records where HEAD was right before an sg command changed it.
+type Point struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Branch  string    `json:"branch"`
+	Head    string    `json:"head"`
+}
+
+// Record appends an undo point for the current HEAD. Repositories without
+// commits have nothing to go back to and are skipped.
+func Record(ctx context.Context, dir, command string) error {
+	head, err := git.RevParse(ctx, dir, "HEAD")
+	if err != nil {
+		return nil
+	}
+	branch, err := git.CurrentBranch(ctx, dir)
+	if err != nil {
+		return err
+	}
+	path, err := git.GitPath(ctx, dir, pointsFile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(Point{Time: time.Now().UTC(), Command: command, Branch: branch, Head: head})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Points returns the recorded undo points, oldest first.
+func Points(ctx context.Context, dir string) ([]Point, error) {
+	path, err := git.GitPath(ctx, dir, pointsFile)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var points []Point
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p Point
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			continue
+		}
+		points = append(points, p)
+	}
+	return points, scanner.Err()
+}
+
+// Kind classifies a reflog operation.
+type Kind string
+
+const (
+	KindCommit   Kind = "commit"
+	KindCheckout Kind = "checkout"
+	KindRebase   Kind = "rebase"
+	KindReset    Kind = "reset"
+	KindMerge    Kind = "merge"
+	KindPull     Kind = "pull"
+	KindOther    Kind = "other"
+)
+
+// Operation is one user-level action, which may span several reflog
+// entries (a rebase writes one per replayed commit).
+type Operation struct {
+	Kind        Kind
+	Description string
+	Time        time.Time
+	// Before is the commit HEAD pointed at before the operation.
+	Before string
+	// After is the commit HEAD pointed at after the operation.
+	After string
+	// From and To are the branches of a checkout.
+	From string
+	To   string
+	// By names the sg command that recorded an undo point right before
+	// this operation, if any.
+	By string
+}
+
+// Operations groups reflog entries (newest first) into operations. The
+// oldest entry only provides the "before" commit of the one after it, so
+// at most len(entries)-1 operations are returned. A rebase whose "(start)"
+// entry is outside entries ends the list rather than being guessed at.
+func Operations(entries []git.ReflogEntry, points []Point) []Operation {
+	var ops []Operation
+	for i := 0; i+1 < len(entries); i++ {
+		e := entries[i]
+		op := Operation{Time: e.Time, After: e.Hash}
+
+		action, detail, _ := strings.Cut(e.Subject, ": ")
+		switch {
+		case isRebaseEnd(action):
+			// Walk back to the matching "(start)" entry, which needs an
+			// entry of its own before it for the "before" commit.
+			j := i
+			for j < len(entries)-1 && !strings.HasSuffix(reflogAction(entries[j]), "(start)") {
+				j++
+			}
+			if j == len(entries)-1 {
+				// The rebase began before the window: where it started is
+				// unknown, and so is everything older.
+				return ops
+			}
+			_, onto, _ := strings.Cut(entries[j].Subject, ": ")
+			onto = strings.TrimPrefix(onto, "checkout ")
+			op.Kind = KindRebase
+			op.Description = fmt.Sprintf("rebased %s onto %s", strings.TrimPrefix(detail, "returning to refs/heads/"), onto)
+			if strings.HasSuffix(action, "(abort)") {
+				op.Description = fmt.Sprintf("started and aborted a rebase onto %s (no change)", onto)
+			}
+			if strings.HasPrefix(action, "pull") {
+				op.Description = "pulled with rebase: " + op.Description
+			}
+			i = j
+		case strings.HasPrefix(action, "commit"):
+			op.Kind = KindCommit
+			switch {
+			case strings.Contains(action, "(amend)"):
+				op.Description = fmt.Sprintf("amended the last commit (now %q)", detail)
+			case strings.Contains(action, "(initial)"):
+				op.Description = fmt.Sprintf("created the first commit %q", detail)
+			case strings.Contains(action, "(merge)"):
+				op.Kind = KindMerge
+				op.Description = fmt.Sprintf("committed a merge %q", detail)
+			default:
+				op.Description = fmt.Sprintf("committed %q", detail)
+			}
+		case action == "checkout":
+			op.Kind = KindCheckout
+			from, to, ok := strings.Cut(strings.TrimPrefix(detail, "moving from "), " to ")
+			if ok {
+				op.From, op.To = from, to
+				op.Description = fmt.Sprintf("switched from %s to %s", from, to)
+			} else {
+				op.Description = "checked out " + detail
+			}
+		case action == "reset":
+			op.Kind = KindReset
+			op.Description = "reset " + strings.Replace(detail, "moving to ", "the branch to ", 1)
+		case strings.HasPrefix(action, "merge "):
+			op.Kind = KindMerge
+			op.Description = fmt.Sprintf("merged %s (%s)", strings.TrimPrefix(action, "merge "), strings.TrimSuffix(detail, "."))
+		case strings.HasPrefix(action, "pull"):
+			op.Kind = KindPull
+			op.Description = "pulled (" + strings.TrimSuffix(detail, ".") + ")"
+		case strings.HasPrefix(action, "cherry-pick"):
+			op.Kind = KindCommit
+			op.Description = fmt.Sprintf("cherry-picked %q", detail)
+		default:
+			op.Kind = KindOther
+			op.Description = e.Subject
+		}
+
+		op.Before = entries[i+1].Hash
+		op.By = recordedBy(points, op.Before, op.Time)
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func reflogAction(e git.ReflogEntry) string {
+	action, _, _ := strings.Cut(e.Subject, ": ")
+	return action
+}
+
+func isRebaseEnd(action string) bool {
+	return (strings.HasPrefix(action, "rebase") || strings.HasPrefix(action, "pull --rebase")) &&
+		(strings.HasSuffix(action, "(finish)") || strings.HasSuffix(action, "(abort)"))
+}
+
+// recordedBy finds the latest undo point taken at head shortly before t.
+func recordedBy(points []Point, head string, t time.Time) string {
+	for i := len(points) - 1; i >= 0; i-- {
+		p := points[i]
+		// Reflog times have one-second resolution.
+		if p.Head != head || p.Time.After(t.Add(time.Second)) {
+			continue
+		}
+		if t.Sub(p.Time) > 10*time.Minute {
+			return ""
+		}
+		return p.Command
+	}
+	return ""
+}
+
+// Step is one git command of a restore plan.
+type Step struct {
+	Args   []string
+	Reason string
+}
+
+// Plan returns the least destructive git commands that undo ops[0..n-1]
+// (newest first), given the current branch. Runs of commits are undone
+// with "reset --soft" so their changes come back staged; rebases, resets
+// and merges with "reset --keep", which refuses to overwrite local
+// changes; checkouts by switching back.
+func Plan(ops []Operation, n int, branch string) []Step {
+	var steps []Step
+	for i := 0; i < n && i < len(ops); {
+		op := ops[i]
+		if op.Kind == KindCheckout {
+			if op.From != "" && op.From != op.To {
+				steps = append(steps, Step{Args: []string{"checkout", op.From}, Reason: "switch back to " + op.From})
+				branch = op.From
+			}
+			i++
+			continue
+		}
+
+		j, soft, rebase := i, true, false
+		for ; j < n && j < len(ops) && ops[j].Kind != KindCheckout; j++ {
+			soft = soft && ops[j].Kind == KindCommit
+			rebase = rebase || ops[j].Kind == KindRebase
+		}
+		target := ops[j-1].Before
+		if target == ops[i].After {
+			i = j
+			continue
+		}
+		short := target
+		if len(short) > 7 {
+			short = short[:7]
+		}
+		switch {
+		case soft:
+			steps = append(steps, Step{
+				Args:   []string{"reset", "--soft", target},
+				Reason: fmt.Sprintf("move %s back to %s and keep the undone commits' changes staged", branch, short),
+			})
+		case rebase && i == 0 && j == 1:
+			steps = append(steps, Step{
+				Args:   []string{"reset", "--keep", target},
+				Reason: fmt.Sprintf("put %s back where it was before the rebase (ORIG_HEAD, %s)", branch, short),
+			})
+		default:
+			steps = append(steps, Step{
+				Args:   []string{"reset", "--keep", target},
+				Reason: fmt.Sprintf("move %s back to %s, keeping uncommitted changes", branch, short),
+			})
+		}
+		i = j
+	}
+	return steps
+}
@@ -0,0 +1,240 @@
+package undo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vinhtran/git-smart/internal/git"
+)
+
+var t0 = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// reflog builds entries newest first from subjects, giving entry i the hash
+// "h<n-i>" so older entries have smaller numbers, one minute apart.
+func reflog(subjects ...string) []git.ReflogEntry {
+	entries := make([]git.ReflogEntry, len(subjects))
+	for i, s := range subjects {
+		n := len(subjects) - i
+		entries[i] = git.ReflogEntry{Hash: "h" + string(rune('0'+n)), Time: t0.Add(time.Duration(n) * time.Minute), Subject: s}
+	}
+	return entries
+}
+
+func TestOperations(t *testing.T) {
+	type op struct {
+		kind          Kind
+		desc          string
+		before, after string
+	}
+	tests := []struct {
+		name    string
+		entries []git.ReflogEntry
+		want    []op
+	}{
+		{
+			name: "rebase, checkout and commits",
+			entries: reflog(
+				"rebase (finish): returning to refs/heads/feat",
+				"rebase (pick): add y",
+				"rebase (start): checkout main",
+				"checkout: moving from main to feat",
+				"commit: add x",
+				"commit (initial): init",
+			),
+			want: []op{
+				{KindRebase, "rebased feat onto main", "h3", "h6"},
+				{KindCheckout, "switched from main to feat", "h2", "h3"},
+				{KindCommit, `committed "add x"`, "h1", "h2"},
+			},
+		},
+		{
+			name: "aborted rebase",
+			entries: reflog(
+				"rebase (abort): returning to refs/heads/feat",
+				"rebase (start): checkout main",
+				"commit: add x",
+			),
+			want: []op{{KindRebase, "started and aborted a rebase onto main (no change)", "h1", "h3"}},
+		},
+		{
+			name: "pull with rebase",
+			entries: reflog(
+				"pull --rebase (finish): returning to refs/heads/main",
+				"pull --rebase (pick): local work",
+				"pull --rebase (start): checkout 1a2b3c4",
+				"commit: local work",
+			),
+			want: []op{{KindRebase, "pulled with rebase: rebased main onto 1a2b3c4", "h1", "h4"}},
+		},
+		{
+			name: "rebase started outside the window",
+			entries: reflog(
+				"commit: newer",
+				"rebase (finish): returning to refs/heads/feat",
+				"rebase (pick): add y",
+				"rebase (pick): add x",
+			),
+			want: []op{{KindCommit, `committed "newer"`, "h3", "h4"}},
+		},
+		{
+			name: "rebase start is the oldest entry",
+			entries: reflog(
+				"rebase (finish): returning to refs/heads/feat",
+				"rebase (pick): add y",
+				"rebase (start): checkout main",
+			),
+			want: nil,
+		},
+		{
+			name: "other kinds",
+			entries: reflog(
+				"commit (amend): better message",
+				"commit (merge): Merge branch 'x'",
+				"reset: moving to HEAD~1",
+				"merge feature: Fast-forward",
+				"pull: Fast-forward",
+				"cherry-pick: fix typo",
+				"checkout: moving from main to 1a2b3c4",
+				"branch: Created from HEAD",
+				"commit: base",
+			),
+			want: []op{
+				{KindCommit, `amended the last commit (now "better message")`, "h8", "h9"},
+				{KindMerge, `committed a merge "Merge branch 'x'"`, "h7", "h8"},
+				{KindReset, "reset the branch to HEAD~1", "h6", "h7"},
+				{KindMerge, "merged feature (Fast-forward)", "h5", "h6"},
+				{KindPull, "pulled (Fast-forward)", "h4", "h5"},
+				{KindCommit, `cherry-picked "fix typo"`, "h3", "h4"},
+				{KindCheckout, "switched from main to 1a2b3c4", "h2", "h3"},
+				{KindOther, "branch: Created from HEAD", "h1", "h2"},
+			},
+		},
+		{
+			name:    "single entry",
+			entries: reflog("commit (initial): init"),
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := Operations(tt.entries, nil)
+			var got []op
+			for _, o := range ops {
+				got = append(got, op{o.Kind, o.Description, o.Before, o.After})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Operations() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperationsRecordedBy(t *testing.T) {
+	entries := reflog("commit: b", "commit: a", "commit (initial): init")
+	points := []Point{
+		{Time: t0.Add(2*time.Minute - 5*time.Second), Command: "sg cm", Head: "h2"},
+		// Too old to belong to the commit of h2.
+		{Time: t0.Add(-time.Hour), Command: "sg sw", Head: "h1"},
+	}
+	ops := Operations(entries, points)
+	if len(ops) != 2 {
+		t.Fatalf("Operations returned %d operations, want 2", len(ops))
+	}
+	if ops[0].By != "sg cm" {
+		t.Errorf("ops[0].By = %q, want sg cm", ops[0].By)
+	}
+	if ops[1].By != "" {
+		t.Errorf("ops[1].By = %q, want none", ops[1].By)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	commit := func(before, after string) Operation {
+		return Operation{Kind: KindCommit, Before: before, After: after}
+	}
+	tests := []struct {
+		name   string
+		ops    []Operation
+		n      int
+		branch string
+		want   []Step
+	}{
+		{
+			name:   "commits are undone softly",
+			ops:    []Operation{commit("bbbbbbbbbb", "cccccccccc"), commit("aaaaaaaaaa", "bbbbbbbbbb")},
+			n:      2,
+			branch: "feat",
+			want: []Step{{
+				Args:   []string{"reset", "--soft", "aaaaaaaaaa"},
+				Reason: "move feat back to aaaaaaa and keep the undone commits' changes staged",
+			}},
+		},
+		{
+			name:   "n limits the operations",
+			ops:    []Operation{commit("bbbbbbbbbb", "cccccccccc"), commit("aaaaaaaaaa", "bbbbbbbbbb")},
+			n:      1,
+			branch: "feat",
+			want: []Step{{
+				Args:   []string{"reset", "--soft", "bbbbbbbbbb"},
+				Reason: "move feat back to bbbbbbb and keep the undone commits' changes staged",
+			}},
+		},
+		{
+			name:   "rebase",
+			ops:    []Operation{{Kind: KindRebase, Before: "aaaaaaaaaa", After: "bbbbbbbbbb"}},
+			n:      1,
+			branch: "feat",
+			want: []Step{{
+				Args:   []string{"reset", "--keep", "aaaaaaaaaa"},
+				Reason: "put feat back where it was before the rebase (ORIG_HEAD, aaaaaaa)",
+			}},
+		},
+		{
+			name:   "commit then reset",
+			ops:    []Operation{commit("bbbbbbbbbb", "cccccccccc"), {Kind: KindReset, Before: "aaaaaaaaaa", After: "bbbbbbbbbb"}},
+			n:      2,
+			branch: "main",
+			want: []Step{{
+				Args:   []string{"reset", "--keep", "aaaaaaaaaa"},
+				Reason: "move main back to aaaaaaa, keeping uncommitted changes",
+			}},
+		},
+		{
+			name: "across a checkout",
+			ops: []Operation{
+				commit("bbbbbbbbbb", "cccccccccc"),
+				{Kind: KindCheckout, From: "main", To: "feat", Before: "aaaaaaaaaa", After: "bbbbbbbbbb"},
+				commit("9999999999", "aaaaaaaaaa"),
+			},
+			n:      3,
+			branch: "feat",
+			want: []Step{
+				{Args: []string{"reset", "--soft", "bbbbbbbbbb"}, Reason: "move feat back to bbbbbbb and keep the undone commits' changes staged"},
+				{Args: []string{"checkout", "main"}, Reason: "switch back to main"},
+				{Args: []string{"reset", "--soft", "9999999999"}, Reason: "move main back to 9999999 and keep the undone commits' changes staged"},
+			},
+		},
+		{
+			name:   "aborted rebase changes nothing",
+			ops:    []Operation{{Kind: KindRebase, Before: "aaaaaaaaaa", After: "aaaaaaaaaa"}},
+			n:      1,
+			branch: "feat",
+			want:   nil,
+		},
+		{
+			name:   "checkout of the same branch",
+			ops:    []Operation{{Kind: KindCheckout, From: "main", To: "main", Before: "aaaaaaaaaa", After: "aaaaaaaaaa"}},
+			n:      1,
+			branch: "main",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Plan(tt.ops, tt.n, tt.branch); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}